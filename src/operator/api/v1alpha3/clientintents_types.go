@@ -50,6 +50,7 @@ const (
 	AllIntentsRemovedAnnotation               = "intents.otterize.com/all-intents-removed"
 	OtterizeCreatedForServiceAnnotation       = "intents.otterize.com/created-for-service"
 	OtterizeCreatedForIngressAnnotation       = "intents.otterize.com/created-for-ingress"
+	OtterizeCreatedForGatewayRouteAnnotation  = "intents.otterize.com/created-for-gateway-route"
 	OtterizeSingleNetworkPolicyNameTemplate   = "%s-access"
 	OtterizeNetworkPolicy                     = "intents.otterize.com/network-policy"
 	OtterizeSvcNetworkPolicy                  = "intents.otterize.com/svc-network-policy"
@@ -58,17 +59,27 @@ const (
 	ClientIntentsFinalizerName                = "intents.otterize.com/client-intents-finalizer"
 	ProtectedServicesFinalizerName            = "intents.otterize.com/protected-services-finalizer"
 	OtterizeIstioClientAnnotationKey          = "intents.otterize.com/istio-client"
+	OtterizeIstioEgressGatewayDomainLabelKey  = "intents.otterize.com/istio-egress-gateway-domain"
+	OtterizeLinkerdClientLabelKey             = "intents.otterize.com/linkerd-client"
+	OtterizeLinkerdServerLabelKey             = "intents.otterize.com/linkerd-server"
+	OtterizeKafkaServerConfigLabelKey         = "intents.otterize.com/kafka-server-config"
+	OtterizeIstioPeerAuthenticationLabelKey   = "intents.otterize.com/istio-peer-authentication"
 	OtterizeClientServiceAccountAnnotation    = "intents.otterize.com/client-intents-service-account"
 	OtterizeSharedServiceAccountAnnotation    = "intents.otterize.com/shared-service-account"
 	OtterizeMissingSidecarAnnotation          = "intents.otterize.com/service-missing-sidecar"
 	OtterizeServersWithoutSidecarAnnotation   = "intents.otterize.com/servers-without-sidecar"
 	OtterizeTargetServerIndexField            = "spec.service.calls.server"
 	OtterizeKafkaServerConfigServiceNameField = "spec.service.name"
+	KafkaServerConfigSecretNamesIndexField    = "kafkaServerConfigSecretNames"
 	OtterizeProtectedServiceNameIndexField    = "spec.name"
 	OtterizeFormattedTargetServerIndexField   = "formattedTargetServer"
 	EndpointsPodNamesIndexField               = "endpointsPodNames"
 	IngressServiceNamesIndexField             = "ingressServiceNames"
+	HTTPRouteServiceNamesIndexField           = "httpRouteServiceNames"
+	GRPCRouteServiceNamesIndexField           = "grpcRouteServiceNames"
+	TLSRouteServiceNamesIndexField            = "tlsRouteServiceNames"
 	MaxOtterizeNameLength                     = 20
+	ExternalKafkaServerPrefix                 = "external:"
 	MaxNamespaceLength                        = 20
 	OtterizeSvcEgressNetworkPolicy            = "intents.otterize.com/svc-egress-network-policy"
 	OtterizeEgressNetworkPolicy               = "intents.otterize.com/egress-network-policy"
@@ -122,6 +133,15 @@ const (
 	HTTPMethodConnect HTTPMethod = "CONNECT"
 )
 
+// +kubebuilder:validation:Enum=second;minute;hour
+type RateLimitUnit string
+
+const (
+	RateLimitUnitSecond RateLimitUnit = "second"
+	RateLimitUnitMinute RateLimitUnit = "minute"
+	RateLimitUnitHour   RateLimitUnit = "hour"
+)
+
 // +kubebuilder:validation:Enum=ALL;SELECT;INSERT;UPDATE;DELETE
 type DatabaseOperation string
 
@@ -239,6 +259,18 @@ type Intent struct {
 	//+optional
 	Topics []KafkaTopic `json:"kafkaTopics,omitempty" yaml:"kafkaTopics,omitempty"`
 
+	//+optional
+	ConsumerGroups []KafkaConsumerGroup `json:"kafkaConsumerGroups,omitempty" yaml:"kafkaConsumerGroups,omitempty"`
+
+	//+optional
+	TransactionalIDs []KafkaTransactionalID `json:"kafkaTransactionalIds,omitempty" yaml:"kafkaTransactionalIds,omitempty"`
+
+	//+optional
+	ClusterOperations []KafkaOperation `json:"kafkaClusterOperations,omitempty" yaml:"kafkaClusterOperations,omitempty"`
+
+	//+optional
+	Quota *KafkaQuota `json:"kafkaQuota,omitempty" yaml:"kafkaQuota,omitempty"`
+
 	//+optional
 	HTTPResources []HTTPResource `json:"HTTPResources,omitempty" yaml:"HTTPResources,omitempty"`
 
@@ -248,6 +280,9 @@ type Intent struct {
 	//+optional
 	AWSActions []string `json:"awsActions,omitempty" yaml:"awsActions,omitempty"`
 
+	//+optional
+	AWSManagedPolicies []string `json:"awsManagedPolicies,omitempty" yaml:"awsManagedPolicies,omitempty"`
+
 	//+optional
 	GCPPermissions []string `json:"gcpPermissions,omitempty" yaml:"gcpPermissions,omitempty"`
 
@@ -259,6 +294,16 @@ type Intent struct {
 
 	//+optional
 	Internet *Internet `json:"internet,omitempty" yaml:"internet,omitempty"`
+
+	//+optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+}
+
+// RateLimit is the request budget of a client towards an HTTP server: Requests per Per, which defaults to a second.
+type RateLimit struct {
+	Requests int `json:"requests" yaml:"requests"`
+	//+optional
+	Per RateLimitUnit `json:"per,omitempty" yaml:"per,omitempty"`
 }
 
 type Internet struct {
@@ -279,8 +324,44 @@ type DatabaseResource struct {
 }
 
 type HTTPResource struct {
+	// Path is matched exactly, unless it starts or ends with '*' for suffix or prefix matching. A '*' path matches any path.
 	Path    string       `json:"path"`
 	Methods []HTTPMethod `json:"methods" yaml:"methods"`
+	//+optional
+	Hosts []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	//+optional
+	Port int `json:"port,omitempty" yaml:"port,omitempty"`
+	//+optional
+	Headers []HTTPHeader `json:"headers,omitempty" yaml:"headers,omitempty"`
+	//+optional
+	JWT *JWTRequirement `json:"jwt,omitempty" yaml:"jwt,omitempty"`
+}
+
+// HTTPHeader requires the request to carry the header Name with one of Values.
+type HTTPHeader struct {
+	Name   string   `json:"name" yaml:"name"`
+	Values []string `json:"values" yaml:"values"`
+}
+
+// JWTRequirement requires requests to carry a valid JWT issued by Issuer, in addition to the mesh identity of the client.
+// The token can be further limited to specific subjects, audiences and claim values.
+type JWTRequirement struct {
+	Issuer string `json:"issuer" yaml:"issuer"`
+	// JWKSURI is where the issuer's signing keys are published. When empty, they are discovered using OpenID Connect.
+	//+optional
+	JWKSURI string `json:"jwksUri,omitempty" yaml:"jwksUri,omitempty"`
+	//+optional
+	Subjects []string `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+	//+optional
+	Audiences []string `json:"audiences,omitempty" yaml:"audiences,omitempty"`
+	//+optional
+	Claims []JWTClaim `json:"claims,omitempty" yaml:"claims,omitempty"`
+}
+
+// JWTClaim requires the token's claim Name to have one of Values.
+type JWTClaim struct {
+	Name   string   `json:"name" yaml:"name"`
+	Values []string `json:"values" yaml:"values"`
 }
 
 type KafkaTopic struct {
@@ -288,6 +369,46 @@ type KafkaTopic struct {
 	Operations []KafkaOperation `json:"operations" yaml:"operations"`
 }
 
+// KafkaConsumerGroup is a consumer group the client may use, which grants it read and describe on the group.
+type KafkaConsumerGroup struct {
+	Name string `json:"name" yaml:"name"`
+	//+optional
+	Pattern ResourcePatternType `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+}
+
+// KafkaTransactionalID is a transactional ID the client may use, such as for exactly-once producers, which need the
+// produce and describe operations on it.
+type KafkaTransactionalID struct {
+	Name string `json:"name" yaml:"name"`
+	//+optional
+	Pattern    ResourcePatternType `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Operations []KafkaOperation    `json:"operations" yaml:"operations"`
+}
+
+// KafkaQuota limits the throughput of the client on the Kafka server. Quotas are applied to the principal of the client,
+// and removed once no intent of the client requests them.
+type KafkaQuota struct {
+	// ProducerByteRate is the number of bytes per second the client may produce.
+	//+optional
+	//+kubebuilder:validation:Minimum=1
+	ProducerByteRate *int64 `json:"producerByteRate,omitempty" yaml:"producerByteRate,omitempty"`
+	// ConsumerByteRate is the number of bytes per second the client may consume.
+	//+optional
+	//+kubebuilder:validation:Minimum=1
+	ConsumerByteRate *int64 `json:"consumerByteRate,omitempty" yaml:"consumerByteRate,omitempty"`
+	// RequestPercentage is the percentage of the time of a request handler or network thread that the client may use.
+	//+optional
+	//+kubebuilder:validation:Minimum=1
+	RequestPercentage *int64 `json:"requestPercentage,omitempty" yaml:"requestPercentage,omitempty"`
+}
+
+// AppliedKafkaQuota is a quota applied to the client on a Kafka server.
+type AppliedKafkaQuota struct {
+	// Server is the name of the Kafka server, formatted as name.namespace.
+	Server string     `json:"server" yaml:"server"`
+	Quota  KafkaQuota `json:"quota" yaml:"quota"`
+}
+
 type ResolvedIPs struct {
 	DNS string   `json:"dns,omitempty" yaml:"dns,omitempty"`
 	IPs []string `json:"ips,omitempty" yaml:"ips,omitempty"`
@@ -315,6 +436,9 @@ type IntentsStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration"`
 	// +optional
 	ResolvedIPs []ResolvedIPs `json:"resolvedIPs,omitempty" yaml:"resolvedIPs,omitempty"`
+	// The Kafka quotas applied to the client, by server.
+	// +optional
+	KafkaQuotas []AppliedKafkaQuota `json:"kafkaQuotas,omitempty" yaml:"kafkaQuotas,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -361,7 +485,7 @@ func (in *ClientIntents) GetIntentsLabelMapping(requestNamespace string) map[str
 	otterizeAccessLabels := make(map[string]string)
 
 	for _, intent := range in.GetCallsList() {
-		if intent.Type == IntentTypeAWS || intent.Type == IntentTypeGCP || intent.Type == IntentTypeAzure || intent.Type == IntentTypeDatabase {
+		if intent.Type == IntentTypeAWS || intent.Type == IntentTypeGCP || intent.Type == IntentTypeAzure || intent.Type == IntentTypeDatabase || intent.IsTargetExternalKafkaServer() {
 			continue
 		}
 		ns := intent.GetTargetServerNamespace(requestNamespace)
@@ -404,7 +528,15 @@ func (in *Intent) IsTargetTheKubernetesAPIServer(objectNamespace string) bool {
 		in.GetTargetServerNamespace(objectNamespace) == KubernetesAPIServerNamespace
 }
 
+// IsTargetExternalKafkaServer returns whether the intent targets a Kafka server outside the cluster by its alias.
+func (in *Intent) IsTargetExternalKafkaServer() bool {
+	return in.Type == IntentTypeKafka && strings.HasPrefix(in.Name, ExternalKafkaServerPrefix)
+}
+
 func (in *Intent) IsTargetInCluster() bool {
+	if in.IsTargetExternalKafkaServer() {
+		return false
+	}
 	if in.Type == "" || in.Type == IntentTypeHTTP || in.Type == IntentTypeKafka {
 		return true
 	}
@@ -427,6 +559,10 @@ func (in *Intent) GetTargetServerName() string {
 		return in.Name
 	}
 
+	if in.IsTargetExternalKafkaServer() {
+		return strings.TrimPrefix(in.Name, ExternalKafkaServerPrefix)
+	}
+
 	if in.IsTargetServerKubernetesService() {
 		name = strings.ReplaceAll(in.Name, "svc:", "") // Replace so all chars are valid in K8s label
 	} else {
@@ -713,6 +849,7 @@ func intentsHTTPResourceToCloud(resource HTTPResource, index int) *graphqlclient
 		return lo.ToPtr(graphqlclient.HTTPMethod(method))
 	})
 
+	// hosts, port and headers are not reported, as the cloud API does not support them yet
 	httpConfig := graphqlclient.HTTPConfigInput{
 		Path:    lo.ToPtr(resource.Path),
 		Methods: methods,
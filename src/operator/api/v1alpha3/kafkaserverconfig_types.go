@@ -17,18 +17,84 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"fmt"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// TLSSource configures the client certificate and root CA used to connect to the Kafka server, either as files mounted
+// on the operator pod or as a Secret with the "tls.crt", "tls.key" and "ca.crt" keys. Secrets are watched, so rotated
+// credentials are used without restarting the operator.
 type TLSSource struct {
+	// +kubebuilder:validation:Optional
+	CertFile string `json:"certFile,omitempty" yaml:"certFile,omitempty"`
+	// +kubebuilder:validation:Optional
+	KeyFile string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	// +kubebuilder:validation:Optional
+	RootCAFile string `json:"rootCAFile,omitempty" yaml:"rootCAFile,omitempty"`
+	// +kubebuilder:validation:Optional
+	SecretRef *SecretReference `json:"secretRef,omitempty" yaml:"secretRef,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=SCRAM-SHA-256;SCRAM-SHA-512;PLAIN;OAUTHBEARER
+type KafkaSASLMechanism string
+
+const (
+	KafkaSASLMechanismSCRAMSHA256 KafkaSASLMechanism = "SCRAM-SHA-256"
+	KafkaSASLMechanismSCRAMSHA512 KafkaSASLMechanism = "SCRAM-SHA-512"
+	KafkaSASLMechanismPlain       KafkaSASLMechanism = "PLAIN"
+	KafkaSASLMechanismOAuthBearer KafkaSASLMechanism = "OAUTHBEARER"
+)
+
+type SecretReference struct {
 	// +kubebuilder:validation:Required
-	CertFile string `json:"certFile" yaml:"certFile"`
+	Name string `json:"name" yaml:"name"`
+	// Must be empty or the namespace of the KafkaServerConfig, as Secrets are only read from the namespace of the
+	// KafkaServerConfig.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// KafkaAuthentication configures SASL authentication to the Kafka server. The Secret holds the "username" and "password"
+// keys for SCRAM and PLAIN, or the "clientId" and "clientSecret" keys for OAUTHBEARER, which gets its tokens from
+// TokenURL using the OAuth client credentials flow.
+type KafkaAuthentication struct {
 	// +kubebuilder:validation:Required
-	KeyFile string `json:"keyFile" yaml:"keyFile"`
+	Mechanism KafkaSASLMechanism `json:"mechanism" yaml:"mechanism"`
 	// +kubebuilder:validation:Required
-	RootCAFile string `json:"rootCAFile" yaml:"rootCAFile"`
+	SecretRef SecretReference `json:"secretRef" yaml:"secretRef"`
+	// +kubebuilder:validation:Optional
+	TokenURL string `json:"tokenURL,omitempty" yaml:"tokenURL,omitempty"`
+	// +kubebuilder:validation:Optional
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// StrimziConfig configures the Strimzi Kafka cluster whose User Operator applies the ACLs of the KafkaUser resources
+// written by the intents operator.
+type StrimziConfig struct {
+	// The name of the Strimzi Kafka cluster, set as the strimzi.io/cluster label of KafkaUser resources.
+	// +kubebuilder:validation:Required
+	ClusterName string `json:"clusterName" yaml:"clusterName"`
+	// The namespace watched by the Strimzi User Operator. Defaults to the namespace of the KafkaServerConfig.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// ExternalKafkaServer configures a Kafka server running outside the Kubernetes cluster, such as Amazon MSK or Confluent
+// Cloud. Kafka intents target it by its alias, as "external:<alias>", and network policies allow egress to its IPs.
+type ExternalKafkaServer struct {
+	// The name Kafka intents use to target the server. Must be unique across the cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Alias string `json:"alias" yaml:"alias"`
+	// The IP addresses or CIDRs of the Kafka brokers, allowed as egress destinations in the network policies of clients.
+	// +kubebuilder:validation:Optional
+	IPs []string `json:"ips,omitempty" yaml:"ips,omitempty"`
+	// The ports of the Kafka brokers. Defaults to the port of Addr.
+	// +kubebuilder:validation:Optional
+	Ports []int `json:"ports,omitempty" yaml:"ports,omitempty"`
 }
 
 // +kubebuilder:validation:Enum=literal;prefix
@@ -56,9 +122,29 @@ type KafkaServerConfigSpec struct {
 	// will automatically create an Intent so that the Intents Operator can connect. Set to true to disable.
 	NoAutoCreateIntentsForOperator bool   `json:"noAutoCreateIntentsForOperator,omitempty" yaml:"noAutoCreateIntentsForOperator,omitempty"`
 	Addr                           string `json:"addr,omitempty" yaml:"addr,omitempty"`
+	// Grants every client read and describe on all consumer groups, instead of only on the consumer groups declared in
+	// its intents.
+	// +kubebuilder:validation:Optional
+	AllowAllConsumerGroups bool `json:"allowAllConsumerGroups,omitempty" yaml:"allowAllConsumerGroups,omitempty"`
 	// +kubebuilder:validation:Optional
-	TLS    TLSSource     `json:"tls,omitempty" yaml:"tls,omitempty"`
-	Topics []TopicConfig `json:"topics,omitempty" yaml:"topics,omitempty"`
+	TLS TLSSource `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// Authenticates to the Kafka server with SASL. TLS is still used to encrypt the connection, and the client
+	// certificate is optional.
+	// +kubebuilder:validation:Optional
+	Authentication *KafkaAuthentication `json:"authentication,omitempty" yaml:"authentication,omitempty"`
+	// The Kafka user name of client services, with $ServiceName and $Namespace placeholders. Defaults to the subject of
+	// the operator's certificate with CN=$ServiceName.$Namespace for mutual TLS, and to $ServiceName.$Namespace for SASL.
+	// +kubebuilder:validation:Optional
+	UserNameMapping string `json:"userNameMapping,omitempty" yaml:"userNameMapping,omitempty"`
+	// Enforces intents by writing a KafkaUser resource per client service, named after its Kafka user name, instead of
+	// connecting to the Kafka server, so the Strimzi User Operator remains the single writer of ACLs. Topic
+	// configurations are not applied, as KafkaUser resources cannot express them.
+	// +kubebuilder:validation:Optional
+	Strimzi *StrimziConfig `json:"strimzi,omitempty" yaml:"strimzi,omitempty"`
+	// Identifies a Kafka server outside the cluster by an alias, instead of by Service.
+	// +kubebuilder:validation:Optional
+	External *ExternalKafkaServer `json:"external,omitempty" yaml:"external,omitempty"`
+	Topics   []TopicConfig        `json:"topics,omitempty" yaml:"topics,omitempty"`
 }
 
 // KafkaServerConfigStatus defines the observed state of KafkaServerConfig
@@ -82,6 +168,23 @@ type KafkaServerConfig struct {
 
 func (ksc *KafkaServerConfig) Hub() {}
 
+// GetServerName returns the name Kafka intents to this server are grouped by: the namespaced name of its service, or
+// the external server alias with an empty namespace, as aliases are cluster-wide.
+func (ksc *KafkaServerConfig) GetServerName() types.NamespacedName {
+	if ksc.Spec.External != nil {
+		return types.NamespacedName{Name: ExternalKafkaServerPrefix + ksc.Spec.External.Alias}
+	}
+	return types.NamespacedName{Name: ksc.Spec.Service.Name, Namespace: ksc.Namespace}
+}
+
+// GetIntentTargetName returns the name Kafka intents use to target this server.
+func (ksc *KafkaServerConfig) GetIntentTargetName() string {
+	if ksc.Spec.External != nil {
+		return ExternalKafkaServerPrefix + ksc.Spec.External.Alias
+	}
+	return fmt.Sprintf("%s.%s", ksc.Spec.Service.Name, ksc.Namespace)
+}
+
 //+kubebuilder:object:root=true
 
 // KafkaServerConfigList contains a list of KafkaServerConfig
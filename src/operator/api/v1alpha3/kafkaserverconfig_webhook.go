@@ -18,10 +18,11 @@ package v1alpha3
 
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
-func (ksc *KafkaServerConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (ksc *KafkaServerConfig) SetupWebhookWithManager(mgr ctrl.Manager, validator webhook.CustomValidator) error {
 	return ctrl.NewWebhookManagedBy(mgr).
-		For(ksc).
+		For(ksc).WithValidator(validator).
 		Complete()
 }
@@ -23,12 +23,18 @@ import (
 // ProtectedServiceSpec defines the desired state of ProtectedService
 type ProtectedServiceSpec struct {
 	Name string `json:"name,omitempty"`
+	// JWT requires every request to the service to carry a matching token, on top of what the clients' intents allow.
+	//+optional
+	JWT *JWTRequirement `json:"jwt,omitempty"`
 }
 
 // ProtectedServiceStatus defines the observed state of ProtectedService
 type ProtectedServiceStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// IstioStrictMTLS is true when an Istio PeerAuthentication in STRICT mode is applied to the service's workloads.
+	IstioStrictMTLS bool `json:"istioStrictMTLS,omitempty"`
+	// ClientsMissingSidecar lists the clients that have intents to the service but no Istio sidecar. These clients
+	// cannot reach the service while strict mTLS is enforced.
+	ClientsMissingSidecar []string `json:"clientsMissingSidecar,omitempty"`
 }
 
 //+kubebuilder:object:root=true
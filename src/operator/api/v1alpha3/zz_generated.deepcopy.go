@@ -25,6 +25,22 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedKafkaQuota) DeepCopyInto(out *AppliedKafkaQuota) {
+	*out = *in
+	in.Quota.DeepCopyInto(&out.Quota)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedKafkaQuota.
+func (in *AppliedKafkaQuota) DeepCopy() *AppliedKafkaQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedKafkaQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientIntents) DeepCopyInto(out *ClientIntents) {
 	*out = *in
@@ -108,6 +124,51 @@ func (in *DatabaseResource) DeepCopy() *DatabaseResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalKafkaServer) DeepCopyInto(out *ExternalKafkaServer) {
+	*out = *in
+	if in.IPs != nil {
+		in, out := &in.IPs, &out.IPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalKafkaServer.
+func (in *ExternalKafkaServer) DeepCopy() *ExternalKafkaServer {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalKafkaServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
+func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPResource) DeepCopyInto(out *HTTPResource) {
 	*out = *in
@@ -116,6 +177,23 @@ func (in *HTTPResource) DeepCopyInto(out *HTTPResource) {
 		*out = make([]HTTPMethod, len(*in))
 		copy(*out, *in)
 	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(JWTRequirement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPResource.
@@ -138,6 +216,28 @@ func (in *Intent) DeepCopyInto(out *Intent) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConsumerGroups != nil {
+		in, out := &in.ConsumerGroups, &out.ConsumerGroups
+		*out = make([]KafkaConsumerGroup, len(*in))
+		copy(*out, *in)
+	}
+	if in.TransactionalIDs != nil {
+		in, out := &in.TransactionalIDs, &out.TransactionalIDs
+		*out = make([]KafkaTransactionalID, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterOperations != nil {
+		in, out := &in.ClusterOperations, &out.ClusterOperations
+		*out = make([]KafkaOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(KafkaQuota)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.HTTPResources != nil {
 		in, out := &in.HTTPResources, &out.HTTPResources
 		*out = make([]HTTPResource, len(*in))
@@ -157,6 +257,11 @@ func (in *Intent) DeepCopyInto(out *Intent) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AWSManagedPolicies != nil {
+		in, out := &in.AWSManagedPolicies, &out.AWSManagedPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.GCPPermissions != nil {
 		in, out := &in.GCPPermissions, &out.GCPPermissions
 		*out = make([]string, len(*in))
@@ -172,6 +277,11 @@ func (in *Intent) DeepCopyInto(out *Intent) {
 		*out = new(Internet)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Intent.
@@ -217,6 +327,13 @@ func (in *IntentsStatus) DeepCopyInto(out *IntentsStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.KafkaQuotas != nil {
+		in, out := &in.KafkaQuotas, &out.KafkaQuotas
+		*out = make([]AppliedKafkaQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentsStatus.
@@ -259,6 +376,124 @@ func (in *Internet) DeepCopy() *Internet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTClaim) DeepCopyInto(out *JWTClaim) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTClaim.
+func (in *JWTClaim) DeepCopy() *JWTClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTRequirement) DeepCopyInto(out *JWTRequirement) {
+	*out = *in
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]JWTClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTRequirement.
+func (in *JWTRequirement) DeepCopy() *JWTRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaAuthentication) DeepCopyInto(out *KafkaAuthentication) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaAuthentication.
+func (in *KafkaAuthentication) DeepCopy() *KafkaAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConsumerGroup) DeepCopyInto(out *KafkaConsumerGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConsumerGroup.
+func (in *KafkaConsumerGroup) DeepCopy() *KafkaConsumerGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConsumerGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaQuota) DeepCopyInto(out *KafkaQuota) {
+	*out = *in
+	if in.ProducerByteRate != nil {
+		in, out := &in.ProducerByteRate, &out.ProducerByteRate
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ConsumerByteRate != nil {
+		in, out := &in.ConsumerByteRate, &out.ConsumerByteRate
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RequestPercentage != nil {
+		in, out := &in.RequestPercentage, &out.RequestPercentage
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaQuota.
+func (in *KafkaQuota) DeepCopy() *KafkaQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaServerConfig) DeepCopyInto(out *KafkaServerConfig) {
 	*out = *in
@@ -322,7 +557,22 @@ func (in *KafkaServerConfigList) DeepCopyObject() runtime.Object {
 func (in *KafkaServerConfigSpec) DeepCopyInto(out *KafkaServerConfigSpec) {
 	*out = *in
 	out.Service = in.Service
-	out.TLS = in.TLS
+	in.TLS.DeepCopyInto(&out.TLS)
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(KafkaAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Strimzi != nil {
+		in, out := &in.Strimzi, &out.Strimzi
+		*out = new(StrimziConfig)
+		**out = **in
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalKafkaServer)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Topics != nil {
 		in, out := &in.Topics, &out.Topics
 		*out = make([]TopicConfig, len(*in))
@@ -375,13 +625,33 @@ func (in *KafkaTopic) DeepCopy() *KafkaTopic {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTransactionalID) DeepCopyInto(out *KafkaTransactionalID) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]KafkaOperation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTransactionalID.
+func (in *KafkaTransactionalID) DeepCopy() *KafkaTransactionalID {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTransactionalID)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtectedService) DeepCopyInto(out *ProtectedService) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedService.
@@ -437,6 +707,11 @@ func (in *ProtectedServiceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtectedServiceSpec) DeepCopyInto(out *ProtectedServiceSpec) {
 	*out = *in
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(JWTRequirement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedServiceSpec.
@@ -452,6 +727,11 @@ func (in *ProtectedServiceSpec) DeepCopy() *ProtectedServiceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProtectedServiceStatus) DeepCopyInto(out *ProtectedServiceStatus) {
 	*out = *in
+	if in.ClientsMissingSidecar != nil {
+		in, out := &in.ClientsMissingSidecar, &out.ClientsMissingSidecar
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedServiceStatus.
@@ -464,6 +744,21 @@ func (in *ProtectedServiceStatus) DeepCopy() *ProtectedServiceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResolvedIPs) DeepCopyInto(out *ResolvedIPs) {
 	*out = *in
@@ -484,6 +779,21 @@ func (in *ResolvedIPs) DeepCopy() *ResolvedIPs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
@@ -499,9 +809,29 @@ func (in *Service) DeepCopy() *Service {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrimziConfig) DeepCopyInto(out *StrimziConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrimziConfig.
+func (in *StrimziConfig) DeepCopy() *StrimziConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StrimziConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSSource) DeepCopyInto(out *TLSSource) {
 	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSource.
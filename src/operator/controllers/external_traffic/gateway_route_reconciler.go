@@ -0,0 +1,90 @@
+package external_traffic
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/operator_cloud_client"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+//+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes;grpcroutes;tlsroutes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=networkpolicies,verbs=get;update;patch;list;watch;delete;create
+
+// GatewayRouteReconciler is the Gateway API counterpart of the IngressReconciler - it watches HTTPRoutes, GRPCRoutes
+// and TLSRoutes, for the route kinds that are installed in the cluster.
+type GatewayRouteReconciler struct {
+	client.Client
+	extNetpolHandler *NetworkPolicyHandler
+	injectablerecorder.InjectableRecorder
+	serviceUploader ServiceUploader
+}
+
+func NewGatewayRouteReconciler(
+	client client.Client,
+	extNetpolHandler *NetworkPolicyHandler,
+	otterizeClient operator_cloud_client.CloudClient,
+) *GatewayRouteReconciler {
+	serviceUploader := NewServiceUploader(client, otterizeClient, extNetpolHandler.gatewayRoutes)
+
+	return &GatewayRouteReconciler{
+		Client:           client,
+		extNetpolHandler: extNetpolHandler,
+		serviceUploader:  serviceUploader,
+	}
+}
+
+func (r *GatewayRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if !r.extNetpolHandler.gatewayRoutes.IsAnyRouteKindInstalled() {
+		return nil
+	}
+
+	recorder := mgr.GetEventRecorderFor("intents-operator")
+	r.InjectRecorder(recorder)
+
+	watcher, err := controller.New("gateway-route-watcher", mgr, controller.Options{
+		Reconciler:   r,
+		RecoverPanic: lo.ToPtr(true),
+	})
+	if err != nil {
+		return errors.Errorf("unable to set up gateway route controller: %w", err)
+	}
+
+	for _, routeKind := range r.extNetpolHandler.gatewayRoutes.installedKinds {
+		if err = watcher.Watch(source.Kind(mgr.GetCache(), routeKind.newObject()), &handler.EnqueueRequestForObject{}); err != nil {
+			return errors.Errorf("unable to watch %s: %w", routeKind.groupKind.Kind, err)
+		}
+	}
+
+	return nil
+}
+
+// Reconcile handles route creation, update and delete. Like the IngressReconciler, it re-evaluates the external traffic
+// policies for all pods, and reports the externally accessible services of each namespace the route refers to, or
+// referred to before it was updated or deleted.
+func (r *GatewayRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	err := r.extNetpolHandler.HandleAllPods(ctx)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	namespaces, err := r.extNetpolHandler.gatewayRoutes.getNamespacesReferredByRoute(ctx, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	for _, namespace := range sets.List(namespaces) {
+		err = r.serviceUploader.UploadNamespaceServices(ctx, namespace)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,236 @@
+package external_traffic
+
+import (
+	"context"
+	"fmt"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	"sync"
+)
+
+// gatewayRouteKind describes a Gateway API route kind whose backendRefs may expose a service to external traffic.
+type gatewayRouteKind struct {
+	groupKind  schema.GroupKind
+	version    string
+	indexField string
+	newObject  func() client.Object
+	newList    func() client.ObjectList
+	// backendServices returns the namespaced names of the services the route forwards traffic to.
+	backendServices func(route client.Object) sets.Set[types.NamespacedName]
+	// routeNames returns the names of the routes in the list, in the same format as used by the created-for annotation.
+	routeNames func(list client.ObjectList) []string
+}
+
+var supportedGatewayRouteKinds = []gatewayRouteKind{
+	{
+		groupKind:  schema.GroupKind{Group: gatewayv1.GroupName, Kind: "HTTPRoute"},
+		version:    gatewayv1.GroupVersion.Version,
+		indexField: v1alpha3.HTTPRouteServiceNamesIndexField,
+		newObject:  func() client.Object { return &gatewayv1.HTTPRoute{} },
+		newList:    func() client.ObjectList { return &gatewayv1.HTTPRouteList{} },
+		backendServices: func(route client.Object) sets.Set[types.NamespacedName] {
+			httpRoute := route.(*gatewayv1.HTTPRoute)
+			services := sets.New[types.NamespacedName]()
+			for _, rule := range httpRoute.Spec.Rules {
+				for _, backendRef := range rule.BackendRefs {
+					insertBackendService(services, httpRoute.Namespace, backendRef.BackendObjectReference)
+				}
+			}
+			return services
+		},
+		routeNames: func(list client.ObjectList) []string {
+			names := make([]string, 0)
+			for _, route := range list.(*gatewayv1.HTTPRouteList).Items {
+				names = append(names, formatGatewayRouteName("HTTPRoute", route.Namespace, route.Name))
+			}
+			return names
+		},
+	},
+	{
+		groupKind:  schema.GroupKind{Group: gatewayv1alpha2.GroupName, Kind: "GRPCRoute"},
+		version:    gatewayv1alpha2.GroupVersion.Version,
+		indexField: v1alpha3.GRPCRouteServiceNamesIndexField,
+		newObject:  func() client.Object { return &gatewayv1alpha2.GRPCRoute{} },
+		newList:    func() client.ObjectList { return &gatewayv1alpha2.GRPCRouteList{} },
+		backendServices: func(route client.Object) sets.Set[types.NamespacedName] {
+			grpcRoute := route.(*gatewayv1alpha2.GRPCRoute)
+			services := sets.New[types.NamespacedName]()
+			for _, rule := range grpcRoute.Spec.Rules {
+				for _, backendRef := range rule.BackendRefs {
+					insertBackendService(services, grpcRoute.Namespace, backendRef.BackendObjectReference)
+				}
+			}
+			return services
+		},
+		routeNames: func(list client.ObjectList) []string {
+			names := make([]string, 0)
+			for _, route := range list.(*gatewayv1alpha2.GRPCRouteList).Items {
+				names = append(names, formatGatewayRouteName("GRPCRoute", route.Namespace, route.Name))
+			}
+			return names
+		},
+	},
+	{
+		groupKind:  schema.GroupKind{Group: gatewayv1alpha2.GroupName, Kind: "TLSRoute"},
+		version:    gatewayv1alpha2.GroupVersion.Version,
+		indexField: v1alpha3.TLSRouteServiceNamesIndexField,
+		newObject:  func() client.Object { return &gatewayv1alpha2.TLSRoute{} },
+		newList:    func() client.ObjectList { return &gatewayv1alpha2.TLSRouteList{} },
+		backendServices: func(route client.Object) sets.Set[types.NamespacedName] {
+			tlsRoute := route.(*gatewayv1alpha2.TLSRoute)
+			services := sets.New[types.NamespacedName]()
+			for _, rule := range tlsRoute.Spec.Rules {
+				for _, backendRef := range rule.BackendRefs {
+					insertBackendService(services, tlsRoute.Namespace, backendRef.BackendObjectReference)
+				}
+			}
+			return services
+		},
+		routeNames: func(list client.ObjectList) []string {
+			names := make([]string, 0)
+			for _, route := range list.(*gatewayv1alpha2.TLSRouteList).Items {
+				names = append(names, formatGatewayRouteName("TLSRoute", route.Namespace, route.Name))
+			}
+			return names
+		},
+	},
+}
+
+// insertBackendService adds the service referenced by a backendRef. backendRefs that point at something other than
+// a core Service (e.g. a ServiceImport) are ignored. Cross-namespace references are included as is - they are only
+// honored by the Gateway implementation if a ReferenceGrant allows them.
+func insertBackendService(services sets.Set[types.NamespacedName], routeNamespace string, ref gatewayv1.BackendObjectReference) {
+	if ref.Group != nil && *ref.Group != corev1.GroupName {
+		return
+	}
+	if ref.Kind != nil && *ref.Kind != "Service" {
+		return
+	}
+
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	services.Insert(types.NamespacedName{Namespace: namespace, Name: string(ref.Name)})
+}
+
+func formatGatewayRouteName(kind string, namespace string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// GatewayRoutes resolves which Gateway API routes (HTTPRoute, GRPCRoute, TLSRoute) refer to a service.
+// Only route kinds whose CRDs are installed in the cluster when the operator starts are taken into account.
+type GatewayRoutes struct {
+	client         client.Client
+	installedKinds []gatewayRouteKind
+	// referredNamespaces holds the namespaces each route referred to when it was last seen, so that backends in other
+	// namespaces can still be re-evaluated once the route is updated or deleted.
+	referredNamespaces     map[types.NamespacedName]sets.Set[string]
+	referredNamespacesLock sync.Mutex
+}
+
+func NewGatewayRoutes(client client.Client) *GatewayRoutes {
+	return &GatewayRoutes{client: client, referredNamespaces: make(map[types.NamespacedName]sets.Set[string])}
+}
+
+// InitGatewayRouteReferencedServicesIndices detects which Gateway API route kinds are served by the cluster and
+// indexes them by the services they refer to. Must be called before the manager is started.
+func (g *GatewayRoutes) InitGatewayRouteReferencedServicesIndices(mgr ctrl.Manager) error {
+	for _, routeKind := range supportedGatewayRouteKinds {
+		_, err := mgr.GetRESTMapper().RESTMapping(routeKind.groupKind, routeKind.version)
+		if meta.IsNoMatchError(err) {
+			logrus.Debugf("%s CRD is not installed, external traffic via %s will not be detected", routeKind.groupKind.String(), routeKind.groupKind.Kind)
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err)
+		}
+
+		backendServices := routeKind.backendServices
+		err = mgr.GetCache().IndexField(
+			context.Background(),
+			routeKind.newObject(),
+			routeKind.indexField,
+			func(object client.Object) []string {
+				serviceKeys := sets.New[string]()
+				for service := range backendServices(object) {
+					serviceKeys.Insert(service.String())
+				}
+				return sets.List(serviceKeys)
+			})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+
+		g.installedKinds = append(g.installedKinds, routeKind)
+	}
+
+	return nil
+}
+
+func (g *GatewayRoutes) IsAnyRouteKindInstalled() bool {
+	return len(g.installedKinds) > 0
+}
+
+// getRoutesReferringToService returns the names of all Gateway API routes that forward traffic to the service.
+func (g *GatewayRoutes) getRoutesReferringToService(ctx context.Context, svc *corev1.Service) ([]string, error) {
+	routeNames := make([]string, 0)
+	serviceKey := types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}.String()
+	for _, routeKind := range g.installedKinds {
+		routeList := routeKind.newList()
+		// Routes may refer to services in other namespaces, so routes are listed across all namespaces.
+		err := g.client.List(ctx, routeList, &client.MatchingFields{routeKind.indexField: serviceKey})
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		routeNames = append(routeNames, routeKind.routeNames(routeList)...)
+	}
+
+	return routeNames, nil
+}
+
+// getNamespacesReferredByRoute returns the namespaces of the services referred to by routes with the given name,
+// of any installed route kind, alongside the route's own namespace. The namespaces the routes referred to when they were
+// last seen are included as well, so that services that are no longer referred to because the route was updated or
+// deleted are re-evaluated too.
+func (g *GatewayRoutes) getNamespacesReferredByRoute(ctx context.Context, routeName types.NamespacedName) (sets.Set[string], error) {
+	namespaces := sets.New[string](routeName.Namespace)
+	routeFound := false
+	for _, routeKind := range g.installedKinds {
+		route := routeKind.newObject()
+		err := g.client.Get(ctx, routeName, route)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+
+		routeFound = true
+		for service := range routeKind.backendServices(route) {
+			namespaces.Insert(service.Namespace)
+		}
+	}
+
+	g.referredNamespacesLock.Lock()
+	defer g.referredNamespacesLock.Unlock()
+	previousNamespaces := g.referredNamespaces[routeName]
+	if routeFound {
+		g.referredNamespaces[routeName] = namespaces
+	} else {
+		delete(g.referredNamespaces, routeName)
+	}
+
+	return namespaces.Union(previousNamespaces), nil
+}
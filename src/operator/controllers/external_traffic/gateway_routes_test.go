@@ -0,0 +1,90 @@
+package external_traffic
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"testing"
+)
+
+const backendNamespace = "backend-namespace"
+
+type GatewayRoutesTestSuite struct {
+	testbase.MocksSuiteBase
+	gatewayRoutes *GatewayRoutes
+	routeName     types.NamespacedName
+}
+
+func (s *GatewayRoutesTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.gatewayRoutes = NewGatewayRoutes(s.Client)
+	s.gatewayRoutes.installedKinds = lo.Filter(supportedGatewayRouteKinds, func(routeKind gatewayRouteKind, _ int) bool {
+		return routeKind.groupKind.Kind == "HTTPRoute"
+	})
+	s.routeName = types.NamespacedName{Namespace: testNamespace, Name: "http-route"}
+}
+
+func (s *GatewayRoutesTestSuite) expectRoute(backendNamespaces ...string) {
+	s.Client.EXPECT().Get(gomock.Any(), s.routeName, gomock.AssignableToTypeOf(&gatewayv1.HTTPRoute{})).DoAndReturn(
+		func(ctx context.Context, name types.NamespacedName, route *gatewayv1.HTTPRoute, opts ...client.GetOption) error {
+			backendRefs := lo.Map(backendNamespaces, func(namespace string, _ int) gatewayv1.HTTPBackendRef {
+				return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name:      "backend",
+					Namespace: lo.ToPtr(gatewayv1.Namespace(namespace)),
+				}}}
+			})
+			route.ObjectMeta = metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}
+			route.Spec.Rules = []gatewayv1.HTTPRouteRule{{BackendRefs: backendRefs}}
+			return nil
+		})
+}
+
+func (s *GatewayRoutesTestSuite) expectRouteNotFound() {
+	s.Client.EXPECT().Get(gomock.Any(), s.routeName, gomock.AssignableToTypeOf(&gatewayv1.HTTPRoute{})).
+		Return(k8serrors.NewNotFound(gatewayv1.Resource("httproutes"), s.routeName.Name))
+}
+
+func (s *GatewayRoutesTestSuite) TestDeletedRouteIncludesPreviouslyReferredNamespaces() {
+	s.expectRoute(backendNamespace)
+	namespaces, err := s.gatewayRoutes.getNamespacesReferredByRoute(context.Background(), s.routeName)
+	s.Require().NoError(err)
+	s.Equal(sets.New(testNamespace, backendNamespace), namespaces)
+
+	s.expectRouteNotFound()
+	namespaces, err = s.gatewayRoutes.getNamespacesReferredByRoute(context.Background(), s.routeName)
+	s.Require().NoError(err)
+	s.Equal(sets.New(testNamespace, backendNamespace), namespaces)
+
+	s.expectRouteNotFound()
+	namespaces, err = s.gatewayRoutes.getNamespacesReferredByRoute(context.Background(), s.routeName)
+	s.Require().NoError(err)
+	s.Equal(sets.New(testNamespace), namespaces)
+}
+
+func (s *GatewayRoutesTestSuite) TestUpdatedRouteIncludesPreviouslyReferredNamespaces() {
+	s.expectRoute(backendNamespace)
+	_, err := s.gatewayRoutes.getNamespacesReferredByRoute(context.Background(), s.routeName)
+	s.Require().NoError(err)
+
+	s.expectRoute(testNamespace)
+	namespaces, err := s.gatewayRoutes.getNamespacesReferredByRoute(context.Background(), s.routeName)
+	s.Require().NoError(err)
+	s.Equal(sets.New(testNamespace, backendNamespace), namespaces)
+
+	s.expectRoute(testNamespace)
+	namespaces, err = s.gatewayRoutes.getNamespacesReferredByRoute(context.Background(), s.routeName)
+	s.Require().NoError(err)
+	s.Equal(sets.New(testNamespace), namespaces)
+}
+
+func TestGatewayRoutesTestSuite(t *testing.T) {
+	suite.Run(t, new(GatewayRoutesTestSuite))
+}
@@ -27,7 +27,7 @@ func NewIngressReconciler(
 	extNetpolHandler *NetworkPolicyHandler,
 	otterizeClient operator_cloud_client.CloudClient,
 ) *IngressReconciler {
-	serviceUploader := NewServiceUploader(client, otterizeClient)
+	serviceUploader := NewServiceUploader(client, otterizeClient, extNetpolHandler.gatewayRoutes)
 
 	return &IngressReconciler{
 		Client:           client,
@@ -41,20 +41,22 @@ type NetworkPolicyHandler struct {
 	scheme *runtime.Scheme
 	injectablerecorder.InjectableRecorder
 	allowExternalTraffic allowexternaltraffic.Enum
+	gatewayRoutes        *GatewayRoutes
 }
 
 func NewNetworkPolicyHandler(
 	client client.Client,
 	scheme *runtime.Scheme,
 	allowExternalTraffic allowexternaltraffic.Enum,
+	gatewayRoutes *GatewayRoutes,
 ) *NetworkPolicyHandler {
-	return &NetworkPolicyHandler{client: client, scheme: scheme, allowExternalTraffic: allowExternalTraffic}
+	return &NetworkPolicyHandler{client: client, scheme: scheme, allowExternalTraffic: allowExternalTraffic, gatewayRoutes: gatewayRoutes}
 }
 
 func (r *NetworkPolicyHandler) createOrUpdateNetworkPolicy(
-	ctx context.Context, endpoints *corev1.Endpoints, owner *corev1.Service, otterizeServiceName string, selector metav1.LabelSelector, ingressList *v1.IngressList, successMsg string) error {
+	ctx context.Context, endpoints *corev1.Endpoints, owner *corev1.Service, otterizeServiceName string, selector metav1.LabelSelector, ingressList *v1.IngressList, gatewayRoutes []string, successMsg string) error {
 	policyName := r.formatPolicyName(endpoints.Name)
-	newPolicy := buildNetworkPolicyObjectForEndpoints(endpoints, otterizeServiceName, selector, ingressList, policyName)
+	newPolicy := buildNetworkPolicyObjectForEndpoints(endpoints, otterizeServiceName, selector, ingressList, gatewayRoutes, policyName)
 	err := controllerutil.SetOwnerReference(owner, newPolicy, r.scheme)
 	if err != nil {
 		return errors.Wrap(err)
@@ -111,7 +113,7 @@ func (r *NetworkPolicyHandler) arePoliciesEqual(existingPolicy *v1.NetworkPolicy
 }
 
 func buildNetworkPolicyObjectForEndpoints(
-	endpoints *corev1.Endpoints, otterizeServiceName string, selector metav1.LabelSelector, ingressList *v1.IngressList, policyName string) *v1.NetworkPolicy {
+	endpoints *corev1.Endpoints, otterizeServiceName string, selector metav1.LabelSelector, ingressList *v1.IngressList, gatewayRoutes []string, policyName string) *v1.NetworkPolicy {
 	serviceSpecCopy := endpoints.Subsets
 
 	annotations := map[string]string{
@@ -124,6 +126,10 @@ func buildNetworkPolicyObjectForEndpoints(
 		}), ",")
 	}
 
+	if len(gatewayRoutes) != 0 {
+		annotations[v1alpha3.OtterizeCreatedForGatewayRouteAnnotation] = strings.Join(gatewayRoutes, ",")
+	}
+
 	netpol := &v1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      policyName,
@@ -288,14 +294,17 @@ func (r *NetworkPolicyHandler) handlePod(ctx context.Context, pod *corev1.Pod) e
 // HandleEndpoints
 // Every HandleX function goes through this function, and it handles this cases:
 // (1) Endpoints reconciler watch endpoints and call HandleEndpoints, which means it gets updates when Services are updated, or the pods backing them are updated.
-// (2) It receives handle requests from the IngressReconciler, when Ingresses are created, updated or deleted.
+// (2) It receives handle requests from the IngressReconciler and GatewayRouteReconciler, when Ingresses or Gateway API
+//
+//	routes are created, updated or deleted.
+//
 // (3) It receives handle requests from the Intents NetworkPolicyReconciler, when Network Policies that apply intents
 //
 //	are created, updated or deleted. This means that if you create, update or delete intents, the corresponding
 //	external traffic policy will be created (if there were no other intents affecting the service before then) or
 //	deleted (if no intents network policies refer to the pods backing the service any longer).
 //
-//	 When HandleEndpoints is called, and the Service is of type LoadBalancer, NodePort, or is referenced by an Ingress,
+//	 When HandleEndpoints is called, and the Service is of type LoadBalancer, NodePort, or is referenced by an Ingress or a Gateway API route,
 //		   it checks if the backing pods are affected by Otterize Intents Network Policies.
 //		   If so, and the reconciler is enabled, it will create network policies to allow external traffic to those pods.
 //		   If the Endpoints (= Services) update port, it will update the port specified in the corresponding network policy.
@@ -319,14 +328,19 @@ func (r *NetworkPolicyHandler) HandleEndpoints(ctx context.Context, endpoints *c
 		return errors.Wrap(err)
 	}
 
-	if !isServiceExternallyAccessible(svc, ingressList) {
+	gatewayRoutes, err := r.gatewayRoutes.getRoutesReferringToService(ctx, svc)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if !isServiceExternallyAccessible(svc, ingressList, gatewayRoutes) {
 		return r.handlePolicyDelete(ctx, r.formatPolicyName(svc.Name), svc.Namespace)
 	}
 
-	return r.handleEndpointsWithIngressList(ctx, endpoints, ingressList)
+	return r.handleEndpointsWithIngressList(ctx, endpoints, ingressList, gatewayRoutes)
 }
 
-func (r *NetworkPolicyHandler) handleEndpointsWithIngressList(ctx context.Context, endpoints *corev1.Endpoints, ingressList *v1.IngressList) error {
+func (r *NetworkPolicyHandler) handleEndpointsWithIngressList(ctx context.Context, endpoints *corev1.Endpoints, ingressList *v1.IngressList, gatewayRoutes []string) error {
 	addresses := r.getAddressesFromEndpoints(endpoints)
 	foundOtterizeNetpolsAffectingPods := false
 	for _, address := range addresses {
@@ -363,7 +377,7 @@ func (r *NetworkPolicyHandler) handleEndpointsWithIngressList(ctx context.Contex
 
 		if !hasIngressRules {
 			if r.allowExternalTraffic == allowexternaltraffic.Always {
-				err := r.handleNetpolsForOtterizeServiceWithoutIntents(ctx, endpoints, serverLabel, ingressList)
+				err := r.handleNetpolsForOtterizeServiceWithoutIntents(ctx, endpoints, serverLabel, ingressList, gatewayRoutes)
 				if err != nil {
 					return errors.Wrap(err)
 				}
@@ -375,7 +389,7 @@ func (r *NetworkPolicyHandler) handleEndpointsWithIngressList(ctx context.Contex
 		netpolSlice = append(netpolSlice, netpolList.Items...)
 
 		foundOtterizeNetpolsAffectingPods = true
-		err = r.handleNetpolsForOtterizeService(ctx, endpoints, serverLabel, ingressList, netpolSlice)
+		err = r.handleNetpolsForOtterizeService(ctx, endpoints, serverLabel, ingressList, gatewayRoutes, netpolSlice)
 		if err != nil {
 			return errors.Wrap(err)
 		}
@@ -455,7 +469,7 @@ func (r *NetworkPolicyHandler) handlePolicyDelete(ctx context.Context, policyNam
 	return nil
 }
 
-func (r *NetworkPolicyHandler) handleNetpolsForOtterizeService(ctx context.Context, endpoints *corev1.Endpoints, otterizeServiceName string, ingressList *v1.IngressList, netpolList []v1.NetworkPolicy) error {
+func (r *NetworkPolicyHandler) handleNetpolsForOtterizeService(ctx context.Context, endpoints *corev1.Endpoints, otterizeServiceName string, ingressList *v1.IngressList, gatewayRoutes []string, netpolList []v1.NetworkPolicy) error {
 	svc := &corev1.Service{}
 	err := r.client.Get(ctx, types.NamespacedName{Name: endpoints.Name, Namespace: endpoints.Namespace}, svc)
 	if err != nil {
@@ -474,7 +488,7 @@ func (r *NetworkPolicyHandler) handleNetpolsForOtterizeService(ctx context.Conte
 
 	for _, netpol := range netpolList {
 		successMsg := fmt.Sprintf(successMsgNetpolCreate, endpoints.GetName(), netpol.GetName())
-		err = r.createOrUpdateNetworkPolicy(ctx, endpoints, svc, otterizeServiceName, netpol.Spec.PodSelector, ingressList, successMsg)
+		err = r.createOrUpdateNetworkPolicy(ctx, endpoints, svc, otterizeServiceName, netpol.Spec.PodSelector, ingressList, gatewayRoutes, successMsg)
 
 		if err != nil {
 			return errors.Wrap(err)
@@ -483,7 +497,7 @@ func (r *NetworkPolicyHandler) handleNetpolsForOtterizeService(ctx context.Conte
 	return nil
 }
 
-func (r *NetworkPolicyHandler) handleNetpolsForOtterizeServiceWithoutIntents(ctx context.Context, endpoints *corev1.Endpoints, otterizeServiceName string, ingressList *v1.IngressList) error {
+func (r *NetworkPolicyHandler) handleNetpolsForOtterizeServiceWithoutIntents(ctx context.Context, endpoints *corev1.Endpoints, otterizeServiceName string, ingressList *v1.IngressList, gatewayRoutes []string) error {
 	svc := &corev1.Service{}
 	err := r.client.Get(ctx, types.NamespacedName{Name: endpoints.Name, Namespace: endpoints.Namespace}, svc)
 	if err != nil {
@@ -500,7 +514,7 @@ func (r *NetworkPolicyHandler) handleNetpolsForOtterizeServiceWithoutIntents(ctx
 		return nil
 	}
 
-	err = r.createOrUpdateNetworkPolicy(ctx, endpoints, svc, otterizeServiceName, metav1.LabelSelector{MatchLabels: svc.Spec.Selector}, ingressList, fmt.Sprintf("created external traffic network policy for service '%s'", endpoints.GetName()))
+	err = r.createOrUpdateNetworkPolicy(ctx, endpoints, svc, otterizeServiceName, metav1.LabelSelector{MatchLabels: svc.Spec.Selector}, ingressList, gatewayRoutes, fmt.Sprintf("created external traffic network policy for service '%s'", endpoints.GetName()))
 	if err != nil {
 		return errors.Wrap(err)
 	}
@@ -12,16 +12,18 @@ import (
 type ServiceUploaderImpl struct {
 	client.Client
 	otterizeClient operator_cloud_client.CloudClient
+	gatewayRoutes  *GatewayRoutes
 }
 
 type ServiceUploader interface {
 	UploadNamespaceServices(ctx context.Context, namespace string) error
 }
 
-func NewServiceUploader(client client.Client, otterizeClient operator_cloud_client.CloudClient) ServiceUploader {
+func NewServiceUploader(client client.Client, otterizeClient operator_cloud_client.CloudClient, gatewayRoutes *GatewayRoutes) ServiceUploader {
 	return &ServiceUploaderImpl{
 		Client:         client,
 		otterizeClient: otterizeClient,
+		gatewayRoutes:  gatewayRoutes,
 	}
 }
 
@@ -66,7 +68,12 @@ func (s *ServiceUploaderImpl) getExternalService(ctx context.Context, svc *corev
 		return graphqlclient.ExternallyAccessibleServiceInput{}, false, errors.Wrap(err)
 	}
 
-	externalService, isExternal, err := convertToCloudExternalService(svc, ingressList)
+	gatewayRoutes, err := s.gatewayRoutes.getRoutesReferringToService(ctx, svc)
+	if err != nil {
+		return graphqlclient.ExternallyAccessibleServiceInput{}, false, errors.Wrap(err)
+	}
+
+	externalService, isExternal, err := convertToCloudExternalService(svc, ingressList, gatewayRoutes)
 	if err != nil {
 		return graphqlclient.ExternallyAccessibleServiceInput{}, false, errors.Wrap(err)
 	}
@@ -6,12 +6,14 @@ import (
 	"github.com/otterize/intents-operator/src/shared/otterizecloud/graphqlclient"
 	otterizecloudmocks "github.com/otterize/intents-operator/src/shared/otterizecloud/mocks"
 	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	"testing"
 	"time"
 )
@@ -26,7 +28,7 @@ func (s *ServiceUploaderTestSuite) SetupTest() {
 	s.MocksSuiteBase.SetupTest()
 	controller := gomock.NewController(s.T())
 	s.otterizeClient = otterizecloudmocks.NewMockCloudClient(controller)
-	s.serviceUploader = NewServiceUploader(s.Client, s.otterizeClient)
+	s.serviceUploader = NewServiceUploader(s.Client, s.otterizeClient, NewGatewayRoutes(s.Client))
 }
 
 func (s *ServiceUploaderTestSuite) TearDownTest() {
@@ -179,8 +181,94 @@ func (s *ServiceUploaderTestSuite) TestUploadNamespaceServices() {
 	s.Require().NoError(err)
 }
 
+func (s *ServiceUploaderTestSuite) TestUploadNamespaceServicesReferredByHTTPRoute() {
+	serviceWithHTTPRouteName := "service-with-http-route"
+	serviceWithHTTPRoute := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceWithHTTPRouteName,
+			Namespace: testNamespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	services := &corev1.ServiceList{
+		Items: []corev1.Service{
+			serviceWithHTTPRoute,
+		},
+	}
+
+	httpRouteList := &gatewayv1.HTTPRouteList{
+		Items: []gatewayv1.HTTPRoute{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "http-route-1",
+					Namespace: testNamespace,
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Rules: []gatewayv1.HTTPRouteRule{
+						{
+							BackendRefs: []gatewayv1.HTTPBackendRef{
+								{
+									BackendRef: gatewayv1.BackendRef{
+										BackendObjectReference: gatewayv1.BackendObjectReference{
+											Name: gatewayv1.ObjectName(serviceWithHTTPRouteName),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	gatewayRoutes := NewGatewayRoutes(s.Client)
+	gatewayRoutes.installedKinds = lo.Filter(supportedGatewayRouteKinds, func(routeKind gatewayRouteKind, _ int) bool {
+		return routeKind.groupKind.Kind == "HTTPRoute"
+	})
+	s.serviceUploader = NewServiceUploader(s.Client, s.otterizeClient, gatewayRoutes)
+
+	emptyServicesList := corev1.ServiceList{}
+	s.Client.EXPECT().List(gomock.Any(), gomock.Eq(&emptyServicesList), gomock.Eq(client.InNamespace(testNamespace))).DoAndReturn(
+		func(ctx context.Context, list *corev1.ServiceList, opts ...client.ListOption) error {
+			services.DeepCopyInto(list)
+			return nil
+		})
+
+	emptyIngressesList := v1.IngressList{}
+	s.Client.EXPECT().List(
+		gomock.Any(),
+		gomock.Eq(&emptyIngressesList),
+		gomock.Eq(&client.MatchingFields{v1alpha3.IngressServiceNamesIndexField: serviceWithHTTPRouteName}),
+		gomock.Eq(&client.ListOptions{Namespace: testNamespace})).Return(nil)
+
+	emptyHTTPRouteList := gatewayv1.HTTPRouteList{}
+	s.Client.EXPECT().List(
+		gomock.Any(),
+		gomock.Eq(&emptyHTTPRouteList),
+		gomock.Eq(&client.MatchingFields{v1alpha3.HTTPRouteServiceNamesIndexField: testNamespace + "/" + serviceWithHTTPRouteName})).DoAndReturn(
+		func(ctx context.Context, list *gatewayv1.HTTPRouteList, opts ...client.ListOption) error {
+			httpRouteList.DeepCopyInto(list)
+			return nil
+		})
+
+	externalServiceInputList := []graphqlclient.ExternallyAccessibleServiceInput{
+		{
+			Namespace:         testNamespace,
+			ServerName:        serviceWithHTTPRouteName,
+			ReferredByIngress: true,
+			ServiceType:       graphqlclient.KubernetesServiceTypeClusterIp,
+		},
+	}
+	s.otterizeClient.EXPECT().ReportExternallyAccessibleServices(gomock.Any(), testNamespace, externalServiceInputList).Return(nil)
+	err := s.serviceUploader.UploadNamespaceServices(context.Background(), testNamespace)
+	s.Require().NoError(err)
+}
+
 func (s *ServiceUploaderTestSuite) TestDoNotUploadNamespaceServicesIfOtterizeClientIsNil() {
-	s.serviceUploader = NewServiceUploader(s.Client, nil)
+	s.serviceUploader = NewServiceUploader(s.Client, nil, NewGatewayRoutes(s.Client))
 	err := s.serviceUploader.UploadNamespaceServices(context.Background(), testNamespace)
 	s.Require().NoError(err)
 }
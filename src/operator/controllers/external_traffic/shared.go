@@ -31,16 +31,17 @@ func serviceNamesFromIngress(ingress *v1.Ingress) sets.Set[string] {
 	return serviceNames
 }
 
-func isServiceExternallyAccessible(svc *corev1.Service, referringIngressList *v1.IngressList) bool {
-	return svc.Spec.Type == corev1.ServiceTypeLoadBalancer || svc.Spec.Type == corev1.ServiceTypeNodePort || len(referringIngressList.Items) > 0
+func isServiceExternallyAccessible(svc *corev1.Service, referringIngressList *v1.IngressList, referringGatewayRoutes []string) bool {
+	return svc.Spec.Type == corev1.ServiceTypeLoadBalancer || svc.Spec.Type == corev1.ServiceTypeNodePort || len(referringIngressList.Items) > 0 || len(referringGatewayRoutes) > 0
 }
 
-func convertToCloudExternalService(svc *corev1.Service, referringIngressList *v1.IngressList) (graphqlclient.ExternallyAccessibleServiceInput, bool, error) {
-	if !isServiceExternallyAccessible(svc, referringIngressList) {
+func convertToCloudExternalService(svc *corev1.Service, referringIngressList *v1.IngressList, referringGatewayRoutes []string) (graphqlclient.ExternallyAccessibleServiceInput, bool, error) {
+	if !isServiceExternallyAccessible(svc, referringIngressList, referringGatewayRoutes) {
 		return graphqlclient.ExternallyAccessibleServiceInput{}, false, nil
 	}
 
-	ReferredByIngress := len(referringIngressList.Items) > 0
+	// Gateway API routes are reported as ingresses, as they serve the same purpose
+	ReferredByIngress := len(referringIngressList.Items) > 0 || len(referringGatewayRoutes) > 0
 	var cloudServiceType graphqlclient.KubernetesServiceType
 	switch svc.Spec.Type {
 	case corev1.ServiceTypeLoadBalancer:
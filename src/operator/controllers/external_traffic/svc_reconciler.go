@@ -25,7 +25,7 @@ type ServiceReconciler struct {
 }
 
 func NewServiceReconciler(client client.Client, extNetpolHandler *NetworkPolicyHandler, otterizeClient operator_cloud_client.CloudClient) *ServiceReconciler {
-	serviceUploader := NewServiceUploader(client, otterizeClient)
+	serviceUploader := NewServiceUploader(client, otterizeClient, extNetpolHandler.gatewayRoutes)
 	return &ServiceReconciler{
 		Client:           client,
 		extNetpolHandler: extNetpolHandler,
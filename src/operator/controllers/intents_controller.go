@@ -59,6 +59,12 @@ type EnforcementConfig struct {
 	EnableNetworkPolicy                  bool
 	EnableKafkaACL                       bool
 	EnableIstioPolicy                    bool
+	EnableIstioStrictMTLS                bool
+	EnableIstioSidecarEgress             bool
+	EnableIstioAmbient                   bool
+	IstioEgressGateway                   string
+	EnableIstioEgressGatewayPolicy       bool
+	EnableLinkerdPolicy                  bool
 	EnableDatabasePolicy                 bool
 	EnableEgressNetworkPolicyReconcilers bool
 	EnableAWSPolicy                      bool
@@ -77,6 +83,7 @@ func NewIntentsReconciler(
 	client client.Client,
 	scheme *runtime.Scheme,
 	kafkaServerStore kafkaacls.ServersStore,
+	kafkaIntentsAdminFactory kafkaacls.IntentsAdminFactoryFunction,
 	restrictToNamespaces []string,
 	enforcementConfig EnforcementConfig,
 	otterizeClient operator_cloud_client.CloudClient,
@@ -88,8 +95,9 @@ func NewIntentsReconciler(
 	serviceIdResolver := serviceidresolver.NewResolver(client)
 	reconcilers := []reconcilergroup.ReconcilerWithEvents{
 		intents_reconcilers.NewPodLabelReconciler(client, scheme),
-		intents_reconcilers.NewKafkaACLReconciler(client, scheme, kafkaServerStore, enforcementConfig.EnableKafkaACL, kafkaacls.NewKafkaIntentsAdmin, enforcementConfig.EnforcementDefaultState, operatorPodName, operatorPodNamespace, serviceIdResolver, enforcementConfig.EnforcedNamespaces),
-		intents_reconcilers.NewIstioPolicyReconciler(client, scheme, restrictToNamespaces, enforcementConfig.EnableIstioPolicy, enforcementConfig.EnforcementDefaultState, enforcementConfig.EnforcedNamespaces),
+		intents_reconcilers.NewKafkaACLReconciler(client, scheme, kafkaServerStore, enforcementConfig.EnableKafkaACL, kafkaIntentsAdminFactory, enforcementConfig.EnforcementDefaultState, operatorPodName, operatorPodNamespace, serviceIdResolver, enforcementConfig.EnforcedNamespaces),
+		intents_reconcilers.NewIstioPolicyReconciler(client, scheme, restrictToNamespaces, enforcementConfig.EnableIstioPolicy, enforcementConfig.EnableIstioSidecarEgress, enforcementConfig.EnableIstioAmbient, enforcementConfig.IstioEgressGateway, enforcementConfig.EnableIstioEgressGatewayPolicy, enforcementConfig.EnforcementDefaultState, enforcementConfig.EnforcedNamespaces),
+		intents_reconcilers.NewLinkerdPolicyReconciler(client, scheme, restrictToNamespaces, enforcementConfig.EnableLinkerdPolicy, enforcementConfig.EnforcementDefaultState, enforcementConfig.EnforcedNamespaces),
 	}
 	reconcilers = append(reconcilers, additionalReconcilers...)
 	reconcilersGroup := reconcilergroup.NewGroup(
@@ -182,6 +190,7 @@ func (r *IntentsReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		WithOptions(controller.Options{RecoverPanic: lo.ToPtr(true)}).
 		Watches(&otterizev1alpha3.ProtectedService{}, handler.EnqueueRequestsFromMapFunc(r.mapProtectedServiceToClientIntents)).
 		Watches(&corev1.Endpoints{}, handler.EnqueueRequestsFromMapFunc(r.watchApiServerEndpoint)).
+		Watches(&otterizev1alpha3.KafkaServerConfig{}, handler.EnqueueRequestsFromMapFunc(r.mapExternalKafkaServerConfigToClientIntents)).
 		Complete(r)
 	if err != nil {
 		return errors.Wrap(err)
@@ -228,6 +237,29 @@ func (r *IntentsReconciler) mapProtectedServiceToClientIntents(ctx context.Conte
 	return r.mapIntentsToRequests(intentsToReconcile)
 }
 
+// mapExternalKafkaServerConfigToClientIntents enqueues the intents to an external Kafka server, so that their egress
+// network policies follow changes to the IPs of the server.
+func (r *IntentsReconciler) mapExternalKafkaServerConfigToClientIntents(ctx context.Context, obj client.Object) []reconcile.Request {
+	kafkaServerConfig := obj.(*otterizev1alpha3.KafkaServerConfig)
+	if kafkaServerConfig.Spec.External == nil {
+		return nil
+	}
+
+	serverName := kafkaServerConfig.GetIntentTargetName()
+	var intentsToServer otterizev1alpha3.ClientIntentsList
+	err := r.client.List(
+		ctx,
+		&intentsToServer,
+		&client.MatchingFields{otterizev1alpha3.OtterizeTargetServerIndexField: serverName},
+	)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to list client intents for external Kafka server %s", serverName)
+		return nil
+	}
+
+	return r.mapIntentsToRequests(intentsToServer.Items)
+}
+
 func (r *IntentsReconciler) mapIntentsToRequests(intentsToReconcile []otterizev1alpha3.ClientIntents) []reconcile.Request {
 	requests := make([]reconcile.Request, 0)
 	for _, clientIntents := range intentsToReconcile {
@@ -274,6 +306,11 @@ func (r *IntentsReconciler) InitIntentsServerIndices(mgr ctrl.Manager) error {
 			}
 
 			for _, intent := range intents.GetCallsList() {
+				if intent.IsTargetExternalKafkaServer() {
+					// External Kafka servers are cluster-wide, so they are indexed by their alias alone.
+					res = append(res, intent.Name)
+					continue
+				}
 				if !intent.IsTargetServerKubernetesService() {
 					res = append(res, intent.GetServerFullyQualifiedName(intents.Namespace))
 				}
@@ -28,6 +28,7 @@ func (s *IntentsControllerTestSuite) SetupTest() {
 		scheme.Scheme,
 		nil,
 		nil,
+		nil,
 		EnforcementConfig{},
 		nil,
 		"",
@@ -12,6 +12,8 @@ const (
 	ReasonCreatedNetworkPolicies                     = "CreatedNetworkPolicies"
 	ReasonIstioPolicyCreationDisabled                = "IstioPolicyCreationDisabled"
 	ReasonRemovingIstioPolicyFailed                  = "RemovingIstioPolicyFailed"
+	ReasonLinkerdPolicyCreationDisabled              = "LinkerdPolicyCreationDisabled"
+	ReasonRemovingLinkerdPolicyFailed                = "RemovingLinkerdPolicyFailed"
 	ReasonPodsNotFound                               = "PodsNotFound"
 	ReasonIntentsFoundButNoServiceAccount            = "ReasonIntentsFoundButNoServiceAccount"
 	ReasonReconciledAWSPolicies                      = "ReasonReconciledAWSPolicies"
@@ -29,4 +31,5 @@ const (
 	ReasonCreatedInternetEgressNetworkPolicies       = "CreatedInternetEgressNetworkPolicies"
 	ReasonIntentToUnresolvedDns                      = "IntentToUnresolvedDns"
 	ReasonNetworkPolicyCreationFailedMissingIP       = "NetworkPolicyCreationFailedMissingIP"
+	ReasonExternalKafkaServerNotFound                = "ExternalKafkaServerNotFound"
 )
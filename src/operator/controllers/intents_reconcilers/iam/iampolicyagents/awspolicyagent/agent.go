@@ -4,6 +4,8 @@ import (
 	"context"
 	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
 	"github.com/otterize/intents-operator/src/shared/awsagent"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	"regexp"
 )
@@ -42,6 +44,11 @@ func (a *Agent) createPolicyFromIntents(intents []otterizev1alpha3.Intent) awsag
 	}
 
 	for _, intent := range intents {
+		if len(intent.AWSActions) == 0 {
+			// intents that only attach managed policies don't add statements
+			continue
+		}
+
 		awsResource := a.templateResourceName(intent.Name)
 		actions := intent.AWSActions
 
@@ -55,11 +62,32 @@ func (a *Agent) createPolicyFromIntents(intents []otterizev1alpha3.Intent) awsag
 	return policy
 }
 
+func (a *Agent) getManagedPolicyARNsFromIntents(intents []otterizev1alpha3.Intent) []string {
+	policyARNs := make([]string, 0)
+	for _, intent := range intents {
+		for _, policyARN := range intent.AWSManagedPolicies {
+			policyARNs = append(policyARNs, a.templateResourceName(policyARN))
+		}
+	}
+	return lo.Uniq(policyARNs)
+}
+
 func (a *Agent) AddRolePolicyFromIntents(ctx context.Context, namespace string, accountName string, intentsServiceName string, intents []otterizev1alpha3.Intent, _ corev1.Pod) error {
 	policyDoc := a.createPolicyFromIntents(intents)
-	return a.agent.AddRolePolicy(ctx, namespace, accountName, intentsServiceName, policyDoc.Statement)
+	err := a.agent.AddRolePolicy(ctx, namespace, accountName, intentsServiceName, policyDoc.Statement)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return a.agent.SetRoleManagedPolicies(ctx, namespace, accountName, intentsServiceName, a.getManagedPolicyARNsFromIntents(intents))
 }
 
 func (a *Agent) DeleteRolePolicyFromIntents(ctx context.Context, intents otterizev1alpha3.ClientIntents) error {
+	managedPolicyARNs := a.getManagedPolicyARNsFromIntents(intents.GetFilteredCallsList(otterizev1alpha3.IntentTypeAWS))
+	err := a.agent.DetachRoleManagedPolicies(ctx, intents.Namespace, intents.Spec.Service.Name, managedPolicyARNs)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
 	return a.agent.DeleteRolePolicyByNamespacedName(ctx, intents.Namespace, intents.Spec.Service.Name)
 }
@@ -47,6 +47,32 @@ func (a *AWSAgentPolicySuite) Test_createPolicyFromIntents_TemplateResourceName(
 
 }
 
+func (a *AWSAgentPolicySuite) Test_managedPoliciesFromIntents() {
+	// Given
+	agent := &Agent{
+		agent: &awsagent.Agent{
+			Region:    "test-region",
+			AccountID: "test-accountid",
+		},
+	}
+	intents := []otterizev1alpha3.Intent{
+		{
+			Name:       "arn:aws:sqs:$(AWS_REGION):$(AWS_ACCOUNT_ID):queue1",
+			AWSActions: []string{"sqs:SendMessage"},
+		},
+		{
+			Name:               "s3-read-only",
+			AWSManagedPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess", "arn:aws:iam::$(AWS_ACCOUNT_ID):policy/custom"},
+		},
+	}
+	// When
+	policyDoc := agent.createPolicyFromIntents(intents)
+	managedPolicyARNs := agent.getManagedPolicyARNsFromIntents(intents)
+	// Then
+	a.Len(policyDoc.Statement, 1)
+	a.Equal([]string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess", "arn:aws:iam::test-accountid:policy/custom"}, managedPolicyARNs)
+}
+
 func TestRunAWSAgentPolicySuite(t *testing.T) {
 	suite.Run(t, new(AWSAgentPolicySuite))
 }
@@ -16,15 +16,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// IstioPolicyReconciler keeps the Istio status of ClientIntents up to date and manages the sidecar and internet egress of
+// clients.
+// Authorization policies are created per server by istiopolicy.EffectivePolicyReconciler.
 type IstioPolicyReconciler struct {
 	client.Client
 	Scheme                    *runtime.Scheme
 	RestrictToNamespaces      []string
 	enableIstioPolicyCreation bool
+	enableSidecarEgress       bool
+	enableAmbient             bool
 	enforcementDefaultState   bool
 	injectablerecorder.InjectableRecorder
-	serviceIdResolver serviceidresolver.ServiceResolver
-	policyManager     istiopolicy.PolicyManager
+	serviceIdResolver     serviceidresolver.ServiceResolver
+	policyManager         istiopolicy.PolicyManager
+	sidecarManager        *istiopolicy.SidecarManager
+	internetEgressManager *istiopolicy.InternetEgressManager
 }
 
 func NewIstioPolicyReconciler(
@@ -32,6 +39,10 @@ func NewIstioPolicyReconciler(
 	s *runtime.Scheme,
 	restrictToNamespaces []string,
 	enableIstioPolicyCreation bool,
+	enableSidecarEgress bool,
+	enableAmbient bool,
+	istioEgressGateway string,
+	enableIstioEgressGatewayPolicy bool,
 	enforcementDefaultState bool,
 	enforcedNamespaces *goset.Set[string],
 ) *IstioPolicyReconciler {
@@ -40,12 +51,18 @@ func NewIstioPolicyReconciler(
 		Scheme:                    s,
 		RestrictToNamespaces:      restrictToNamespaces,
 		enableIstioPolicyCreation: enableIstioPolicyCreation,
+		enableSidecarEgress:       enableSidecarEgress,
+		enableAmbient:             enableAmbient,
 		enforcementDefaultState:   enforcementDefaultState,
 		serviceIdResolver:         serviceidresolver.NewResolver(c),
 	}
 
-	reconciler.policyManager = istiopolicy.NewPolicyManager(c, &reconciler.InjectableRecorder, restrictToNamespaces,
-		reconciler.enforcementDefaultState, reconciler.enableIstioPolicyCreation, enforcedNamespaces)
+	egressGateway := istiopolicy.ParseEgressGateway(istioEgressGateway)
+	reconciler.policyManager = istiopolicy.NewPolicyManager(c, &reconciler.InjectableRecorder)
+	reconciler.sidecarManager = istiopolicy.NewSidecarManager(c, &reconciler.InjectableRecorder, reconciler.serviceIdResolver,
+		restrictToNamespaces, egressGateway, reconciler.enforcementDefaultState, enforcedNamespaces)
+	reconciler.internetEgressManager = istiopolicy.NewInternetEgressManager(c, &reconciler.InjectableRecorder, restrictToNamespaces,
+		egressGateway, enableIstioEgressGatewayPolicy, reconciler.enforcementDefaultState)
 
 	return reconciler
 }
@@ -74,14 +91,11 @@ func (r *IstioPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	logrus.Debugf("Reconciling Istio authorization policies for service %s in namespace %s",
+	logrus.Debugf("Reconciling Istio status for service %s in namespace %s",
 		intents.Spec.Service.Name, req.Namespace)
 
 	if !intents.DeletionTimestamp.IsZero() {
-		err := r.policyManager.DeleteAll(ctx, intents)
-		if err != nil {
-			return ctrl.Result{}, errors.Wrap(err)
-		}
+		err := r.deleteEgressResources(ctx, intents)
 		if err != nil {
 			if k8serrors.IsConflict(err) {
 				return ctrl.Result{Requeue: true}, nil
@@ -108,7 +122,11 @@ func (r *IstioPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	clientServiceAccountName := pod.Spec.ServiceAccountName
-	missingSideCar := !istiopolicy.IsPodPartOfIstioMesh(pod)
+	isPodInMesh, err := istiopolicy.IsPodEnrolledInIstioMesh(ctx, r.Client, pod, r.enableAmbient)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+	missingSideCar := !isPodInMesh
 
 	err = r.policyManager.UpdateIntentsStatus(ctx, intents, clientServiceAccountName, missingSideCar)
 	if err != nil {
@@ -126,7 +144,11 @@ func (r *IstioPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	err = r.policyManager.Create(ctx, intents, clientServiceAccountName)
+	if !r.enableIstioPolicyCreation {
+		return ctrl.Result{}, nil
+	}
+
+	err = r.internetEgressManager.Create(ctx, intents, clientServiceAccountName)
 	if err != nil {
 		if k8serrors.IsConflict(err) {
 			return ctrl.Result{Requeue: true}, nil
@@ -134,9 +156,36 @@ func (r *IstioPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, errors.Wrap(err)
 	}
 
+	if r.enableSidecarEgress {
+		err = r.sidecarManager.Create(ctx, intents)
+		if err != nil {
+			if k8serrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, errors.Wrap(err)
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
+func (r *IstioPolicyReconciler) deleteEgressResources(ctx context.Context, intents *otterizev1alpha3.ClientIntents) error {
+	if !r.enableIstioPolicyCreation {
+		return nil
+	}
+
+	err := r.internetEgressManager.DeleteAll(ctx, intents)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if !r.enableSidecarEgress {
+		return nil
+	}
+
+	return r.sidecarManager.DeleteAll(ctx, intents)
+}
+
 func (r *IstioPolicyReconciler) updateServerSidecarStatus(ctx context.Context, intents *otterizev1alpha3.ClientIntents) error {
 	for _, intent := range intents.Spec.Calls {
 		serverNamespace := intent.GetTargetServerNamespace(intents.Namespace)
@@ -148,7 +197,11 @@ func (r *IstioPolicyReconciler) updateServerSidecarStatus(ctx context.Context, i
 			return errors.Wrap(err)
 		}
 
-		missingSideCar := !istiopolicy.IsPodPartOfIstioMesh(pod)
+		isPodInMesh, err := istiopolicy.IsPodEnrolledInIstioMesh(ctx, r.Client, pod, r.enableAmbient)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		missingSideCar := !isPodInMesh
 		formattedTargetServer := otterizev1alpha3.GetFormattedOtterizeIdentity(intent.GetTargetServerName(), serverNamespace)
 		err = r.policyManager.UpdateServerSidecar(ctx, intents, formattedTargetServer, missingSideCar)
 		if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/otterize/intents-operator/src/shared/testbase"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	"istio.io/client-go/pkg/apis/security/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,6 +42,10 @@ func (s *IstioPolicyReconcilerTestSuite) SetupTest() {
 		s.scheme,
 		restrictToNamespaces,
 		true,
+		false,
+		false,
+		"",
+		false,
 		true,
 		nil,
 	)
@@ -55,7 +60,7 @@ func (s *IstioPolicyReconcilerTestSuite) TearDownTest() {
 	s.MocksSuiteBase.TearDownTest()
 }
 
-func (s *IstioPolicyReconcilerTestSuite) TestCreatePolicy() {
+func (s *IstioPolicyReconcilerTestSuite) TestUpdateIstioStatus() {
 	clientIntentsName := "client-intents"
 	serviceName := "test-client"
 	serverNamespace := "far-far-away"
@@ -139,7 +144,7 @@ func (s *IstioPolicyReconcilerTestSuite) TestCreatePolicy() {
 	s.policyAdmin.EXPECT().UpdateIntentsStatus(gomock.Any(), gomock.Eq(&intentsObj), clientServiceAccount, false).Return(nil)
 	s.serviceResolver.EXPECT().ResolveIntentServerToPod(gomock.Any(), gomock.Eq(intentsObj.Spec.Calls[0]), serverNamespace).Return(serverPod, nil)
 	s.policyAdmin.EXPECT().UpdateServerSidecar(gomock.Any(), gomock.Eq(&intentsObj), "test-server-far-far-away-aa0d79", false).Return(nil)
-	s.policyAdmin.EXPECT().Create(gomock.Any(), gomock.Eq(&intentsObj), clientServiceAccount).Return(nil)
+	s.expectServiceEntryDeleted()
 	res, err := s.Reconciler.Reconcile(context.Background(), req)
 	s.NoError(err)
 	s.Empty(res)
@@ -150,17 +155,22 @@ func (s *IstioPolicyReconcilerTestSuite) expectValidatingIstioIsInstalled() {
 	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "authorizationpolicies.security.istio.io"}, gomock.Any()).Return(nil)
 }
 
+func (s *IstioPolicyReconcilerTestSuite) expectServiceEntryDeleted() {
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).Return(nil)
+}
+
 func (s *IstioPolicyReconcilerTestSuite) TestGlobalEnforcementDisabled() {
 	s.Reconciler.enforcementDefaultState = false
-	s.assertPolicyCreateCalledEvenIfDisabledEnforcementConfigHappensInPolicyManager()
+	s.expectServiceEntryDeleted()
+	s.assertStatusUpdatedEvenIfEnforcementDisabled()
 }
 
 func (s *IstioPolicyReconcilerTestSuite) TestIstioPolicyEnforcementDisabled() {
 	s.Reconciler.enableIstioPolicyCreation = false
-	s.assertPolicyCreateCalledEvenIfDisabledEnforcementConfigHappensInPolicyManager()
+	s.assertStatusUpdatedEvenIfEnforcementDisabled()
 }
 
-func (s *IstioPolicyReconcilerTestSuite) assertPolicyCreateCalledEvenIfDisabledEnforcementConfigHappensInPolicyManager() {
+func (s *IstioPolicyReconcilerTestSuite) assertStatusUpdatedEvenIfEnforcementDisabled() {
 	clientIntentsName := "client-intents"
 	serviceName := "test-client"
 	serverNamespace := "far-far-away"
@@ -242,7 +252,6 @@ func (s *IstioPolicyReconcilerTestSuite) assertPolicyCreateCalledEvenIfDisabledE
 	s.policyAdmin.EXPECT().UpdateIntentsStatus(gomock.Any(), gomock.Eq(&clientIntentsObj), clientServiceAccount, false).Return(nil)
 	s.serviceResolver.EXPECT().ResolveIntentServerToPod(gomock.Any(), gomock.Eq(clientIntentsObj.Spec.Calls[0]), serverNamespace).Return(serverPod, nil)
 	s.policyAdmin.EXPECT().UpdateServerSidecar(gomock.Any(), gomock.Eq(&clientIntentsObj), "test-server-far-far-away-aa0d79", false).Return(nil)
-	s.policyAdmin.EXPECT().Create(gomock.Any(), gomock.Eq(&clientIntentsObj), clientServiceAccount).Return(nil)
 
 	res, err := s.Reconciler.Reconcile(context.Background(), req)
 	s.NoError(err)
@@ -292,8 +301,8 @@ func (s *IstioPolicyReconcilerTestSuite) TestIstioPolicyDeleted() {
 			return nil
 		})
 
-	s.policyAdmin.EXPECT().DeleteAll(gomock.Any(), gomock.Eq(&clientIntentsObj)).Return(nil)
-
+	// Authorization policies are removed by the effective policy reconciler, so only the internet egress is removed here
+	s.expectServiceEntryDeleted()
 	res, err := s.Reconciler.Reconcile(context.Background(), req)
 	s.NoError(err)
 	s.Empty(res)
@@ -15,8 +15,11 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"slices"
+	"strings"
 )
 
 const (
@@ -27,6 +30,7 @@ const (
 	ReasonRemovingKafkaACLsFailed              = "RemovingKafkaACLsFailed"
 	ReasonApplyingKafkaACLsFailed              = "ApplyingKafkaACLsFailed"
 	ReasonAppliedKafkaACLs                     = "AppliedKafkaACLs"
+	ReasonCouldNotApplyKafkaQuota              = "CouldNotApplyKafkaQuota"
 	ReasonIntentsOperatorIdentityResolveFailed = "IntentsOperatorIdentityResolveFailed"
 )
 
@@ -81,6 +85,10 @@ func getIntentsByServer(defaultNamespace string, intents []otterizev1alpha3.Inte
 			Name:      intent.GetTargetServerName(),
 			Namespace: intent.GetTargetServerNamespace(defaultNamespace),
 		}
+		if intent.IsTargetExternalKafkaServer() {
+			// External servers are keyed by their cluster-wide alias, matching KafkaServerConfig.GetServerName.
+			serverName = types.NamespacedName{Name: intent.Name}
+		}
 
 		intentsByServer[serverName] = append(intentsByServer[serverName], intent)
 	}
@@ -88,7 +96,14 @@ func getIntentsByServer(defaultNamespace string, intents []otterizev1alpha3.Inte
 	return intentsByServer
 }
 
-func (r *KafkaACLReconciler) applyACLs(ctx context.Context, intents *otterizev1alpha3.ClientIntents) (serverCount int, err error) {
+func formatKafkaServerName(serverName types.NamespacedName) string {
+	if serverName.Namespace == "" {
+		return serverName.Name
+	}
+	return serverName.Name + "." + serverName.Namespace
+}
+
+func (r *KafkaACLReconciler) applyACLs(ctx context.Context, intents *otterizev1alpha3.ClientIntents) (serverCount int, appliedQuotas []otterizev1alpha3.AppliedKafkaQuota, err error) {
 	intentsByServer := getIntentsByServer(intents.Namespace, intents.Spec.Calls)
 
 	if err := r.KafkaServersStore.MapErr(func(serverName types.NamespacedName, config *otterizev1alpha3.KafkaServerConfig, tls otterizev1alpha3.TLSSource) error {
@@ -103,7 +118,7 @@ func (r *KafkaACLReconciler) applyACLs(ctx context.Context, intents *otterizev1a
 			r.RecordNormalEventf(intents, consts.ReasonEnforcementDefaultOff, "Enforcement is disabled globally and called service '%s' is not explicitly protected using a ProtectedService resource, Kafka ACL creation skipped", serverName.Name)
 			// Intentionally no return - KafkaIntentsAdminImpl skips the creation, but still needs to do deletion.
 		}
-		kafkaIntentsAdmin, err := r.getNewKafkaIntentsAdmin(*config, tls, r.enableKafkaACLCreation, shouldCreatePolicy)
+		kafkaIntentsAdmin, err := r.getNewKafkaIntentsAdmin(ctx, *config, tls, r.enableKafkaACLCreation, shouldCreatePolicy)
 		if err != nil {
 			err = errors.Errorf("failed to connect to Kafka server %s: %w", serverName, err)
 			r.RecordWarningEventf(intents, ReasonCouldNotConnectToKafkaServer, "Kafka ACL reconcile failed: %s", err.Error())
@@ -114,9 +129,20 @@ func (r *KafkaACLReconciler) applyACLs(ctx context.Context, intents *otterizev1a
 			r.RecordWarningEventf(intents, ReasonCouldNotApplyIntentsOnKafkaServer, "Kafka ACL reconcile failed: %s", err.Error())
 			return errors.Errorf("failed applying intents on kafka server %s: %w", serverName, err)
 		}
+
+		// The quota on the server is queried even if the intents have none, so that quotas left behind by a reconcile that
+		// failed before recording them in the status are removed as well.
+		appliedQuota, err := kafkaIntentsAdmin.ApplyClientQuota(intents.Spec.Service.Name, intents.Namespace, kafkaacls.GetClientQuota(intentsForServer))
+		if err != nil {
+			r.RecordWarningEventf(intents, ReasonCouldNotApplyKafkaQuota, "Kafka quota reconcile failed: %s", err.Error())
+			return errors.Errorf("failed applying quota on kafka server %s: %w", serverName, err)
+		}
+		if appliedQuota != nil {
+			appliedQuotas = append(appliedQuotas, otterizev1alpha3.AppliedKafkaQuota{Server: formatKafkaServerName(serverName), Quota: *appliedQuota})
+		}
 		return nil
 	}); err != nil {
-		return 0, errors.Wrap(err)
+		return 0, nil, errors.Wrap(err)
 	}
 
 	if !r.enableKafkaACLCreation {
@@ -130,7 +156,23 @@ func (r *KafkaACLReconciler) applyACLs(ctx context.Context, intents *otterizev1a
 		}
 	}
 
-	return len(intentsByServer), nil
+	return len(intentsByServer), appliedQuotas, nil
+}
+
+func (r *KafkaACLReconciler) updateKafkaQuotasStatus(ctx context.Context, intents *otterizev1alpha3.ClientIntents, appliedQuotas []otterizev1alpha3.AppliedKafkaQuota) error {
+	slices.SortFunc(appliedQuotas, func(a, b otterizev1alpha3.AppliedKafkaQuota) int {
+		return strings.Compare(a.Server, b.Server)
+	})
+	if (len(appliedQuotas) == 0 && len(intents.Status.KafkaQuotas) == 0) || reflect.DeepEqual(appliedQuotas, intents.Status.KafkaQuotas) {
+		return nil
+	}
+
+	intentsCopy := intents.DeepCopy()
+	intentsCopy.Status.KafkaQuotas = appliedQuotas
+	if err := r.client.Status().Patch(ctx, intentsCopy, client.MergeFrom(intents)); err != nil {
+		return errors.Wrap(err)
+	}
+	return nil
 }
 
 func (r *KafkaACLReconciler) RemoveACLs(ctx context.Context, intents *otterizev1alpha3.ClientIntents) error {
@@ -141,7 +183,7 @@ func (r *KafkaACLReconciler) RemoveACLs(ctx context.Context, intents *otterizev1
 		}
 
 		// We just pass shouldCreatePolicy to the KafkaIntentsAdmin - it determines whether to create or delete.
-		kafkaIntentsAdmin, err := r.getNewKafkaIntentsAdmin(*config, tls, r.enableKafkaACLCreation, shouldCreatePolicy)
+		kafkaIntentsAdmin, err := r.getNewKafkaIntentsAdmin(ctx, *config, tls, r.enableKafkaACLCreation, shouldCreatePolicy)
 		if err != nil {
 			return errors.Wrap(err)
 		}
@@ -150,6 +192,10 @@ func (r *KafkaACLReconciler) RemoveACLs(ctx context.Context, intents *otterizev1
 		if err := kafkaIntentsAdmin.RemoveClientIntents(intents.Spec.Service.Name, intents.Namespace); err != nil {
 			return errors.Errorf("failed removing intents from kafka server %s: %w", serverName, err)
 		}
+
+		if _, err := kafkaIntentsAdmin.ApplyClientQuota(intents.Spec.Service.Name, intents.Namespace, nil); err != nil {
+			return errors.Errorf("failed removing quota from kafka server %s: %w", serverName, err)
+		}
 		return nil
 	})
 }
@@ -214,12 +260,16 @@ func (r *KafkaACLReconciler) isIntentsForTheIntentsOperator(ctx context.Context,
 
 func (r *KafkaACLReconciler) applyAcls(ctx context.Context, logger *logrus.Entry, intents *otterizev1alpha3.ClientIntents) (ctrl.Result, error) {
 	logger.Debug("Applying new ACLs")
-	serverCount, err := r.applyACLs(ctx, intents)
+	serverCount, appliedQuotas, err := r.applyACLs(ctx, intents)
 	if err != nil {
 		r.RecordWarningEventf(intents, ReasonApplyingKafkaACLsFailed, "could not apply Kafka ACLs: %s", err.Error())
 		return ctrl.Result{}, errors.Wrap(err)
 	}
 
+	if err := r.updateKafkaQuotasStatus(ctx, intents, appliedQuotas); err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
 	if serverCount > 0 {
 		r.RecordNormalEventf(intents, ReasonAppliedKafkaACLs, "Kafka ACL reconcile complete, reconciled %d Kafka brokers", serverCount)
 	}
@@ -0,0 +1,75 @@
+package intents_reconcilers
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/kafkaacls"
+	kafkaaclsmocks "github.com/otterize/intents-operator/src/operator/controllers/kafkaacls/mocks"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+const externalKafkaServerAlias = "msk"
+
+type KafkaACLExternalServerTestSuite struct {
+	testbase.MocksSuiteBase
+	intentsAdmin  *kafkaaclsmocks.MockKafkaIntentsAdmin
+	aclReconciler *KafkaACLReconciler
+}
+
+func (s *KafkaACLExternalServerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.intentsAdmin = kafkaaclsmocks.NewMockKafkaIntentsAdmin(s.Controller)
+	factory := func(_ context.Context, config otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, _ bool, _ bool) (kafkaacls.KafkaIntentsAdmin, error) {
+		s.Require().Equal(externalKafkaServerAlias, config.Spec.External.Alias)
+		return s.intentsAdmin, nil
+	}
+	serversStore := kafkaacls.NewServersStore(otterizev1alpha3.TLSSource{}, true, factory, true)
+	serversStore.Add(&otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "msk-config", Namespace: kafkaServerNamespace},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			Addr:     "b-1.msk.amazonaws.com:9096",
+			External: &otterizev1alpha3.ExternalKafkaServer{Alias: externalKafkaServerAlias},
+		},
+	})
+
+	s.aclReconciler = NewKafkaACLReconciler(s.Client, &runtime.Scheme{}, serversStore, true, factory, true, "operator-pod", "otterize-system", nil, nil)
+	s.aclReconciler.InjectRecorder(s.Recorder)
+}
+
+func (s *KafkaACLExternalServerTestSuite) TestIntentsToAliasAppliedOnExternalServer() {
+	intents := otterizev1alpha3.ClientIntents{
+		ObjectMeta: metav1.ObjectMeta{Name: "client-intents", Namespace: testNamespace},
+		Spec: &otterizev1alpha3.IntentsSpec{
+			Service: otterizev1alpha3.Service{Name: "client"},
+			Calls: []otterizev1alpha3.Intent{{
+				Name:   otterizev1alpha3.ExternalKafkaServerPrefix + externalKafkaServerAlias,
+				Type:   otterizev1alpha3.IntentTypeKafka,
+				Topics: []otterizev1alpha3.KafkaTopic{{Name: "orders", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationConsume}}},
+			}},
+		},
+	}
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: intents.Name, Namespace: intents.Namespace}, gomock.AssignableToTypeOf(&otterizev1alpha3.ClientIntents{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, obj *otterizev1alpha3.ClientIntents, _ ...client.GetOption) error {
+			intents.DeepCopyInto(obj)
+			return nil
+		})
+	s.intentsAdmin.EXPECT().ApplyClientIntents("client", testNamespace, intents.Spec.Calls).Return(nil)
+	s.intentsAdmin.EXPECT().ApplyClientQuota("client", testNamespace, nil).Return(nil, nil)
+	s.intentsAdmin.EXPECT().Close()
+
+	_, err := s.aclReconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: intents.Name, Namespace: intents.Namespace}})
+	s.Require().NoError(err)
+	s.ExpectEvent(ReasonAppliedKafkaACLs)
+}
+
+func TestKafkaACLExternalServerTestSuite(t *testing.T) {
+	suite.Run(t, new(KafkaACLExternalServerTestSuite))
+}
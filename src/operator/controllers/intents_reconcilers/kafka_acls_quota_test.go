@@ -0,0 +1,142 @@
+package intents_reconcilers
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/kafkaacls"
+	kafkaaclsmocks "github.com/otterize/intents-operator/src/operator/controllers/kafkaacls/mocks"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+// statusPatchRecorder records the status patches of ClientIntents.
+type statusPatchRecorder struct {
+	client.SubResourceWriter
+	patched []*otterizev1alpha3.ClientIntents
+}
+
+func (r *statusPatchRecorder) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	r.patched = append(r.patched, obj.(*otterizev1alpha3.ClientIntents))
+	return nil
+}
+
+type KafkaACLQuotaTestSuite struct {
+	testbase.MocksSuiteBase
+	intentsAdmin  *kafkaaclsmocks.MockKafkaIntentsAdmin
+	statusWriter  *statusPatchRecorder
+	aclReconciler *KafkaACLReconciler
+}
+
+func (s *KafkaACLQuotaTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.intentsAdmin = kafkaaclsmocks.NewMockKafkaIntentsAdmin(s.Controller)
+	s.statusWriter = &statusPatchRecorder{}
+	factory := func(_ context.Context, _ otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, _ bool, _ bool) (kafkaacls.KafkaIntentsAdmin, error) {
+		return s.intentsAdmin, nil
+	}
+	serversStore := kafkaacls.NewServersStore(otterizev1alpha3.TLSSource{}, true, factory, true)
+	serversStore.Add(&otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-config", Namespace: kafkaServerNamespace},
+		Spec:       otterizev1alpha3.KafkaServerConfigSpec{Service: otterizev1alpha3.Service{Name: kafkaServerName}},
+	})
+
+	s.aclReconciler = NewKafkaACLReconciler(s.Client, &runtime.Scheme{}, serversStore, true, factory, true, "operator-pod", "otterize-system", nil, nil)
+	s.aclReconciler.InjectRecorder(s.Recorder)
+}
+
+func (s *KafkaACLQuotaTestSuite) expectGetIntents(intents otterizev1alpha3.ClientIntents) {
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: intents.Name, Namespace: intents.Namespace}, gomock.AssignableToTypeOf(&otterizev1alpha3.ClientIntents{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, obj *otterizev1alpha3.ClientIntents, _ ...client.GetOption) error {
+			intents.DeepCopyInto(obj)
+			return nil
+		})
+}
+
+func (s *KafkaACLQuotaTestSuite) reconcile() {
+	_, err := s.aclReconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "client-intents", Namespace: testNamespace}})
+	s.Require().NoError(err)
+	s.ExpectEvent(ReasonAppliedKafkaACLs)
+}
+
+func kafkaIntentsWithQuota(quota *otterizev1alpha3.KafkaQuota) otterizev1alpha3.ClientIntents {
+	return otterizev1alpha3.ClientIntents{
+		ObjectMeta: metav1.ObjectMeta{Name: "client-intents", Namespace: testNamespace},
+		Spec: &otterizev1alpha3.IntentsSpec{
+			Service: otterizev1alpha3.Service{Name: "client"},
+			Calls: []otterizev1alpha3.Intent{{
+				Name:   kafkaServerName + "." + kafkaServerNamespace,
+				Type:   otterizev1alpha3.IntentTypeKafka,
+				Topics: []otterizev1alpha3.KafkaTopic{{Name: "orders", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationProduce}}},
+				Quota:  quota,
+			}},
+		},
+	}
+}
+
+func (s *KafkaACLQuotaTestSuite) TestQuotaAppliedAndReportedInStatus() {
+	quota := &otterizev1alpha3.KafkaQuota{ProducerByteRate: lo.ToPtr(int64(1048576)), RequestPercentage: lo.ToPtr(int64(50))}
+	intents := kafkaIntentsWithQuota(quota)
+	s.expectGetIntents(intents)
+	s.intentsAdmin.EXPECT().ApplyClientIntents("client", testNamespace, intents.Spec.Calls).Return(nil)
+	s.intentsAdmin.EXPECT().ApplyClientQuota("client", testNamespace, quota).Return(quota, nil)
+	s.intentsAdmin.EXPECT().Close()
+	s.Client.EXPECT().Status().Return(s.statusWriter)
+
+	s.reconcile()
+
+	s.Require().Len(s.statusWriter.patched, 1)
+	s.Equal([]otterizev1alpha3.AppliedKafkaQuota{{Server: kafkaServerName + "." + kafkaServerNamespace, Quota: *quota}}, s.statusWriter.patched[0].Status.KafkaQuotas)
+}
+
+func (s *KafkaACLQuotaTestSuite) TestQuotaRemovedWhenNoLongerRequested() {
+	intents := kafkaIntentsWithQuota(nil)
+	intents.Status.KafkaQuotas = []otterizev1alpha3.AppliedKafkaQuota{{
+		Server: kafkaServerName + "." + kafkaServerNamespace,
+		Quota:  otterizev1alpha3.KafkaQuota{ConsumerByteRate: lo.ToPtr(int64(1024))},
+	}}
+	s.expectGetIntents(intents)
+	s.intentsAdmin.EXPECT().ApplyClientIntents("client", testNamespace, intents.Spec.Calls).Return(nil)
+	s.intentsAdmin.EXPECT().ApplyClientQuota("client", testNamespace, nil).Return(nil, nil)
+	s.intentsAdmin.EXPECT().Close()
+	s.Client.EXPECT().Status().Return(s.statusWriter)
+
+	s.reconcile()
+
+	s.Require().Len(s.statusWriter.patched, 1)
+	s.Empty(s.statusWriter.patched[0].Status.KafkaQuotas)
+}
+
+func (s *KafkaACLQuotaTestSuite) TestNoQuotaRequestedOrApplied() {
+	intents := kafkaIntentsWithQuota(nil)
+	s.expectGetIntents(intents)
+	s.intentsAdmin.EXPECT().ApplyClientIntents("client", testNamespace, intents.Spec.Calls).Return(nil)
+	// the server is queried for a quota left behind by a reconcile that failed before updating the status
+	s.intentsAdmin.EXPECT().ApplyClientQuota("client", testNamespace, nil).Return(nil, nil)
+	s.intentsAdmin.EXPECT().Close()
+
+	s.reconcile()
+
+	s.Empty(s.statusWriter.patched)
+}
+
+func (s *KafkaACLQuotaTestSuite) TestQuotaMissingFromStatusRemovedWithIntents() {
+	intents := kafkaIntentsWithQuota(nil)
+	s.intentsAdmin.EXPECT().RemoveClientIntents("client", testNamespace).Return(nil)
+	s.intentsAdmin.EXPECT().ApplyClientQuota("client", testNamespace, nil).Return(nil, nil)
+	s.intentsAdmin.EXPECT().Close()
+
+	err := s.aclReconciler.RemoveACLs(context.Background(), &intents)
+	s.Require().NoError(err)
+}
+
+func TestKafkaACLQuotaTestSuite(t *testing.T) {
+	suite.Run(t, new(KafkaACLQuotaTestSuite))
+}
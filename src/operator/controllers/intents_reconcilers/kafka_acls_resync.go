@@ -0,0 +1,126 @@
+package intents_reconcilers
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/protected_services"
+	"github.com/otterize/intents-operator/src/prometheus"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+// KafkaACLResyncer periodically compares the ACLs on each Kafka server with the intents of its clients, so that ACLs
+// edited outside the operator are detected even when no ClientIntents or KafkaServerConfig is reconciled. Drift is
+// exported as metrics, and is repaired only if repair is enabled.
+type KafkaACLResyncer struct {
+	aclReconciler *KafkaACLReconciler
+	interval      time.Duration
+	repair        bool
+}
+
+func NewKafkaACLResyncer(aclReconciler *KafkaACLReconciler, interval time.Duration, repair bool) *KafkaACLResyncer {
+	return &KafkaACLResyncer{
+		aclReconciler: aclReconciler,
+		interval:      interval,
+		repair:        repair,
+	}
+}
+
+// Start implements manager.Runnable, and runs only on the leader.
+func (r *KafkaACLResyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Resync(ctx); err != nil {
+				logrus.WithError(err).Error("Failed resyncing Kafka ACLs")
+			}
+		}
+	}
+}
+
+// Resync compares the ACLs on each Kafka server with the intents of its clients, and repairs the drift if enabled.
+// A failure on one server does not stop the resync of the others.
+func (r *KafkaACLResyncer) Resync(ctx context.Context) error {
+	intentsByServer, err := r.getIntentsByServerAndClient(ctx)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return r.aclReconciler.KafkaServersStore.MapErr(func(serverName types.NamespacedName, config *otterizev1alpha3.KafkaServerConfig, tls otterizev1alpha3.TLSSource) error {
+		logger := logrus.WithField("server", serverName)
+		if err := r.resyncServer(ctx, serverName, config, tls, intentsByServer[serverName]); err != nil {
+			logger.WithError(err).Error("Failed resyncing Kafka ACLs of server")
+		}
+		return nil
+	})
+}
+
+func (r *KafkaACLResyncer) resyncServer(
+	ctx context.Context,
+	serverName types.NamespacedName,
+	config *otterizev1alpha3.KafkaServerConfig,
+	tls otterizev1alpha3.TLSSource,
+	intentsByClient map[types.NamespacedName][]otterizev1alpha3.Intent,
+) error {
+	shouldCreatePolicy, err := protected_services.IsServerEnforcementEnabledDueToProtectionOrDefaultState(ctx, r.aclReconciler.client, serverName.Name, serverName.Namespace, r.aclReconciler.enforcementDefaultState, r.aclReconciler.activeNamespaces)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	kafkaIntentsAdmin, err := r.aclReconciler.getNewKafkaIntentsAdmin(ctx, *config, tls, r.aclReconciler.enableKafkaACLCreation, shouldCreatePolicy)
+	if err != nil {
+		return errors.Errorf("failed to connect to Kafka server %s: %w", serverName, err)
+	}
+	defer kafkaIntentsAdmin.Close()
+
+	missing, unexpected, err := kafkaIntentsAdmin.ResyncACLs(config.Spec.Topics, intentsByClient, r.repair)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	prometheus.SetKafkaACLDrift(serverName.Name, serverName.Namespace, missing, unexpected)
+	if r.repair {
+		prometheus.IncrementKafkaACLDriftRepaired(missing + unexpected)
+	}
+	return nil
+}
+
+// getIntentsByServerAndClient returns the Kafka intents of all clients, other than the intents operator, by server.
+func (r *KafkaACLResyncer) getIntentsByServerAndClient(ctx context.Context) (map[types.NamespacedName]map[types.NamespacedName][]otterizev1alpha3.Intent, error) {
+	var intentsList otterizev1alpha3.ClientIntentsList
+	if err := r.aclReconciler.client.List(ctx, &intentsList); err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	intentsByServer := map[types.NamespacedName]map[types.NamespacedName][]otterizev1alpha3.Intent{}
+	for i := range intentsList.Items {
+		intents := &intentsList.Items[i]
+		if intents.Spec == nil || r.aclReconciler.intentsObjectUnderDeletion(intents) {
+			continue
+		}
+
+		clientIsOperator, err := r.aclReconciler.isIntentsForTheIntentsOperator(ctx, intents)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if clientIsOperator {
+			continue
+		}
+
+		clientName := types.NamespacedName{Name: intents.Spec.Service.Name, Namespace: intents.Namespace}
+		for serverName, intentsForServer := range getIntentsByServer(intents.Namespace, intents.Spec.Calls) {
+			if intentsByServer[serverName] == nil {
+				intentsByServer[serverName] = map[types.NamespacedName][]otterizev1alpha3.Intent{}
+			}
+			intentsByServer[serverName][clientName] = append(intentsByServer[serverName][clientName], intentsForServer...)
+		}
+	}
+
+	return intentsByServer, nil
+}
@@ -0,0 +1,91 @@
+package intents_reconcilers
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/kafkaacls"
+	kafkaaclsmocks "github.com/otterize/intents-operator/src/operator/controllers/kafkaacls/mocks"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+	"time"
+)
+
+const (
+	kafkaServerName      = "kafka"
+	kafkaServerNamespace = "kafka-namespace"
+)
+
+type KafkaACLResyncerTestSuite struct {
+	testbase.MocksSuiteBase
+	intentsAdmin *kafkaaclsmocks.MockKafkaIntentsAdmin
+	resyncer     *KafkaACLResyncer
+}
+
+func (s *KafkaACLResyncerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.intentsAdmin = kafkaaclsmocks.NewMockKafkaIntentsAdmin(s.Controller)
+	factory := func(_ context.Context, _ otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, _ bool, _ bool) (kafkaacls.KafkaIntentsAdmin, error) {
+		return s.intentsAdmin, nil
+	}
+	serversStore := kafkaacls.NewServersStore(otterizev1alpha3.TLSSource{}, true, factory, true)
+	serversStore.Add(&otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-config", Namespace: kafkaServerNamespace},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			Service: otterizev1alpha3.Service{Name: kafkaServerName},
+			Topics:  []otterizev1alpha3.TopicConfig{{Topic: "*", Pattern: otterizev1alpha3.ResourcePatternTypeLiteral, ClientIdentityRequired: true, IntentsRequired: true}},
+		},
+	})
+
+	aclReconciler := NewKafkaACLReconciler(s.Client, &runtime.Scheme{}, serversStore, true, factory, true, "operator-pod", "otterize-system", nil, nil)
+	s.resyncer = NewKafkaACLResyncer(aclReconciler, time.Minute, true)
+}
+
+func (s *KafkaACLResyncerTestSuite) TestResyncComparesIntentsOfAllClients() {
+	kafkaIntent := otterizev1alpha3.Intent{
+		Name:   kafkaServerName + "." + kafkaServerNamespace,
+		Type:   otterizev1alpha3.IntentTypeKafka,
+		Topics: []otterizev1alpha3.KafkaTopic{{Name: "orders", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationConsume}}},
+	}
+	deletedAt := metav1.Now()
+	intentsList := []otterizev1alpha3.ClientIntents{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "client-intents", Namespace: testNamespace},
+			Spec: &otterizev1alpha3.IntentsSpec{
+				Service: otterizev1alpha3.Service{Name: "client"},
+				Calls:   []otterizev1alpha3.Intent{kafkaIntent, {Name: "server", Type: otterizev1alpha3.IntentTypeHTTP}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleted-intents", Namespace: testNamespace, DeletionTimestamp: &deletedAt},
+			Spec: &otterizev1alpha3.IntentsSpec{
+				Service: otterizev1alpha3.Service{Name: "deleted-client"},
+				Calls:   []otterizev1alpha3.Intent{kafkaIntent},
+			},
+		},
+	}
+
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&otterizev1alpha3.ClientIntentsList{})).DoAndReturn(
+		func(_ context.Context, list *otterizev1alpha3.ClientIntentsList, _ ...client.ListOption) error {
+			list.Items = intentsList
+			return nil
+		})
+	s.intentsAdmin.EXPECT().ResyncACLs(
+		[]otterizev1alpha3.TopicConfig{{Topic: "*", Pattern: otterizev1alpha3.ResourcePatternTypeLiteral, ClientIdentityRequired: true, IntentsRequired: true}},
+		map[types.NamespacedName][]otterizev1alpha3.Intent{{Name: "client", Namespace: testNamespace}: {kafkaIntent}},
+		true,
+	).Return(1, 2, nil)
+	s.intentsAdmin.EXPECT().Close()
+
+	err := s.resyncer.Resync(context.Background())
+	s.Require().NoError(err)
+}
+
+func TestKafkaACLResyncerTestSuite(t *testing.T) {
+	suite.Run(t, new(KafkaACLResyncerTestSuite))
+}
@@ -32,6 +32,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"testing"
+	"time"
 )
 
 const (
@@ -92,7 +93,7 @@ func (s *KafkaACLReconcilerTestSuite) setupServerStore(serviceName string) *kafk
 
 	serverConfig.SetNamespace(s.TestNamespace)
 	emptyTls := otterizev1alpha3.TLSSource{}
-	kafkaServersStore := kafkaacls.NewServersStore(emptyTls, true, kafkaacls.NewKafkaIntentsAdmin, true)
+	kafkaServersStore := kafkaacls.NewServersStore(emptyTls, true, kafkaacls.NewKafkaIntentsAdminFactory(s.Mgr.GetClient(), kafkaacls.NewClusterAdminPool(time.Minute, 1)), true)
 	kafkaServersStore.Add(serverConfig)
 	return kafkaServersStore
 }
@@ -107,6 +108,8 @@ func (s *KafkaACLReconcilerTestSuite) BeforeTest(_, testName string) {
 
 	controller := gomock.NewController(s.T())
 	s.mockKafkaAdmin = kafkaaclsmocks.NewMockClusterAdmin(controller)
+	// the intents of these tests request no quotas, but the quotas of their clients are still queried
+	s.mockKafkaAdmin.EXPECT().DescribeClientQuotas(gomock.Any(), true).Return(nil, nil).AnyTimes()
 	s.mockServiceResolver = intentsreconcilersmocks.NewMockServiceResolver(controller)
 
 	s.initKafkaIntentsAdmin(true, true)
@@ -136,7 +139,7 @@ func (s *KafkaACLReconcilerTestSuite) principal() string {
 }
 
 func getMockIntentsAdminFactory(clusterAdmin sarama.ClusterAdmin, usernameMapping string) kafkaacls.IntentsAdminFactoryFunction {
-	return func(kafkaServer otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementDefaultState bool) (kafkaacls.KafkaIntentsAdmin, error) {
+	return func(_ context.Context, kafkaServer otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementDefaultState bool) (kafkaacls.KafkaIntentsAdmin, error) {
 		return kafkaacls.NewKafkaIntentsAdminImpl(kafkaServer, clusterAdmin, usernameMapping, enableKafkaACLCreation, enforcementDefaultState), nil
 	}
 }
@@ -241,6 +244,7 @@ func (s *KafkaACLReconcilerTestSuite) TestKafkaACLGetCreatedAndUpdatedBasedOnInt
 	}
 
 	// Expected arguments sent to sarama for the produce-write
+	s.expectListResourceACLs()
 	s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil).Times(1)
 	s.mockKafkaAdmin.EXPECT().CreateACLs(MatchSaramaResource(aclForProduce)).Return(nil).Times(1)
 	s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{writeAcl}, nil).Times(1)
@@ -267,6 +271,7 @@ func (s *KafkaACLReconcilerTestSuite) TestKafkaACLGetCreatedAndUpdatedBasedOnInt
 	})
 
 	// Expected arguments sent to sarama for the consume-read
+	s.expectListResourceACLs()
 	s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{writeAcl}, nil).Times(1)
 	s.mockKafkaAdmin.EXPECT().CreateACLs(MatchSaramaResource(aclForConsume)).Return(nil).Times(1)
 	s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{aclFullList}, nil).Times(1)
@@ -310,6 +315,7 @@ func (s *KafkaACLReconcilerTestSuite) TestKafkaACLDeletedAfterIntentsRemoved() {
 
 	aclForConsume := []*sarama.ResourceAcls{&createACL}
 
+	s.expectListResourceACLs()
 	list1 := s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil).Times(1)
 	s.mockKafkaAdmin.EXPECT().CreateACLs(MatchSaramaResource(aclForConsume)).Return(nil)
 	list2 := s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{createACL}, nil).Times(1)
@@ -348,6 +354,16 @@ func (s *KafkaACLReconcilerTestSuite) TestKafkaACLDeletedAfterIntentsRemoved() {
 		Principal:                 lo.ToPtr(s.principal()),
 		Host:                      lo.ToPtr("*"),
 	}, true).Return(deleteResult, nil)
+	for _, resourceType := range []sarama.AclResourceType{sarama.AclResourceGroup, sarama.AclResourceTransactionalID, sarama.AclResourceCluster} {
+		s.mockKafkaAdmin.EXPECT().DeleteACL(sarama.AclFilter{
+			ResourceType:              resourceType,
+			ResourcePatternTypeFilter: sarama.AclPatternAny,
+			PermissionType:            sarama.AclPermissionAllow,
+			Operation:                 sarama.AclOperationAny,
+			Principal:                 lo.ToPtr(s.principal()),
+			Host:                      lo.ToPtr("*"),
+		}, true).Return(nil, nil)
+	}
 
 	s.mockKafkaAdmin.EXPECT().Close().Times(1)
 
@@ -363,6 +379,7 @@ func (s *KafkaACLReconcilerTestSuite) TestKafkaACLCreationDisabled() {
 	s.initKafkaIntentsAdmin(false, true)
 
 	// Expect only to check the ACL list and close, with not creation
+	s.expectListResourceACLs()
 	s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil).Times(2)
 	s.mockKafkaAdmin.EXPECT().Close().Times(1)
 
@@ -386,6 +403,7 @@ func (s *KafkaACLReconcilerTestSuite) TestKafkaACLEnforcementGloballyDisabled()
 	s.initKafkaIntentsAdmin(true, false)
 
 	// Expect only to check the ACL list and close, with not creation
+	s.expectListResourceACLs()
 	s.mockKafkaAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil).Times(2)
 	s.mockKafkaAdmin.EXPECT().Close().Times(1)
 
@@ -411,6 +429,20 @@ func (s *KafkaACLReconcilerTestSuite) TestKafkaACLEnforcementGloballyDisabled()
 	}
 }
 
+// expectListResourceACLs expects the queries for the consumer group, transactional ID and cluster ACLs of the client,
+// which have to be set before the expectations matching any ACL filter.
+func (s *KafkaACLReconcilerTestSuite) expectListResourceACLs() {
+	for _, resourceType := range []sarama.AclResourceType{sarama.AclResourceGroup, sarama.AclResourceTransactionalID, sarama.AclResourceCluster} {
+		s.mockKafkaAdmin.EXPECT().ListAcls(sarama.AclFilter{
+			ResourceType:              resourceType,
+			Principal:                 lo.ToPtr(s.principal()),
+			ResourcePatternTypeFilter: sarama.AclPatternAny,
+			PermissionType:            sarama.AclPermissionAllow,
+			Operation:                 sarama.AclOperationAny,
+		}).Return([]sarama.ResourceAcls{}, nil).Times(1)
+	}
+}
+
 func (s *KafkaACLReconcilerTestSuite) reconcile(namespacedName types.NamespacedName, expectLogsOnReQueue bool) {
 	res := ctrl.Result{Requeue: true}
 	var err error
@@ -0,0 +1,129 @@
+package intents_reconcilers
+
+import (
+	"context"
+	"github.com/amit7itz/goset"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/operator/controllers/linkerdpolicy"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type LinkerdPolicyReconciler struct {
+	client.Client
+	Scheme                      *runtime.Scheme
+	RestrictToNamespaces        []string
+	enableLinkerdPolicyCreation bool
+	enforcementDefaultState     bool
+	injectablerecorder.InjectableRecorder
+	serviceIdResolver serviceidresolver.ServiceResolver
+	policyManager     linkerdpolicy.PolicyManager
+}
+
+func NewLinkerdPolicyReconciler(
+	c client.Client,
+	s *runtime.Scheme,
+	restrictToNamespaces []string,
+	enableLinkerdPolicyCreation bool,
+	enforcementDefaultState bool,
+	enforcedNamespaces *goset.Set[string],
+) *LinkerdPolicyReconciler {
+	reconciler := &LinkerdPolicyReconciler{
+		Client:                      c,
+		Scheme:                      s,
+		RestrictToNamespaces:        restrictToNamespaces,
+		enableLinkerdPolicyCreation: enableLinkerdPolicyCreation,
+		enforcementDefaultState:     enforcementDefaultState,
+		serviceIdResolver:           serviceidresolver.NewResolver(c),
+	}
+
+	reconciler.policyManager = linkerdpolicy.NewPolicyManager(c, &reconciler.InjectableRecorder, reconciler.serviceIdResolver,
+		restrictToNamespaces, reconciler.enforcementDefaultState, reconciler.enableLinkerdPolicyCreation, enforcedNamespaces)
+
+	return reconciler
+}
+
+func (r *LinkerdPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	isLinkerdInstalled, err := linkerdpolicy.IsLinkerdPoliciesInstalled(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	if !isLinkerdInstalled {
+		logrus.Debug("Linkerd authorization policies CRD is not installed, Linkerd policy creation skipped")
+		return ctrl.Result{}, nil
+	}
+
+	intents := &otterizev1alpha3.ClientIntents{}
+	err = r.Get(ctx, req.NamespacedName, intents)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	if intents.Spec == nil {
+		return ctrl.Result{}, nil
+	}
+
+	logrus.Debugf("Reconciling Linkerd authorization policies for service %s in namespace %s",
+		intents.Spec.Service.Name, req.Namespace)
+
+	if !intents.DeletionTimestamp.IsZero() {
+		err := r.policyManager.DeleteAll(ctx, intents)
+		if err != nil {
+			if k8serrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			r.RecordWarningEventf(intents, consts.ReasonRemovingLinkerdPolicyFailed, "Could not remove Linkerd policies: %s", err.Error())
+			return ctrl.Result{}, errors.Wrap(err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	pod, err := r.serviceIdResolver.ResolveClientIntentToPod(ctx, *intents)
+	if err != nil {
+		if errors.Is(err, serviceidresolver.ErrPodNotFound) {
+			r.RecordWarningEventf(
+				intents,
+				consts.ReasonPodsNotFound,
+				"Could not find non-terminating pods for service %s in namespace %s. Intents could not be reconciled now, but will be reconciled if pods appear later.",
+				intents.Spec.Service.Name,
+				intents.Namespace)
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	clientServiceAccountName := pod.Spec.ServiceAccountName
+	err = r.policyManager.UpdateIntentsStatus(ctx, intents, clientServiceAccountName)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	// Without the proxy the client has no mesh identity, so policies referring to it would never match.
+	if !linkerdpolicy.IsPodPartOfLinkerdMesh(pod) {
+		r.RecordWarningEvent(intents, linkerdpolicy.ReasonMissingLinkerdProxy, "Client pod missing Linkerd proxy, will not create policies")
+		logrus.Debugf("Pod %s/%s does not have a Linkerd proxy, skipping Linkerd policy creation", pod.Namespace, pod.Name)
+		return ctrl.Result{}, nil
+	}
+
+	err = r.policyManager.Create(ctx, intents, clientServiceAccountName)
+	if err != nil {
+		if k8serrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -35,34 +35,6 @@ func (m *MockPolicyManager) EXPECT() *MockPolicyManagerMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockPolicyManager) Create(ctx context.Context, clientIntents *v1alpha3.ClientIntents, clientServiceAccount string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, clientIntents, clientServiceAccount)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Create indicates an expected call of Create.
-func (mr *MockPolicyManagerMockRecorder) Create(ctx, clientIntents, clientServiceAccount interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPolicyManager)(nil).Create), ctx, clientIntents, clientServiceAccount)
-}
-
-// DeleteAll mocks base method.
-func (m *MockPolicyManager) DeleteAll(ctx context.Context, clientIntents *v1alpha3.ClientIntents) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAll", ctx, clientIntents)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// DeleteAll indicates an expected call of DeleteAll.
-func (mr *MockPolicyManagerMockRecorder) DeleteAll(ctx, clientIntents interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockPolicyManager)(nil).DeleteAll), ctx, clientIntents)
-}
-
 // UpdateIntentsStatus mocks base method.
 func (m *MockPolicyManager) UpdateIntentsStatus(ctx context.Context, clientIntents *v1alpha3.ClientIntents, clientServiceAccount string, missingSideCar bool) error {
 	m.ctrl.T.Helper()
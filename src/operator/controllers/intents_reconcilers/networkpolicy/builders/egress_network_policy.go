@@ -25,7 +25,7 @@ func (r *EgressNetworkPolicyBuilder) buildNetworkPolicyEgressRules(ep effectivep
 		if call.Type != "" && call.Type != otterizev1alpha3.IntentTypeHTTP && call.Type != otterizev1alpha3.IntentTypeKafka {
 			continue
 		}
-		if call.IsTargetServerKubernetesService() {
+		if call.IsTargetServerKubernetesService() || call.IsTargetExternalKafkaServer() {
 			continue
 		}
 		egressRules = append(egressRules, v1.NetworkPolicyEgressRule{
@@ -0,0 +1,100 @@
+package builders
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+)
+
+// The ExternalKafkaEgressRulesBuilder creates network policies that allow egress traffic from pods to the IPs of Kafka
+// servers outside the cluster, targeted by Kafka intents through the alias of their KafkaServerConfig.
+type ExternalKafkaEgressRulesBuilder struct {
+	client.Client
+	injectablerecorder.InjectableRecorder
+}
+
+func NewExternalKafkaEgressRulesBuilder(c client.Client) *ExternalKafkaEgressRulesBuilder {
+	return &ExternalKafkaEgressRulesBuilder{Client: c}
+}
+
+func (r *ExternalKafkaEgressRulesBuilder) buildEgressRules(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy) ([]v1.NetworkPolicyEgressRule, error) {
+	rules := make([]v1.NetworkPolicyEgressRule, 0)
+
+	intents := lo.Filter(ep.Calls, func(intent otterizev1alpha3.Intent, _ int) bool {
+		return intent.IsTargetExternalKafkaServer()
+	})
+	if len(intents) == 0 {
+		return rules, nil
+	}
+
+	kafkaServerConfigs := otterizev1alpha3.KafkaServerConfigList{}
+	if err := r.List(ctx, &kafkaServerConfigs); err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	for _, intent := range intents {
+		alias := intent.GetTargetServerName()
+		kafkaServerConfig, found := lo.Find(kafkaServerConfigs.Items, func(config otterizev1alpha3.KafkaServerConfig) bool {
+			return config.Spec.External != nil && config.Spec.External.Alias == alias
+		})
+		if !found {
+			ep.ClientIntentsEventRecorder.RecordWarningEventf(consts.ReasonExternalKafkaServerNotFound, "no KafkaServerConfig found for external Kafka server %s", alias)
+			continue
+		}
+		if len(kafkaServerConfig.Spec.External.IPs) == 0 {
+			ep.ClientIntentsEventRecorder.RecordWarningEventf(consts.ReasonNetworkPolicyCreationFailedMissingIP, "no IPs configured for external Kafka server %s", alias)
+			continue
+		}
+
+		peers := make([]v1.NetworkPolicyPeer, 0)
+		for _, ip := range kafkaServerConfig.Spec.External.IPs {
+			cidr, err := getCIDR(ip)
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			peers = append(peers, v1.NetworkPolicyPeer{IPBlock: &v1.IPBlock{CIDR: cidr}})
+		}
+
+		rules = append(rules, v1.NetworkPolicyEgressRule{
+			To:    peers,
+			Ports: getExternalKafkaServerPorts(kafkaServerConfig),
+		})
+	}
+
+	return rules, nil
+}
+
+// getExternalKafkaServerPorts returns the ports of the external Kafka server, or the port of its address when none
+// are configured. An empty list allows all ports.
+func getExternalKafkaServerPorts(kafkaServerConfig otterizev1alpha3.KafkaServerConfig) []v1.NetworkPolicyPort {
+	ports := kafkaServerConfig.Spec.External.Ports
+	if len(ports) == 0 {
+		if _, portStr, err := net.SplitHostPort(kafkaServerConfig.Spec.Addr); err == nil {
+			if port, err := strconv.Atoi(portStr); err == nil {
+				ports = []int{port}
+			}
+		}
+	}
+
+	return lo.Map(ports, func(port int, _ int) v1.NetworkPolicyPort {
+		return v1.NetworkPolicyPort{
+			Port: &intstr.IntOrString{
+				Type:   intstr.Int,
+				IntVal: int32(port),
+			},
+		}
+	})
+}
+
+func (r *ExternalKafkaEgressRulesBuilder) Build(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy) ([]v1.NetworkPolicyEgressRule, error) {
+	return r.buildEgressRules(ctx, ep)
+}
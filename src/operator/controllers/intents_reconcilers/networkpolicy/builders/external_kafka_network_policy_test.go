@@ -0,0 +1,112 @@
+package builders
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver/serviceidentity"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	v1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+type ExternalKafkaEgressRulesBuilderTestSuite struct {
+	testbase.MocksSuiteBase
+	Builder *ExternalKafkaEgressRulesBuilder
+}
+
+func (s *ExternalKafkaEgressRulesBuilderTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.Builder = NewExternalKafkaEgressRulesBuilder(s.Client)
+}
+
+func (s *ExternalKafkaEgressRulesBuilderTestSuite) expectListKafkaServerConfigs(configs ...otterizev1alpha3.KafkaServerConfig) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&otterizev1alpha3.KafkaServerConfigList{})).DoAndReturn(
+		func(_ context.Context, list *otterizev1alpha3.KafkaServerConfigList, _ ...client.ListOption) error {
+			list.Items = configs
+			return nil
+		})
+}
+
+func (s *ExternalKafkaEgressRulesBuilderTestSuite) effectivePolicy(calls ...otterizev1alpha3.Intent) effectivepolicy.ServiceEffectivePolicy {
+	clientIntents := &otterizev1alpha3.ClientIntents{ObjectMeta: metav1.ObjectMeta{Name: "client-intents", Namespace: testNamespace}}
+	return effectivepolicy.ServiceEffectivePolicy{
+		Service:                    serviceidentity.ServiceIdentity{Name: "client", Namespace: testNamespace},
+		Calls:                      calls,
+		ClientIntentsEventRecorder: injectablerecorder.NewObjectEventRecorder(&injectablerecorder.InjectableRecorder{Recorder: s.Recorder}, clientIntents),
+	}
+}
+
+func externalKafkaServerConfig(alias string, ips []string, ports []int) otterizev1alpha3.KafkaServerConfig {
+	return otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: alias, Namespace: testServerNamespace},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			Addr:     "b-1.msk.amazonaws.com:9096",
+			External: &otterizev1alpha3.ExternalKafkaServer{Alias: alias, IPs: ips, Ports: ports},
+		},
+	}
+}
+
+func (s *ExternalKafkaEgressRulesBuilderTestSuite) TestBuildsIPBlockRulesForAlias() {
+	s.expectListKafkaServerConfigs(
+		externalKafkaServerConfig("other", []string{"10.1.0.0/16"}, nil),
+		externalKafkaServerConfig("msk", []string{"10.0.0.1", "10.0.1.0/24"}, nil),
+	)
+
+	rules, err := s.Builder.Build(context.Background(), s.effectivePolicy(
+		otterizev1alpha3.Intent{Name: "server", Type: otterizev1alpha3.IntentTypeKafka},
+		otterizev1alpha3.Intent{Name: otterizev1alpha3.ExternalKafkaServerPrefix + "msk", Type: otterizev1alpha3.IntentTypeKafka},
+	))
+	s.Require().NoError(err)
+	s.Equal([]v1.NetworkPolicyEgressRule{{
+		To: []v1.NetworkPolicyPeer{
+			{IPBlock: &v1.IPBlock{CIDR: "10.0.0.1/32"}},
+			{IPBlock: &v1.IPBlock{CIDR: "10.0.1.0/24"}},
+		},
+		Ports: []v1.NetworkPolicyPort{{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: 9096}}},
+	}}, rules)
+}
+
+func (s *ExternalKafkaEgressRulesBuilderTestSuite) TestConfiguredPortsOverrideAddrPort() {
+	s.expectListKafkaServerConfigs(externalKafkaServerConfig("msk", []string{"10.0.0.1"}, []int{9094, 9098}))
+
+	rules, err := s.Builder.Build(context.Background(), s.effectivePolicy(
+		otterizev1alpha3.Intent{Name: otterizev1alpha3.ExternalKafkaServerPrefix + "msk", Type: otterizev1alpha3.IntentTypeKafka},
+	))
+	s.Require().NoError(err)
+	s.Require().Len(rules, 1)
+	s.Equal([]v1.NetworkPolicyPort{
+		{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: 9094}},
+		{Port: &intstr.IntOrString{Type: intstr.Int, IntVal: 9098}},
+	}, rules[0].Ports)
+}
+
+func (s *ExternalKafkaEgressRulesBuilderTestSuite) TestUnknownAliasRecordsEvent() {
+	s.expectListKafkaServerConfigs()
+
+	rules, err := s.Builder.Build(context.Background(), s.effectivePolicy(
+		otterizev1alpha3.Intent{Name: otterizev1alpha3.ExternalKafkaServerPrefix + "msk", Type: otterizev1alpha3.IntentTypeKafka},
+	))
+	s.Require().NoError(err)
+	s.Empty(rules)
+	s.ExpectEvent(consts.ReasonExternalKafkaServerNotFound)
+}
+
+func (s *ExternalKafkaEgressRulesBuilderTestSuite) TestNoExternalIntentsSkipsListing() {
+	rules, err := s.Builder.Build(context.Background(), s.effectivePolicy(
+		otterizev1alpha3.Intent{Name: "server", Type: otterizev1alpha3.IntentTypeKafka},
+	))
+	s.Require().NoError(err)
+	s.Empty(rules)
+}
+
+func TestExternalKafkaEgressRulesBuilderTestSuite(t *testing.T) {
+	suite.Run(t, new(ExternalKafkaEgressRulesBuilderTestSuite))
+}
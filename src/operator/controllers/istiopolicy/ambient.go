@@ -0,0 +1,85 @@
+package istiopolicy
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	IstioDataplaneModeLabelKey        = "istio.io/dataplane-mode"
+	IstioDataplaneModeAmbient         = "ambient"
+	IstioDataplaneModeNone            = "none"
+	IstioUseWaypointLabelKey          = "istio.io/use-waypoint"
+	IstioUseWaypointNone              = "none"
+	IstioAmbientRedirectionAnnotation = "ambient.istio.io/redirection"
+	IstioAmbientRedirectionEnabled    = "enabled"
+)
+
+// IsPodInAmbientMesh checks whether the pod is captured by ztunnel. The CNI marks enrolled pods with an annotation; pods
+// that were not processed yet are matched by the dataplane-mode label on the pod, falling back to its namespace.
+func IsPodInAmbientMesh(ctx context.Context, k8sClient client.Client, pod corev1.Pod) (bool, error) {
+	if pod.Annotations[IstioAmbientRedirectionAnnotation] == IstioAmbientRedirectionEnabled {
+		return true, nil
+	}
+
+	if IsPodPartOfIstioMesh(pod) {
+		return false, nil
+	}
+
+	switch pod.Labels[IstioDataplaneModeLabelKey] {
+	case IstioDataplaneModeAmbient:
+		return true, nil
+	case IstioDataplaneModeNone:
+		return false, nil
+	}
+
+	namespace := corev1.Namespace{}
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: pod.Namespace}, &namespace)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+
+	return namespace.Labels[IstioDataplaneModeLabelKey] == IstioDataplaneModeAmbient, nil
+}
+
+// IsPodEnrolledInIstioMesh checks whether the pod's traffic is handled by Istio, either by a sidecar or - when ambient
+// support is enabled - by ztunnel.
+func IsPodEnrolledInIstioMesh(ctx context.Context, k8sClient client.Client, pod corev1.Pod, ambientEnabled bool) (bool, error) {
+	if IsPodPartOfIstioMesh(pod) {
+		return true, nil
+	}
+
+	if !ambientEnabled {
+		return false, nil
+	}
+
+	inAmbientMesh, err := IsPodInAmbientMesh(ctx, k8sClient, pod)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+
+	return inAmbientMesh, nil
+}
+
+// getWaypointName returns the waypoint that handles traffic to the service, which is set by a label on the service,
+// falling back to the namespace. An empty string is returned if the service does not use a waypoint.
+func getWaypointName(ctx context.Context, k8sClient client.Client, service corev1.Service) (string, error) {
+	waypoint, ok := service.Labels[IstioUseWaypointLabelKey]
+	if !ok {
+		namespace := corev1.Namespace{}
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: service.Namespace}, &namespace)
+		if err != nil {
+			return "", errors.Wrap(err)
+		}
+		waypoint = namespace.Labels[IstioUseWaypointLabelKey]
+	}
+
+	if waypoint == IstioUseWaypointNone {
+		return "", nil
+	}
+
+	return waypoint, nil
+}
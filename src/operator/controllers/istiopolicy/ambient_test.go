@@ -0,0 +1,182 @@
+package istiopolicy
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	serviceidresolvermocks "github.com/otterize/intents-operator/src/shared/serviceidresolver/mocks"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver/serviceidentity"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+type AmbientTestSuite struct {
+	testbase.MocksSuiteBase
+	serviceResolver *serviceidresolvermocks.MockServiceResolver
+	reconciler      *EffectivePolicyReconciler
+}
+
+func (s *AmbientTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.serviceResolver = serviceidresolvermocks.NewMockServiceResolver(s.Controller)
+	s.reconciler = NewEffectivePolicyReconciler(s.Client, []string{}, nil, true, true, true)
+	s.reconciler.InjectRecorder(s.Recorder)
+	s.reconciler.serviceIdResolver = s.serviceResolver
+}
+
+func (s *AmbientTestSuite) TearDownTest() {
+	s.reconciler = nil
+	s.serviceResolver = nil
+	s.MocksSuiteBase.TearDownTest()
+}
+
+func (s *AmbientTestSuite) expectGetNamespace(name string, labels map[string]string) {
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: name}, gomock.AssignableToTypeOf(&corev1.Namespace{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, namespace *corev1.Namespace, _ ...any) error {
+			namespace.Name = name
+			namespace.Labels = labels
+			return nil
+		})
+}
+
+func (s *AmbientTestSuite) ambientTestEffectivePolicy() effectivepolicy.ServiceEffectivePolicy {
+	clientIntents := &v1alpha3.ClientIntents{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "client-intents",
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{v1alpha3.OtterizeClientServiceAccountAnnotation: "test-client-sa"},
+		},
+		Spec: &v1alpha3.IntentsSpec{Service: v1alpha3.Service{Name: "test-client"}},
+	}
+	intent := v1alpha3.Intent{
+		Name:          "test-server",
+		Type:          v1alpha3.IntentTypeHTTP,
+		HTTPResources: []v1alpha3.HTTPResource{{Path: "/login", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet}}},
+	}
+
+	return effectivepolicy.ServiceEffectivePolicy{
+		Service: serviceidentity.ServiceIdentity{Name: "test-server", Namespace: "test-namespace"},
+		CalledBy: []effectivepolicy.ClientCall{
+			{
+				Service:             serviceidentity.ServiceIdentity{Name: "test-client", Namespace: "test-namespace"},
+				IntendedCall:        intent,
+				ClientIntents:       clientIntents,
+				ObjectEventRecorder: injectablerecorder.NewObjectEventRecorder(&injectablerecorder.InjectableRecorder{Recorder: s.Recorder}, clientIntents),
+			},
+		},
+	}
+}
+
+func (s *AmbientTestSuite) expectServerPod(pod corev1.Pod) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.PodList{}), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, list *corev1.PodList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, pod)
+			return nil
+		})
+}
+
+func (s *AmbientTestSuite) TestPodWithRedirectionAnnotationIsInAmbientMesh() {
+	pod := corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Namespace:   "test-namespace",
+		Annotations: map[string]string{IstioAmbientRedirectionAnnotation: IstioAmbientRedirectionEnabled},
+	}}
+
+	inAmbientMesh, err := IsPodInAmbientMesh(context.Background(), s.Client, pod)
+	s.NoError(err)
+	s.True(inAmbientMesh)
+}
+
+func (s *AmbientTestSuite) TestPodWithSidecarIsNotInAmbientMesh() {
+	pod := corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Namespace: "test-namespace"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: IstioProxyContainerName}}},
+	}
+
+	inAmbientMesh, err := IsPodInAmbientMesh(context.Background(), s.Client, pod)
+	s.NoError(err)
+	s.False(inAmbientMesh)
+}
+
+func (s *AmbientTestSuite) TestPodInAmbientNamespaceIsEnrolledInMesh() {
+	pod := corev1.Pod{ObjectMeta: v1.ObjectMeta{Namespace: "test-namespace"}}
+	s.expectGetNamespace("test-namespace", map[string]string{IstioDataplaneModeLabelKey: IstioDataplaneModeAmbient})
+
+	enrolled, err := IsPodEnrolledInIstioMesh(context.Background(), s.Client, pod, true)
+	s.NoError(err)
+	s.True(enrolled)
+}
+
+func (s *AmbientTestSuite) TestPodOptedOutOfAmbientNamespace() {
+	pod := corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Namespace: "test-namespace",
+		Labels:    map[string]string{IstioDataplaneModeLabelKey: IstioDataplaneModeNone},
+	}}
+
+	enrolled, err := IsPodEnrolledInIstioMesh(context.Background(), s.Client, pod, true)
+	s.NoError(err)
+	s.False(enrolled)
+}
+
+func (s *AmbientTestSuite) TestAmbientDisabledSkipsLookup() {
+	pod := corev1.Pod{ObjectMeta: v1.ObjectMeta{Namespace: "test-namespace"}}
+
+	enrolled, err := IsPodEnrolledInIstioMesh(context.Background(), s.Client, pod, false)
+	s.NoError(err)
+	s.False(enrolled)
+}
+
+func (s *AmbientTestSuite) TestWaypointPolicyTargetsServices() {
+	ep := s.ambientTestEffectivePolicy()
+	serverPod := corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Name:        "test-server-pod",
+		Namespace:   "test-namespace",
+		Annotations: map[string]string{IstioAmbientRedirectionAnnotation: IstioAmbientRedirectionEnabled},
+	}}
+	s.expectServerPod(serverPod)
+	s.serviceResolver.EXPECT().GetKubernetesServicesTargetingPod(gomock.Any(), &serverPod).Return([]corev1.Service{
+		{ObjectMeta: v1.ObjectMeta{Name: "test-server-svc", Namespace: "test-namespace", Labels: map[string]string{IstioUseWaypointLabelKey: "waypoint"}}},
+	}, nil)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Nil(policy.Spec.Selector)
+	s.Require().Len(policy.Spec.TargetRefs, 1)
+	s.Equal("Service", policy.Spec.TargetRefs[0].Kind)
+	s.Equal("test-server-svc", policy.Spec.TargetRefs[0].Name)
+	s.Len(policy.Spec.Rules[0].To, 1)
+	s.ExpectNoEvent()
+}
+
+func (s *AmbientTestSuite) TestAmbientWithoutWaypointDropsHTTPRules() {
+	ep := s.ambientTestEffectivePolicy()
+	serverPod := corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Name:        "test-server-pod",
+		Namespace:   "test-namespace",
+		Annotations: map[string]string{IstioAmbientRedirectionAnnotation: IstioAmbientRedirectionEnabled},
+	}}
+	s.expectServerPod(serverPod)
+	s.serviceResolver.EXPECT().GetKubernetesServicesTargetingPod(gomock.Any(), &serverPod).Return([]corev1.Service{
+		{ObjectMeta: v1.ObjectMeta{Name: "test-server-svc", Namespace: "test-namespace"}},
+	}, nil)
+	s.expectGetNamespace("test-namespace", nil)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.NotNil(policy.Spec.Selector)
+	s.Empty(policy.Spec.TargetRefs)
+	s.Nil(policy.Spec.Rules[0].To)
+	s.ExpectEvent(ReasonAmbientServerNoWaypoint)
+}
+
+func TestAmbientTestSuite(t *testing.T) {
+	suite.Run(t, new(AmbientTestSuite))
+}
@@ -0,0 +1,585 @@
+package istiopolicy
+
+import (
+	"context"
+	"fmt"
+	"github.com/amit7itz/goset"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/protected_services"
+	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver/serviceidentity"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	v1beta1security "istio.io/api/security/v1beta1"
+	v1beta1type "istio.io/api/type/v1beta1"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strconv"
+)
+
+const (
+	OtterizeIstioPolicyNameTemplate = "authorization-policy-to-%s"
+	IstioDryRunAnnotation           = "istio.io/dry-run"
+)
+
+//+kubebuilder:rbac:groups="security.istio.io",resources=authorizationpolicies,verbs=get;update;patch;list;watch;delete;create;deletecollection
+
+// EffectivePolicyReconciler creates a single AuthorizationPolicy for every server, allowing all of the clients that
+// call it. Clients are identified by the service account and sidecar status saved on their ClientIntents.
+// In shadow mode, policies of servers that are not enforced are created in dry-run mode: Envoy only logs and reports
+// the requests they would have denied.
+type EffectivePolicyReconciler struct {
+	client.Client
+	injectablerecorder.InjectableRecorder
+	serviceIdResolver         serviceidresolver.ServiceResolver
+	restrictToNamespaces      []string
+	enforcedNamespaces        *goset.Set[string]
+	enableIstioPolicyCreation bool
+	enforcementDefaultState   bool
+	enableAmbient             bool
+}
+
+func NewEffectivePolicyReconciler(
+	c client.Client,
+	restrictToNamespaces []string,
+	enforcedNamespaces *goset.Set[string],
+	enableIstioPolicyCreation bool,
+	enforcementDefaultState bool,
+	enableAmbient bool,
+) *EffectivePolicyReconciler {
+	return &EffectivePolicyReconciler{
+		Client:                    c,
+		serviceIdResolver:         serviceidresolver.NewResolver(c),
+		restrictToNamespaces:      restrictToNamespaces,
+		enforcedNamespaces:        enforcedNamespaces,
+		enableIstioPolicyCreation: enableIstioPolicyCreation,
+		enforcementDefaultState:   enforcementDefaultState,
+		enableAmbient:             enableAmbient,
+	}
+}
+
+func (r *EffectivePolicyReconciler) InjectRecorder(recorder record.EventRecorder) {
+	r.Recorder = recorder
+}
+
+// ReconcileEffectivePolicies applies the authorization policies of the effective policies, removes the ones of servers
+// that no longer need them and returns the number of policies that exist.
+func (r *EffectivePolicyReconciler) ReconcileEffectivePolicies(ctx context.Context, eps []effectivepolicy.ServiceEffectivePolicy) (int, []error) {
+	isIstioInstalled, err := IsIstioAuthorizationPoliciesInstalled(ctx, r.Client)
+	if err != nil {
+		return 0, []error{errors.Wrap(err)}
+	}
+
+	if !isIstioInstalled {
+		logrus.Debug("Authorization policies CRD is not installed, Istio policy creation skipped")
+		return 0, nil
+	}
+
+	currentPolicies := goset.NewSet[types.NamespacedName]()
+	currentRequestAuthentications := goset.NewSet[types.NamespacedName]()
+	currentRateLimits := goset.NewSet[types.NamespacedName]()
+	errorList := make([]error, 0)
+	for _, ep := range eps {
+		applied, created, err := r.applyServiceEffectivePolicy(ctx, ep)
+		if err != nil {
+			errorList = append(errorList, errors.Wrap(err))
+			continue
+		}
+		if created {
+			currentPolicies.Add(applied.authorizationPolicy)
+			if applied.requestAuthentication != nil {
+				currentRequestAuthentications.Add(*applied.requestAuthentication)
+			}
+			if applied.rateLimit != nil {
+				currentRateLimits.Add(*applied.rateLimit)
+			}
+		}
+	}
+	if len(errorList) > 0 {
+		return 0, errorList
+	}
+
+	err = r.removeAuthorizationPoliciesThatShouldNotExist(ctx, currentPolicies)
+	if err != nil {
+		return currentPolicies.Len(), []error{errors.Wrap(err)}
+	}
+
+	err = r.removeRequestAuthenticationsThatShouldNotExist(ctx, currentRequestAuthentications)
+	if err != nil {
+		return currentPolicies.Len(), []error{errors.Wrap(err)}
+	}
+
+	err = r.removeRateLimitsThatShouldNotExist(ctx, currentRateLimits)
+	if err != nil {
+		return currentPolicies.Len(), []error{errors.Wrap(err)}
+	}
+
+	return currentPolicies.Len(), nil
+}
+
+// appliedIstioObjects are the Istio objects applied for a single server.
+type appliedIstioObjects struct {
+	authorizationPolicy   types.NamespacedName
+	requestAuthentication *types.NamespacedName
+	rateLimit             *types.NamespacedName
+}
+
+func (r *EffectivePolicyReconciler) applyServiceEffectivePolicy(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy) (appliedIstioObjects, bool, error) {
+	if len(ep.CalledBy) == 0 {
+		return appliedIstioObjects{}, false, nil
+	}
+
+	if !r.enableIstioPolicyCreation {
+		ep.RecordOnClientsNormalEvent(consts.ReasonIstioPolicyCreationDisabled, "Istio policy creation is disabled, creation skipped")
+		return appliedIstioObjects{}, false, nil
+	}
+
+	if len(r.restrictToNamespaces) != 0 && !lo.Contains(r.restrictToNamespaces, ep.Service.Namespace) {
+		ep.RecordOnClientsWarningEventf(ReasonNamespaceNotAllowed, "Namespace %s was specified in intent, but is not allowed by configuration, Istio policy ignored", ep.Service.Namespace)
+		return appliedIstioObjects{}, false, nil
+	}
+
+	serverJWT, err := r.getServerJWTRequirement(ctx, ep)
+	if err != nil {
+		return appliedIstioObjects{}, false, errors.Wrap(err)
+	}
+
+	newPolicy, shouldCreate, err := r.buildAuthorizationPolicy(ctx, ep, serverJWT)
+	if err != nil {
+		ep.RecordOnClientsWarningEventf(ReasonCreatingIstioPolicyFailed, "Failed to create Istio policy: %s", err.Error())
+		return appliedIstioObjects{}, false, errors.Wrap(err)
+	}
+	if !shouldCreate {
+		return appliedIstioObjects{}, false, nil
+	}
+
+	shouldEnforce, err := protected_services.IsServerEnforcementEnabledDueToProtectionOrDefaultState(ctx, r.Client, ep.Service.Name, ep.Service.Namespace, r.enforcementDefaultState, r.enforcedNamespaces)
+	if err != nil {
+		return appliedIstioObjects{}, false, errors.Wrap(err)
+	}
+
+	if !shouldEnforce {
+		logrus.Debugf("Enforcement is disabled globally and server is not explicitly protected, creating dry-run Istio policy for server %s in namespace %s", ep.Service.Name, ep.Service.Namespace)
+		ep.RecordOnClientsNormalEventf(consts.ReasonEnforcementDefaultOff, "Enforcement is disabled globally and called service '%s' is not explicitly protected using a ProtectedService resource, Istio policy created in dry-run mode", ep.Service.Name)
+		newPolicy.Annotations = map[string]string{IstioDryRunAnnotation: "true"}
+	}
+
+	policyName, err := r.applyAuthorizationPolicy(ctx, ep, newPolicy)
+	if err != nil {
+		return appliedIstioObjects{}, false, errors.Wrap(err)
+	}
+
+	applied := appliedIstioObjects{authorizationPolicy: policyName}
+	// Request authentications and rate limits reject requests on their own, so they are only applied once the server
+	// is enforced - dry-run policies must not affect traffic.
+	if !shouldEnforce {
+		return applied, true, nil
+	}
+
+	requestAuthentication, shouldCreate := buildRequestAuthentication(ep, newPolicy, serverJWT)
+	if shouldCreate {
+		requestAuthenticationName, err := r.applyRequestAuthentication(ctx, ep, requestAuthentication)
+		if err != nil {
+			return appliedIstioObjects{}, false, errors.Wrap(err)
+		}
+		applied.requestAuthentication = &requestAuthenticationName
+	}
+
+	rateLimitName, shouldCreate, err := r.applyServerRateLimit(ctx, ep, newPolicy)
+	if err != nil {
+		return appliedIstioObjects{}, false, errors.Wrap(err)
+	}
+	if shouldCreate {
+		applied.rateLimit = &rateLimitName
+	}
+
+	return applied, true, nil
+}
+
+func (r *EffectivePolicyReconciler) applyAuthorizationPolicy(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, newPolicy *v1beta1.AuthorizationPolicy) (types.NamespacedName, error) {
+	policyName := types.NamespacedName{Name: newPolicy.Name, Namespace: newPolicy.Namespace}
+	existingPolicy := &v1beta1.AuthorizationPolicy{}
+	err := r.Get(ctx, policyName, existingPolicy)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		ep.RecordOnClientsWarningEventf(ReasonGettingIstioPolicyFailed, "Could not get Istio policies: %s", err.Error())
+		return types.NamespacedName{}, errors.Wrap(err)
+	}
+
+	if k8serrors.IsNotFound(err) {
+		err = r.Create(ctx, newPolicy)
+		if err != nil {
+			ep.RecordOnClientsWarningEventf(ReasonCreatingIstioPolicyFailed, "Failed to create Istio policy: %s", err.Error())
+			return types.NamespacedName{}, errors.Wrap(err)
+		}
+		ep.RecordOnClientsNormalEventf(ReasonCreatedIstioPolicy, "Istio policy created for %s", ep.Service.Name)
+		return policyName, nil
+	}
+
+	err = r.updatePolicy(ctx, ep, existingPolicy, newPolicy)
+	if err != nil {
+		return types.NamespacedName{}, errors.Wrap(err)
+	}
+
+	return policyName, nil
+}
+
+func (r *EffectivePolicyReconciler) updatePolicy(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, existingPolicy *v1beta1.AuthorizationPolicy, newPolicy *v1beta1.AuthorizationPolicy) error {
+	sameDryRun := existingPolicy.Annotations[IstioDryRunAnnotation] == newPolicy.Annotations[IstioDryRunAnnotation]
+	if proto.Equal(&existingPolicy.Spec, &newPolicy.Spec) && reflect.DeepEqual(existingPolicy.Labels, newPolicy.Labels) && sameDryRun {
+		return nil
+	}
+
+	policyCopy := existingPolicy.DeepCopy()
+	policyCopy.Labels = newPolicy.Labels
+	if dryRun, ok := newPolicy.Annotations[IstioDryRunAnnotation]; ok {
+		if policyCopy.Annotations == nil {
+			policyCopy.Annotations = make(map[string]string)
+		}
+		policyCopy.Annotations[IstioDryRunAnnotation] = dryRun
+	} else {
+		// Enforcing the policy - remove only our annotation, others may have been set by users.
+		delete(policyCopy.Annotations, IstioDryRunAnnotation)
+	}
+	policyCopy.Spec.Selector = newPolicy.Spec.Selector
+	policyCopy.Spec.TargetRefs = newPolicy.Spec.TargetRefs
+	policyCopy.Spec.Action = newPolicy.Spec.Action
+	policyCopy.Spec.Rules = newPolicy.Spec.Rules
+
+	err := r.Patch(ctx, policyCopy, client.MergeFrom(existingPolicy))
+	if err != nil {
+		ep.RecordOnClientsWarningEventf(ReasonUpdatingIstioPolicyFailed, "Failed to update Istio policy: %s", err.Error())
+		return errors.Wrap(err)
+	}
+
+	ep.RecordOnClientsNormalEventf(ReasonCreatedIstioPolicy, "Istio policy updated for %s", ep.Service.Name)
+	return nil
+}
+
+// buildAuthorizationPolicy builds the policy of the server. serverJWT is the token every request to the server must
+// carry, if its ProtectedService requires one.
+func (r *EffectivePolicyReconciler) buildAuthorizationPolicy(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, serverJWT *v1alpha3.JWTRequirement) (*v1beta1.AuthorizationPolicy, bool, error) {
+	rules := r.buildRules(ep)
+	if len(rules) == 0 {
+		return nil, false, nil
+	}
+
+	podSelector, shouldCreate, err := r.buildPodSelector(ctx, ep)
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+	if !shouldCreate {
+		return nil, false, nil
+	}
+
+	policy := &v1beta1.AuthorizationPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf(OtterizeIstioPolicyNameTemplate, ep.Service.GetNameWithKind()),
+			Namespace: ep.Service.Namespace,
+			Labels: map[string]string{
+				v1alpha3.OtterizeServiceLabelKey: ep.Service.GetFormattedOtterizeIdentity(),
+			},
+		},
+		Spec: v1beta1security.AuthorizationPolicy{
+			Selector: &v1beta1type.WorkloadSelector{MatchLabels: podSelector},
+			Action:   v1beta1security.AuthorizationPolicy_ALLOW,
+			Rules:    rules,
+		},
+	}
+	applyServerJWTRequirement(policy, serverJWT)
+
+	err = r.applyAmbientEnrollment(ctx, ep, policy)
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+
+	return policy, true, nil
+}
+
+// principalAccess accumulates the access granted to a single client principal.
+type principalAccess struct {
+	wholeServer bool
+	operations  []*v1beta1security.Rule_To
+	// conditionalRules hold resources limited to specific header values or tokens. Istio conditions and request
+	// principals apply to a whole rule, so each of these resources gets a rule of its own.
+	conditionalRules []*v1beta1security.Rule
+}
+
+// buildRules creates a rule for every client service account. Clients whose service account is not known yet, or that
+// are not part of the mesh, cannot be identified by Istio and are skipped - the IstioPolicyReconciler reports them.
+func (r *EffectivePolicyReconciler) buildRules(ep effectivepolicy.ServiceEffectivePolicy) []*v1beta1security.Rule {
+	accessByPrincipal := make(map[string]*principalAccess)
+	for _, clientCall := range ep.CalledBy {
+		intent := clientCall.IntendedCall
+		if intent.Type != "" && intent.Type != v1alpha3.IntentTypeHTTP {
+			continue
+		}
+
+		principal, ok := clientPrincipal(clientCall)
+		if !ok {
+			continue
+		}
+
+		access, found := accessByPrincipal[principal]
+		if !found {
+			access = &principalAccess{}
+			accessByPrincipal[principal] = access
+		}
+
+		if intent.Type != v1alpha3.IntentTypeHTTP || len(intent.HTTPResources) == 0 {
+			// Intents that are not limited to HTTP resources grant access to the whole server.
+			access.wholeServer = true
+			continue
+		}
+
+		for _, resource := range intent.HTTPResources {
+			operation := intentsHTTPResourceToIstioOperation(resource)
+			if len(resource.Headers) == 0 && resource.JWT == nil {
+				access.operations = append(access.operations, operation)
+				continue
+			}
+			access.conditionalRules = append(access.conditionalRules, &v1beta1security.Rule{
+				To: []*v1beta1security.Rule_To{operation},
+				From: []*v1beta1security.Rule_From{
+					{
+						Source: &v1beta1security.Source{
+							Principals:        []string{principal},
+							RequestPrincipals: jwtRequirementToRequestPrincipals(resource.JWT),
+						},
+					},
+				},
+				When: append(intentsHTTPHeadersToIstioConditions(resource.Headers), jwtRequirementToIstioConditions(resource.JWT)...),
+			})
+		}
+	}
+
+	principals := lo.Keys(accessByPrincipal)
+	sort.Strings(principals)
+
+	rules := make([]*v1beta1security.Rule, 0, len(principals))
+	for _, principal := range principals {
+		access := accessByPrincipal[principal]
+		from := []*v1beta1security.Rule_From{
+			{
+				Source: &v1beta1security.Source{
+					Principals: []string{principal},
+				},
+			},
+		}
+
+		if access.wholeServer {
+			rules = append(rules, &v1beta1security.Rule{From: from})
+			continue
+		}
+
+		if len(access.operations) != 0 {
+			rules = append(rules, &v1beta1security.Rule{To: access.operations, From: from})
+		}
+		rules = append(rules, access.conditionalRules...)
+	}
+
+	return rules
+}
+
+// clientPrincipal returns the Istio identity of the client, if it is known and the client is part of the mesh.
+func clientPrincipal(clientCall effectivepolicy.ClientCall) (string, bool) {
+	clientIntents := clientCall.ClientIntents
+	serviceAccountName, ok := clientIntents.Annotations[v1alpha3.OtterizeClientServiceAccountAnnotation]
+	if !ok || serviceAccountName == "" || clientIntents.Annotations[v1alpha3.OtterizeMissingSidecarAnnotation] == "true" {
+		return "", false
+	}
+
+	return fmt.Sprintf("cluster.local/ns/%s/sa/%s", clientIntents.Namespace, serviceAccountName), true
+}
+
+func (r *EffectivePolicyReconciler) buildPodSelector(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy) (map[string]string, bool, error) {
+	if ep.Service.Kind == serviceidentity.KindService {
+		svc := corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: ep.Service.Name, Namespace: ep.Service.Namespace}, &svc)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, false, nil
+			}
+			return nil, false, errors.Wrap(err)
+		}
+		if svc.Spec.Selector == nil {
+			// Services without a selector don't select pods a policy could apply to. This is not an error, so that it
+			// does not prevent the rest of the policies from being reconciled.
+			ep.RecordOnClientsWarningEventf(ReasonServiceWithoutSelector, "Service %s/%s has no selector, Istio policy not created", svc.Namespace, svc.Name)
+			return nil, false, nil
+		}
+		return svc.Spec.Selector, true, nil
+	}
+
+	return map[string]string{
+		v1alpha3.OtterizeServiceLabelKey: ep.Service.GetFormattedOtterizeIdentity(),
+	}, true, nil
+}
+
+// applyAmbientEnrollment adapts the policy to how the server is enrolled in the mesh. Sidecar servers enforce the whole
+// policy themselves. In ambient mode, traffic to services that use a waypoint reaches the server from the waypoint's
+// identity, so the policy targets those services and is enforced by the waypoint instead. Ambient servers without a
+// waypoint are only handled by ztunnel, which enforces L4 rules only, so HTTP resources are dropped.
+func (r *EffectivePolicyReconciler) applyAmbientEnrollment(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, policy *v1beta1.AuthorizationPolicy) error {
+	if !r.enableAmbient {
+		return nil
+	}
+
+	pod, found, err := r.getServerPod(ctx, ep.Service.Namespace, policy.Spec.Selector.MatchLabels)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if !found {
+		return nil
+	}
+
+	inAmbientMesh, err := IsPodInAmbientMesh(ctx, r.Client, pod)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	if !inAmbientMesh {
+		return nil
+	}
+
+	services, err := r.getServerKubernetesServices(ctx, ep, pod)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	servicesWithWaypoint := sets.New[string]()
+	for _, service := range services {
+		waypoint, err := getWaypointName(ctx, r.Client, service)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if waypoint != "" {
+			servicesWithWaypoint.Insert(service.Name)
+		}
+	}
+
+	if servicesWithWaypoint.Len() != 0 {
+		policy.Spec.Selector = nil
+		policy.Spec.TargetRefs = lo.Map(sets.List(servicesWithWaypoint), func(serviceName string, _ int) *v1beta1type.PolicyTargetReference {
+			return &v1beta1type.PolicyTargetReference{Group: "", Kind: "Service", Name: serviceName}
+		})
+		return nil
+	}
+
+	hasHTTPRules := lo.SomeBy(policy.Spec.Rules, func(rule *v1beta1security.Rule) bool {
+		return len(rule.To) != 0 || len(rule.When) != 0 || len(rule.From[0].Source.RequestPrincipals) != 0
+	})
+	if hasHTTPRules {
+		ep.RecordOnClientsWarningEventf(ReasonAmbientServerNoWaypoint,
+			"Server %s is in the Istio ambient mesh without a waypoint, so HTTP resources cannot be enforced and access is granted to the whole server",
+			ep.Service.Name)
+		for _, rule := range policy.Spec.Rules {
+			rule.To = nil
+			rule.When = nil
+			rule.From[0].Source.RequestPrincipals = nil
+		}
+	}
+
+	return nil
+}
+
+func (r *EffectivePolicyReconciler) getServerPod(ctx context.Context, namespace string, podSelector map[string]string) (corev1.Pod, bool, error) {
+	var pods corev1.PodList
+	err := r.List(ctx, &pods, client.MatchingLabels(podSelector), client.InNamespace(namespace))
+	if err != nil {
+		return corev1.Pod{}, false, errors.Wrap(err)
+	}
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil {
+			return pod, true, nil
+		}
+	}
+
+	return corev1.Pod{}, false, nil
+}
+
+func (r *EffectivePolicyReconciler) getServerKubernetesServices(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, pod corev1.Pod) ([]corev1.Service, error) {
+	if ep.Service.Kind != serviceidentity.KindService {
+		services, err := r.serviceIdResolver.GetKubernetesServicesTargetingPod(ctx, &pod)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		return services, nil
+	}
+
+	svc := corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: ep.Service.Name, Namespace: ep.Service.Namespace}, &svc)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return []corev1.Service{svc}, nil
+}
+
+func (r *EffectivePolicyReconciler) removeAuthorizationPoliciesThatShouldNotExist(ctx context.Context, policiesThatShouldExist *goset.Set[types.NamespacedName]) error {
+	var existingPolicies v1beta1.AuthorizationPolicyList
+	err := r.List(ctx, &existingPolicies, client.HasLabels{v1alpha3.OtterizeServiceLabelKey})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	for _, policy := range existingPolicies.Items {
+		if policiesThatShouldExist.Contains(types.NamespacedName{Name: policy.Name, Namespace: policy.Namespace}) {
+			continue
+		}
+
+		logrus.Debugf("Removing orphaned Istio policy: %s in namespace %s", policy.Name, policy.Namespace)
+		err = r.Delete(ctx, policy)
+		if client.IgnoreNotFound(err) != nil {
+			r.RecordWarningEventf(policy, ReasonDeleteIstioPolicyFailed, "Failed to delete Istio policy: %s", err.Error())
+			return errors.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+func intentsHTTPResourceToIstioOperation(resource v1alpha3.HTTPResource) *v1beta1security.Rule_To {
+	// Istio paths and hosts support the same exact, prefix, suffix and presence matching as intents, so they are
+	// passed as is.
+	operation := &v1beta1security.Operation{
+		Methods: intentsMethodsToIstioMethods(resource.Methods),
+		Paths:   []string{resource.Path},
+		Hosts:   resource.Hosts,
+	}
+	if resource.Port != 0 {
+		operation.Ports = []string{strconv.Itoa(resource.Port)}
+	}
+
+	return &v1beta1security.Rule_To{Operation: operation}
+}
+
+func intentsHTTPHeadersToIstioConditions(headers []v1alpha3.HTTPHeader) []*v1beta1security.Condition {
+	return lo.Map(headers, func(header v1alpha3.HTTPHeader, _ int) *v1beta1security.Condition {
+		return &v1beta1security.Condition{
+			Key:    fmt.Sprintf("request.headers[%s]", header.Name),
+			Values: header.Values,
+		}
+	})
+}
+
+func intentsMethodsToIstioMethods(intent []v1alpha3.HTTPMethod) []string {
+	istioMethods := make([]string, 0, len(intent))
+	for _, method := range intent {
+		// Istio documentation specifies "A list of methods as specified in the HTTP request" in uppercase
+		istioMethods = append(istioMethods, string(method))
+	}
+
+	return istioMethods
+}
@@ -0,0 +1,923 @@
+package istiopolicy
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver/serviceidentity"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	v1beta12 "istio.io/api/security/v1beta1"
+	istionetworking "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+	"testing"
+)
+
+const (
+	effectivePolicyNamespace = "test-namespace"
+	effectivePolicyServer    = "test-server"
+	formattedEffectiveServer = "test-server-test-namespace-8ddecb"
+)
+
+type EffectivePolicyReconcilerTestSuite struct {
+	testbase.MocksSuiteBase
+	reconciler *EffectivePolicyReconciler
+	scheme     *runtime.Scheme
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.scheme = runtime.NewScheme()
+	s.scheme.AddKnownTypeWithName(schema.GroupVersionKind{Group: "security.istio.io", Version: "v1", Kind: "authorizationpolicies"}, &v1beta1.AuthorizationPolicy{})
+	s.reconciler = NewEffectivePolicyReconciler(s.Client, []string{}, nil, true, true, false)
+	s.reconciler.InjectRecorder(s.Recorder)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TearDownTest() {
+	s.reconciler = nil
+	s.MocksSuiteBase.TearDownTest()
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) expectIstioInstalled() {
+	s.Client.EXPECT().Scheme().Return(s.scheme)
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "authorizationpolicies.security.istio.io"}, gomock.Any()).Return(nil)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) expectListExistingPolicies(existing ...*v1beta1.AuthorizationPolicy) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicyList{}), client.HasLabels{v1alpha3.OtterizeServiceLabelKey}).DoAndReturn(
+		func(_ context.Context, list *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, existing...)
+			return nil
+		})
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) expectListExistingRequestAuthentications(existing ...*v1beta1.RequestAuthentication) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.RequestAuthenticationList{}), client.HasLabels{v1alpha3.OtterizeServiceLabelKey}).DoAndReturn(
+		func(_ context.Context, list *v1beta1.RequestAuthenticationList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, existing...)
+			return nil
+		})
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) expectListExistingRateLimits(existing ...*istionetworking.EnvoyFilter) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&istionetworking.EnvoyFilterList{}), client.HasLabels{v1alpha3.OtterizeServiceLabelKey}).DoAndReturn(
+		func(_ context.Context, list *istionetworking.EnvoyFilterList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, existing...)
+			return nil
+		})
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) expectServerJWT(jwt *v1alpha3.JWTRequirement) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1alpha3.ProtectedServiceList{}), client.MatchingFields{v1alpha3.OtterizeProtectedServiceNameIndexField: effectivePolicyServer}, client.InNamespace(effectivePolicyNamespace)).DoAndReturn(
+		func(_ context.Context, list *v1alpha3.ProtectedServiceList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, v1alpha3.ProtectedService{Spec: v1alpha3.ProtectedServiceSpec{Name: effectivePolicyServer, JWT: jwt}})
+			return nil
+		})
+}
+
+func effectivePolicyClient(name string, serviceAccount string, missingSidecar bool, calls ...v1alpha3.Intent) *v1alpha3.ClientIntents {
+	return &v1alpha3.ClientIntents{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name + "-intents",
+			Namespace: effectivePolicyNamespace,
+			Annotations: map[string]string{
+				v1alpha3.OtterizeClientServiceAccountAnnotation: serviceAccount,
+				v1alpha3.OtterizeMissingSidecarAnnotation:       strconv.FormatBool(missingSidecar),
+			},
+		},
+		Spec: &v1alpha3.IntentsSpec{
+			Service: v1alpha3.Service{Name: name},
+			Calls:   calls,
+		},
+	}
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) buildEffectivePolicy(server serviceidentity.ServiceIdentity, clients ...*v1alpha3.ClientIntents) effectivepolicy.ServiceEffectivePolicy {
+	recorder := &injectablerecorder.InjectableRecorder{Recorder: s.Recorder}
+	ep := effectivepolicy.ServiceEffectivePolicy{Service: server}
+	for _, clientIntents := range clients {
+		for _, intent := range clientIntents.GetCallsList() {
+			ep.CalledBy = append(ep.CalledBy, effectivepolicy.ClientCall{
+				Service:             serviceidentity.ServiceIdentity{Name: clientIntents.GetServiceName(), Namespace: clientIntents.Namespace},
+				IntendedCall:        intent,
+				ClientIntents:       clientIntents,
+				ObjectEventRecorder: injectablerecorder.NewObjectEventRecorder(recorder, clientIntents),
+			})
+		}
+	}
+	return ep
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestCreateSinglePolicyForAllClients() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-b", "client-b-sa", false, v1alpha3.Intent{
+			Name:          effectivePolicyServer,
+			Type:          v1alpha3.IntentTypeHTTP,
+			HTTPResources: []v1alpha3.HTTPResource{{Path: "/login", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet}}},
+		}),
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	policyName := types.NamespacedName{Name: "authorization-policy-to-test-server", Namespace: effectivePolicyNamespace}
+	s.Client.EXPECT().Get(gomock.Any(), policyName, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+
+	var createdPolicy *v1beta1.AuthorizationPolicy
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ ...client.CreateOption) error {
+			createdPolicy = policy
+			return nil
+		})
+
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicyList{}), client.HasLabels{v1alpha3.OtterizeServiceLabelKey}).DoAndReturn(
+		func(_ context.Context, list *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, createdPolicy)
+			return nil
+		})
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Equal(1, count)
+
+	s.Require().NotNil(createdPolicy)
+	s.Equal(formattedEffectiveServer, createdPolicy.Labels[v1alpha3.OtterizeServiceLabelKey])
+	s.Equal(formattedEffectiveServer, createdPolicy.Spec.Selector.MatchLabels[v1alpha3.OtterizeServiceLabelKey])
+	s.Require().Len(createdPolicy.Spec.Rules, 2)
+	s.Equal(generatePrincipal(effectivePolicyNamespace, "client-a-sa"), createdPolicy.Spec.Rules[0].From[0].Source.Principals[0])
+	s.Nil(createdPolicy.Spec.Rules[0].To)
+	s.Equal(generatePrincipal(effectivePolicyNamespace, "client-b-sa"), createdPolicy.Spec.Rules[1].From[0].Source.Principals[0])
+	s.Require().Len(createdPolicy.Spec.Rules[1].To, 1)
+	s.Equal([]string{"/login"}, createdPolicy.Spec.Rules[1].To[0].Operation.Paths)
+	s.Equal([]string{"GET"}, createdPolicy.Spec.Rules[1].To[0].Operation.Methods)
+	s.ExpectEventsOrderAndCountDontMatter(ReasonCreatedIstioPolicy, ReasonCreatedIstioPolicy)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestNothingToUpdate() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	existingPolicy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: existingPolicy.Name, Namespace: existingPolicy.Namespace}, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, policy *v1beta1.AuthorizationPolicy, _ ...client.GetOption) error {
+			existingPolicy.DeepCopyInto(policy)
+			return nil
+		})
+	s.expectListExistingPolicies(existingPolicy)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Equal(1, count)
+	s.ExpectNoEvent()
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestUpdatePolicyWhenClientChangesServiceAccount() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	newPolicy, _, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	existingPolicy := newPolicy.DeepCopy()
+	existingPolicy.Spec.Rules[0].From[0].Source.Principals = []string{generatePrincipal(effectivePolicyNamespace, "outdated-sa")}
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: newPolicy.Name, Namespace: newPolicy.Namespace}, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, policy *v1beta1.AuthorizationPolicy, _ ...client.GetOption) error {
+			existingPolicy.DeepCopyInto(policy)
+			return nil
+		})
+	s.Client.EXPECT().Patch(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{}), gomock.Any()).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ client.Patch, _ ...client.PatchOption) error {
+			s.Equal(generatePrincipal(effectivePolicyNamespace, "client-a-sa"), policy.Spec.Rules[0].From[0].Source.Principals[0])
+			return nil
+		})
+	s.expectListExistingPolicies(existingPolicy)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.ExpectEvent(ReasonCreatedIstioPolicy)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) expectExistingPolicy(existingPolicy *v1beta1.AuthorizationPolicy) {
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: existingPolicy.Name, Namespace: existingPolicy.Namespace}, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, policy *v1beta1.AuthorizationPolicy, _ ...client.GetOption) error {
+			existingPolicy.DeepCopyInto(policy)
+			return nil
+		})
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestUpdatePolicyWithHTTPResources() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{
+			Name: effectivePolicyServer,
+			Type: v1alpha3.IntentTypeHTTP,
+			HTTPResources: []v1alpha3.HTTPResource{
+				{Path: "/login", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet, v1alpha3.HTTPMethodPost}},
+				{Path: "/logout", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodPost}},
+			},
+		}),
+	)
+	existingPolicyWithoutHTTP, _, err := s.reconciler.buildAuthorizationPolicy(context.Background(),
+		s.buildEffectivePolicy(ep.Service, effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer})), nil)
+	s.Require().NoError(err)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.expectExistingPolicy(existingPolicyWithoutHTTP)
+	s.Client.EXPECT().Patch(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{}), gomock.Any()).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ client.Patch, _ ...client.PatchOption) error {
+			s.Equal(existingPolicyWithoutHTTP.Name, policy.Name)
+			s.Equal(existingPolicyWithoutHTTP.Spec.Selector.MatchLabels, policy.Spec.Selector.MatchLabels)
+			s.Require().Len(policy.Spec.Rules, 1)
+			s.Equal(generatePrincipal(effectivePolicyNamespace, "client-a-sa"), policy.Spec.Rules[0].From[0].Source.Principals[0])
+			s.Require().Len(policy.Spec.Rules[0].To, 2)
+			s.Equal([]string{"/login"}, policy.Spec.Rules[0].To[0].Operation.Paths)
+			s.Equal([]string{"GET", "POST"}, policy.Spec.Rules[0].To[0].Operation.Methods)
+			s.Equal([]string{"/logout"}, policy.Spec.Rules[0].To[1].Operation.Paths)
+			s.Equal([]string{"POST"}, policy.Spec.Rules[0].To[1].Operation.Methods)
+			return nil
+		})
+	s.expectListExistingPolicies(existingPolicyWithoutHTTP)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.ExpectEvent(ReasonCreatedIstioPolicy)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestNothingToUpdateHTTPResources() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{
+			Name: effectivePolicyServer,
+			Type: v1alpha3.IntentTypeHTTP,
+			HTTPResources: []v1alpha3.HTTPResource{
+				{Path: "/login", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet, v1alpha3.HTTPMethodPost}},
+				{Path: "/logout", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodPost}},
+			},
+		}),
+	)
+	existingPolicy, _, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.expectExistingPolicy(existingPolicy)
+	s.expectListExistingPolicies(existingPolicy)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.ExpectNoEvent()
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestRemovedClientIsRemovedFromPolicy() {
+	server := serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace}
+	clientA := effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer})
+	clientB := effectivePolicyClient("client-b", "client-b-sa", false, v1alpha3.Intent{Name: effectivePolicyServer})
+	existingPolicy, _, err := s.reconciler.buildAuthorizationPolicy(context.Background(), s.buildEffectivePolicy(server, clientA, clientB), nil)
+	s.Require().NoError(err)
+	s.Require().Len(existingPolicy.Spec.Rules, 2)
+
+	// The ClientIntents of client-b were deleted, so it no longer calls the server.
+	ep := s.buildEffectivePolicy(server, clientA)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.expectExistingPolicy(existingPolicy)
+	s.Client.EXPECT().Patch(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{}), gomock.Any()).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ client.Patch, _ ...client.PatchOption) error {
+			s.Require().Len(policy.Spec.Rules, 1)
+			s.Equal([]string{generatePrincipal(effectivePolicyNamespace, "client-a-sa")}, policy.Spec.Rules[0].From[0].Source.Principals)
+			return nil
+		})
+	s.expectListExistingPolicies(existingPolicy)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.ExpectEvent(ReasonCreatedIstioPolicy)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestDeletePolicyOfServerNoLongerCalled() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+	existingPolicy, _, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	existingPolicy.UID = "uid_1"
+	outdatedPolicy := &v1beta1.AuthorizationPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "authorization-policy-to-test-server-from-old-intent-file",
+			Namespace: effectivePolicyNamespace,
+			Labels:    map[string]string{v1alpha3.OtterizeServiceLabelKey: "test-server-from-old-intent-file"},
+			UID:       "uid_2",
+		},
+	}
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.expectExistingPolicy(existingPolicy)
+	s.expectListExistingPolicies(outdatedPolicy, existingPolicy)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+	s.Client.EXPECT().Delete(gomock.Any(), outdatedPolicy).Return(nil)
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Equal(1, count)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestDeleteAllPoliciesWhenNoServerIsCalled() {
+	existingPolicies := []*v1beta1.AuthorizationPolicy{
+		{ObjectMeta: v1.ObjectMeta{Name: "authorization-policy-to-test-server-1", Namespace: effectivePolicyNamespace}},
+		{ObjectMeta: v1.ObjectMeta{Name: "authorization-policy-to-test-server-2", Namespace: effectivePolicyNamespace}},
+	}
+
+	s.expectIstioInstalled()
+	s.expectListExistingPolicies(existingPolicies...)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+	s.Client.EXPECT().Delete(gomock.Any(), existingPolicies[0]).Return(nil)
+	s.Client.EXPECT().Delete(gomock.Any(), existingPolicies[1]).Return(nil)
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{})
+	s.Empty(errs)
+	s.Equal(0, count)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestClientWithoutServiceAccountAnnotationIsSkipped() {
+	clientWithoutStatus := effectivePolicyClient("client-b", "", false, v1alpha3.Intent{Name: effectivePolicyServer})
+	delete(clientWithoutStatus.Annotations, v1alpha3.OtterizeClientServiceAccountAnnotation)
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+		clientWithoutStatus,
+	)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Require().Len(policy.Spec.Rules, 1)
+	s.Equal([]string{generatePrincipal(effectivePolicyNamespace, "client-a-sa")}, policy.Spec.Rules[0].From[0].Source.Principals)
+
+	_, shouldCreate, err = s.reconciler.buildAuthorizationPolicy(context.Background(), s.buildEffectivePolicy(ep.Service, clientWithoutStatus), nil)
+	s.Require().NoError(err)
+	s.False(shouldCreate)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestClientsSharingServiceAccountShareRule() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "shared-sa", false, v1alpha3.Intent{
+			Name:          effectivePolicyServer,
+			Type:          v1alpha3.IntentTypeHTTP,
+			HTTPResources: []v1alpha3.HTTPResource{{Path: "/login", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet}}},
+		}),
+		effectivePolicyClient("client-b", "shared-sa", false, v1alpha3.Intent{
+			Name:          effectivePolicyServer,
+			Type:          v1alpha3.IntentTypeHTTP,
+			HTTPResources: []v1alpha3.HTTPResource{{Path: "/logout", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodPost}}},
+		}),
+	)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Require().Len(policy.Spec.Rules, 1)
+	s.Equal([]string{generatePrincipal(effectivePolicyNamespace, "shared-sa")}, policy.Spec.Rules[0].From[0].Source.Principals)
+	s.Require().Len(policy.Spec.Rules[0].To, 2)
+	s.Equal([]string{"/login"}, policy.Spec.Rules[0].To[0].Operation.Paths)
+	s.Equal([]string{"/logout"}, policy.Spec.Rules[0].To[1].Operation.Paths)
+
+	// Istio cannot tell the clients apart, so a client with access to the whole server grants it to both.
+	ep = s.buildEffectivePolicy(ep.Service,
+		ep.CalledBy[0].ClientIntents,
+		effectivePolicyClient("client-c", "shared-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+	policy, shouldCreate, err = s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Require().Len(policy.Spec.Rules, 1)
+	s.Nil(policy.Spec.Rules[0].To)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestClientMissingSidecarExcludedFromPolicy() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+		effectivePolicyClient("client-b", "client-b-sa", true, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Require().Len(policy.Spec.Rules, 1)
+	s.Equal([]string{generatePrincipal(effectivePolicyNamespace, "client-a-sa")}, policy.Spec.Rules[0].From[0].Source.Principals)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestClientMissingSidecarRemovesPolicy() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", true, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+	legacyPolicy := &v1beta1.AuthorizationPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "authorization-policy-to-test-server-from-client-a.test-namespace",
+			Namespace: effectivePolicyNamespace,
+			Labels:    map[string]string{v1alpha3.OtterizeServiceLabelKey: formattedEffectiveServer},
+		},
+	}
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.expectListExistingPolicies(legacyPolicy)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.Eq(legacyPolicy)).Return(nil)
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Equal(0, count)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestIstioPolicyCreationDisabled() {
+	s.reconciler.enableIstioPolicyCreation = false
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	s.expectIstioInstalled()
+	s.expectListExistingPolicies()
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Equal(0, count)
+	s.ExpectEvent(consts.ReasonIstioPolicyCreationDisabled)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestNamespaceNotAllowed() {
+	s.reconciler.restrictToNamespaces = []string{"other-namespace"}
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	_, shouldCreate, err := s.reconciler.applyServiceEffectivePolicy(context.Background(), ep)
+	s.NoError(err)
+	s.False(shouldCreate)
+	s.ExpectEvent(ReasonNamespaceNotAllowed)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestKubernetesServiceTarget() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace, Kind: serviceidentity.KindService},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: "svc:" + effectivePolicyServer}),
+	)
+
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace}, gomock.AssignableToTypeOf(&corev1.Service{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, svc *corev1.Service, _ ...client.GetOption) error {
+			svc.Spec.Selector = map[string]string{"app": "test-server"}
+			return nil
+		})
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Equal("authorization-policy-to-test-server-service", policy.Name)
+	s.Equal(map[string]string{"app": "test-server"}, policy.Spec.Selector.MatchLabels)
+	s.Equal(v1beta12.AuthorizationPolicy_ALLOW, policy.Spec.Action)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestKubernetesServiceWithoutSelectorIsSkipped() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace, Kind: serviceidentity.KindService},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: "svc:" + effectivePolicyServer}),
+	)
+
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace}, gomock.AssignableToTypeOf(&corev1.Service{})).Return(nil)
+
+	_, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.False(shouldCreate)
+	s.ExpectEvent(ReasonServiceWithoutSelector)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestNonHTTPIntentsAreIgnored() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer, Type: v1alpha3.IntentTypeKafka}),
+	)
+
+	_, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.NoError(err)
+	s.False(shouldCreate)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestHTTPMatchingWithHostsPortsAndHeaders() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{
+			Name: effectivePolicyServer,
+			Type: v1alpha3.IntentTypeHTTP,
+			HTTPResources: []v1alpha3.HTTPResource{
+				{Path: "/api/*", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet}, Hosts: []string{"api.example.com"}, Port: 8080},
+				{Path: "/tenants/*", Headers: []v1alpha3.HTTPHeader{{Name: "x-tenant-id", Values: []string{"tenant-a", "tenant-b"}}}},
+			},
+		}),
+	)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Require().Len(policy.Spec.Rules, 2)
+
+	plainRule := policy.Spec.Rules[0]
+	s.Require().Len(plainRule.To, 1)
+	s.Equal([]string{"/api/*"}, plainRule.To[0].Operation.Paths)
+	s.Equal([]string{"api.example.com"}, plainRule.To[0].Operation.Hosts)
+	s.Equal([]string{"8080"}, plainRule.To[0].Operation.Ports)
+	s.Empty(plainRule.When)
+
+	headerRule := policy.Spec.Rules[1]
+	s.Equal(plainRule.From, headerRule.From)
+	s.Require().Len(headerRule.To, 1)
+	s.Equal([]string{"/tenants/*"}, headerRule.To[0].Operation.Paths)
+	s.Require().Len(headerRule.When, 1)
+	s.Equal("request.headers[x-tenant-id]", headerRule.When[0].Key)
+	s.Equal([]string{"tenant-a", "tenant-b"}, headerRule.When[0].Values)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestShadowModeCreatesDryRunPolicy() {
+	s.reconciler.enforcementDefaultState = false
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1alpha3.ProtectedServiceList{}), gomock.Any(), gomock.Any()).Return(nil)
+	policyName := types.NamespacedName{Name: "authorization-policy-to-test-server", Namespace: effectivePolicyNamespace}
+	s.Client.EXPECT().Get(gomock.Any(), policyName, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+
+	var createdPolicy *v1beta1.AuthorizationPolicy
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ ...client.CreateOption) error {
+			createdPolicy = policy
+			return nil
+		})
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicyList{}), client.HasLabels{v1alpha3.OtterizeServiceLabelKey}).DoAndReturn(
+		func(_ context.Context, list *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, createdPolicy)
+			return nil
+		})
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Equal(1, count)
+	s.Require().NotNil(createdPolicy)
+	s.Equal("true", createdPolicy.Annotations[IstioDryRunAnnotation])
+	s.ExpectEventsOrderAndCountDontMatter(consts.ReasonEnforcementDefaultOff, ReasonCreatedIstioPolicy)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestProtectedServiceEnforcedInShadowMode() {
+	s.reconciler.enforcementDefaultState = false
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	// The server has a ProtectedService, so it is enforced although enforcement is disabled globally.
+	s.expectServerJWT(nil)
+	policyName := types.NamespacedName{Name: "authorization-policy-to-test-server", Namespace: effectivePolicyNamespace}
+	s.Client.EXPECT().Get(gomock.Any(), policyName, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ ...client.CreateOption) error {
+			s.NotContains(policy.Annotations, IstioDryRunAnnotation)
+			return nil
+		})
+	s.expectListExistingPolicies()
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	count, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Equal(1, count)
+	s.ExpectEvent(ReasonCreatedIstioPolicy)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestEnforcingRemovesDryRunAnnotation() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	newPolicy, _, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	existingPolicy := newPolicy.DeepCopy()
+	existingPolicy.Annotations = map[string]string{IstioDryRunAnnotation: "true", "user-annotation": "value"}
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: newPolicy.Name, Namespace: newPolicy.Namespace}, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, policy *v1beta1.AuthorizationPolicy, _ ...client.GetOption) error {
+			existingPolicy.DeepCopyInto(policy)
+			return nil
+		})
+	s.Client.EXPECT().Patch(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{}), gomock.Any()).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ client.Patch, _ ...client.PatchOption) error {
+			s.NotContains(policy.Annotations, IstioDryRunAnnotation)
+			s.Equal("value", policy.Annotations["user-annotation"])
+			return nil
+		})
+	s.expectListExistingPolicies(existingPolicy)
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits()
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.ExpectEvent(ReasonCreatedIstioPolicy)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestResourceJWTRequirement() {
+	jwt := &v1alpha3.JWTRequirement{
+		Issuer:    "https://issuer.example.com",
+		JWKSURI:   "https://issuer.example.com/jwks.json",
+		Subjects:  []string{"billing"},
+		Audiences: []string{"internal-api"},
+		Claims:    []v1alpha3.JWTClaim{{Name: "role", Values: []string{"admin"}}},
+	}
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{
+			Name:          effectivePolicyServer,
+			Type:          v1alpha3.IntentTypeHTTP,
+			HTTPResources: []v1alpha3.HTTPResource{{Path: "/admin", JWT: jwt}},
+		}),
+	)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Require().Len(policy.Spec.Rules, 1)
+	rule := policy.Spec.Rules[0]
+	s.Equal([]string{generatePrincipal(effectivePolicyNamespace, "client-a-sa")}, rule.From[0].Source.Principals)
+	s.Equal([]string{"https://issuer.example.com/billing"}, rule.From[0].Source.RequestPrincipals)
+	s.Require().Len(rule.When, 2)
+	s.Equal("request.auth.audiences", rule.When[0].Key)
+	s.Equal([]string{"internal-api"}, rule.When[0].Values)
+	s.Equal("request.auth.claims[role]", rule.When[1].Key)
+	s.Equal([]string{"admin"}, rule.When[1].Values)
+
+	requestAuthentication, shouldCreate := buildRequestAuthentication(ep, policy, nil)
+	s.Require().True(shouldCreate)
+	s.Equal(policy.Spec.Selector, requestAuthentication.Spec.Selector)
+	s.Require().Len(requestAuthentication.Spec.JwtRules, 1)
+	s.Equal("https://issuer.example.com", requestAuthentication.Spec.JwtRules[0].Issuer)
+	s.Equal("https://issuer.example.com/jwks.json", requestAuthentication.Spec.JwtRules[0].JwksUri)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestProtectedServiceJWTRequirement() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(&v1alpha3.JWTRequirement{Issuer: "https://issuer.example.com"})
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "authorization-policy-to-test-server", Namespace: effectivePolicyNamespace}, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ ...client.CreateOption) error {
+			s.Require().Len(policy.Spec.Rules, 1)
+			s.Require().Len(policy.Spec.Rules[0].When, 1)
+			s.Equal("request.auth.principal", policy.Spec.Rules[0].When[0].Key)
+			s.Equal([]string{"https://issuer.example.com/*"}, policy.Spec.Rules[0].When[0].Values)
+			return nil
+		})
+
+	requestAuthenticationName := types.NamespacedName{Name: "request-authentication-to-test-server", Namespace: effectivePolicyNamespace}
+	s.Client.EXPECT().Get(gomock.Any(), requestAuthenticationName, gomock.AssignableToTypeOf(&v1beta1.RequestAuthentication{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	var createdRequestAuthentication *v1beta1.RequestAuthentication
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.RequestAuthentication{})).DoAndReturn(
+		func(_ context.Context, requestAuthentication *v1beta1.RequestAuthentication, _ ...client.CreateOption) error {
+			createdRequestAuthentication = requestAuthentication
+			return nil
+		})
+	s.expectListExistingPolicies()
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.RequestAuthenticationList{}), client.HasLabels{v1alpha3.OtterizeServiceLabelKey}).DoAndReturn(
+		func(_ context.Context, list *v1beta1.RequestAuthenticationList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, createdRequestAuthentication)
+			return nil
+		})
+	s.expectListExistingRateLimits()
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Require().NotNil(createdRequestAuthentication)
+	s.Equal(formattedEffectiveServer, createdRequestAuthentication.Labels[v1alpha3.OtterizeServiceLabelKey])
+	s.Require().Len(createdRequestAuthentication.Spec.JwtRules, 1)
+	s.Equal("https://issuer.example.com", createdRequestAuthentication.Spec.JwtRules[0].Issuer)
+	s.ExpectEventsOrderAndCountDontMatter(ReasonCreatedIstioPolicy, ReasonCreatedRequestAuthentication)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestRequestAuthenticationRemovedWhenNoLongerRequired() {
+	staleRequestAuthentication := &v1beta1.RequestAuthentication{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "request-authentication-to-test-server",
+			Namespace: effectivePolicyNamespace,
+			Labels:    map[string]string{v1alpha3.OtterizeServiceLabelKey: formattedEffectiveServer},
+		},
+	}
+
+	s.expectIstioInstalled()
+	s.expectListExistingPolicies()
+	s.expectListExistingRequestAuthentications(staleRequestAuthentication)
+	s.expectListExistingRateLimits()
+	s.Client.EXPECT().Delete(gomock.Any(), staleRequestAuthentication).Return(nil)
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{})
+	s.Empty(errs)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestRateLimitEnvoyFilterHasBucketPerClient() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{
+			Name:      effectivePolicyServer,
+			Type:      v1alpha3.IntentTypeHTTP,
+			RateLimit: &v1alpha3.RateLimit{Requests: 100, Per: v1alpha3.RateLimitUnitMinute},
+		}),
+		effectivePolicyClient("client-b", "client-a-sa", false, v1alpha3.Intent{
+			Name:      effectivePolicyServer,
+			Type:      v1alpha3.IntentTypeHTTP,
+			RateLimit: &v1alpha3.RateLimit{Requests: 10},
+		}),
+		effectivePolicyClient("client-c", "client-c-sa", false, v1alpha3.Intent{Name: effectivePolicyServer}),
+	)
+
+	policy, shouldCreate, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+
+	envoyFilter, shouldCreate, err := buildRateLimitEnvoyFilter(ep, policy)
+	s.Require().NoError(err)
+	s.Require().True(shouldCreate)
+	s.Equal("rate-limit-to-test-server", envoyFilter.Name)
+	s.Equal(policy.Spec.Selector.MatchLabels, envoyFilter.Spec.WorkloadSelector.Labels)
+	s.Require().Len(envoyFilter.Spec.ConfigPatches, 2)
+
+	route := envoyFilter.Spec.ConfigPatches[1].Patch.Value.AsMap()
+	rateLimits := route["route"].(map[string]any)["rate_limits"].([]any)
+	s.Require().Len(rateLimits, 1)
+
+	perFilterConfig := route["typed_per_filter_config"].(map[string]any)[localRateLimitFilterName].(map[string]any)["value"].(map[string]any)
+	descriptors := perFilterConfig["descriptors"].([]any)
+	s.Require().Len(descriptors, 1)
+	descriptor := descriptors[0].(map[string]any)
+	entry := descriptor["entries"].([]any)[0].(map[string]any)
+	s.Equal(generatePrincipal(effectivePolicyNamespace, "client-a-sa"), entry["value"])
+	// Both clients use the same service account, so the stricter limit of 100 per minute applies.
+	bucket := descriptor["token_bucket"].(map[string]any)
+	s.Equal(float64(100), bucket["max_tokens"])
+	s.Equal("60s", bucket["fill_interval"])
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestRateLimitIgnoresForgedClientCertHeader() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{
+			Name:      effectivePolicyServer,
+			Type:      v1alpha3.IntentTypeHTTP,
+			RateLimit: &v1alpha3.RateLimit{Requests: 10},
+		}),
+	)
+
+	policy, _, err := s.reconciler.buildAuthorizationPolicy(context.Background(), ep, nil)
+	s.Require().NoError(err)
+	envoyFilter, _, err := buildRateLimitEnvoyFilter(ep, policy)
+	s.Require().NoError(err)
+
+	route := envoyFilter.Spec.ConfigPatches[1].Patch.Value.AsMap()
+	action := route["route"].(map[string]any)["rate_limits"].([]any)[0].(map[string]any)["actions"].([]any)[0].(map[string]any)
+	header := action["header_value_match"].(map[string]any)["headers"].([]any)[0].(map[string]any)
+	s.Equal("x-forwarded-client-cert", header["name"])
+	// Envoy requires safe regexes to match the whole header value.
+	matcher := regexp.MustCompile("^(?:" + header["string_match"].(map[string]any)["safe_regex"].(map[string]any)["regex"].(string) + ")$")
+
+	appendedBySidecar := func(principal string) string {
+		return `By=spiffe://cluster.local/ns/test-namespace/sa/test-server;Hash=abc;Subject="";URI=spiffe://` + principal
+	}
+	clientA := generatePrincipal(effectivePolicyNamespace, "client-a-sa")
+	attacker := generatePrincipal(effectivePolicyNamespace, "attacker-sa")
+
+	s.True(matcher.MatchString(appendedBySidecar(clientA)))
+	s.True(matcher.MatchString("URI=spiffe://" + attacker + "," + appendedBySidecar(clientA)))
+	s.True(matcher.MatchString(appendedBySidecar(clientA) + ";DNS=client-a"))
+	// A client that adds an element with another principal's identity is not counted against that principal.
+	s.False(matcher.MatchString("URI=spiffe://" + clientA + "," + appendedBySidecar(attacker)))
+	s.False(matcher.MatchString("By=x;URI=spiffe://" + clientA + ";Hash=y," + appendedBySidecar(attacker)))
+	s.False(matcher.MatchString(appendedBySidecar(clientA + "-2")))
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestRateLimitCreatedForEnforcedServer() {
+	ep := s.buildEffectivePolicy(
+		serviceidentity.ServiceIdentity{Name: effectivePolicyServer, Namespace: effectivePolicyNamespace},
+		effectivePolicyClient("client-a", "client-a-sa", false, v1alpha3.Intent{
+			Name:      effectivePolicyServer,
+			Type:      v1alpha3.IntentTypeHTTP,
+			RateLimit: &v1alpha3.RateLimit{Requests: 5},
+		}),
+	)
+
+	s.expectIstioInstalled()
+	s.expectServerJWT(nil)
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "authorization-policy-to-test-server", Namespace: effectivePolicyNamespace}, gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(nil)
+
+	rateLimitName := types.NamespacedName{Name: "rate-limit-to-test-server", Namespace: effectivePolicyNamespace}
+	s.Client.EXPECT().Get(gomock.Any(), rateLimitName, gomock.AssignableToTypeOf(&istionetworking.EnvoyFilter{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	var createdEnvoyFilter *istionetworking.EnvoyFilter
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&istionetworking.EnvoyFilter{})).DoAndReturn(
+		func(_ context.Context, envoyFilter *istionetworking.EnvoyFilter, _ ...client.CreateOption) error {
+			createdEnvoyFilter = envoyFilter
+			return nil
+		})
+	s.expectListExistingPolicies()
+	s.expectListExistingRequestAuthentications()
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&istionetworking.EnvoyFilterList{}), client.HasLabels{v1alpha3.OtterizeServiceLabelKey}).DoAndReturn(
+		func(_ context.Context, list *istionetworking.EnvoyFilterList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, createdEnvoyFilter)
+			return nil
+		})
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{ep})
+	s.Empty(errs)
+	s.Require().NotNil(createdEnvoyFilter)
+	s.Equal(formattedEffectiveServer, createdEnvoyFilter.Labels[v1alpha3.OtterizeServiceLabelKey])
+	s.ExpectEventsOrderAndCountDontMatter(ReasonCreatedIstioPolicy, ReasonCreatedRateLimit)
+}
+
+func (s *EffectivePolicyReconcilerTestSuite) TestRateLimitRemovedWhenNoLongerRequired() {
+	staleEnvoyFilter := &istionetworking.EnvoyFilter{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "rate-limit-to-test-server",
+			Namespace: effectivePolicyNamespace,
+			Labels:    map[string]string{v1alpha3.OtterizeServiceLabelKey: formattedEffectiveServer},
+		},
+	}
+
+	s.expectIstioInstalled()
+	s.expectListExistingPolicies()
+	s.expectListExistingRequestAuthentications()
+	s.expectListExistingRateLimits(staleEnvoyFilter)
+	s.Client.EXPECT().Delete(gomock.Any(), staleEnvoyFilter).Return(nil)
+
+	_, errs := s.reconciler.ReconcileEffectivePolicies(context.Background(), []effectivepolicy.ServiceEffectivePolicy{})
+	s.Empty(errs)
+}
+
+func TestEffectivePolicyReconcilerTestSuite(t *testing.T) {
+	suite.Run(t, new(EffectivePolicyReconcilerTestSuite))
+}
@@ -0,0 +1,578 @@
+package istiopolicy
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	v1beta1networking "istio.io/api/networking/v1beta1"
+	v1beta1security "istio.io/api/security/v1beta1"
+	v1beta1type "istio.io/api/type/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"maps"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+const (
+	ReasonCreatingIstioServiceEntryFailed         = "CreatingIstioServiceEntryFailed"
+	ReasonCreatingIstioEgressGatewayRoutingFailed = "CreatingIstioEgressGatewayRoutingFailed"
+	ReasonIstioEgressGatewayNotFound              = "IstioEgressGatewayNotFound"
+	ReasonIstioEgressGatewayDomainNotRouted       = "IstioEgressGatewayDomainNotRouted"
+	OtterizeIstioServiceEntryNameTemplate         = "otterize-internet-%s"
+	OtterizeIstioEgressGatewayNameTemplate        = "otterize-egress-gateway-%s"
+	OtterizeIstioEgressGatewayPolicyNameTemplate  = "egress-gateway-from-%s"
+	defaultEgressGatewayNamespace                 = "istio-system"
+	// egressGatewayPort is the port routed through the egress gateway. Traffic to it is TLS, so the gateway can tell
+	// domains apart by SNI, both for routing and for authorization.
+	egressGatewayPort = 443
+)
+
+var defaultInternetPorts = []int{443, 80}
+
+//+kubebuilder:rbac:groups="networking.istio.io",resources=serviceentries;gateways;destinationrules;virtualservices,verbs=get;update;patch;list;watch;delete;create
+//+kubebuilder:rbac:groups="security.istio.io",resources=authorizationpolicies,verbs=get;update;patch;list;watch;delete;create
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+
+// InternetEgressManager registers the domains of a client's internet intents in the mesh as ServiceEntries, so they are
+// controlled by hostname rather than by resolved IPs. If an egress gateway is configured, traffic to the domains is
+// routed through it. The routing of a domain is shared by all of the clients that reach it.
+// If egress gateway policies are enabled, an AuthorizationPolicy on the gateway allows each client to reach only its own
+// domains. Istio denies all traffic that no policy allows once any ALLOW policy selects a workload, so this takes over
+// the whole gateway: traffic of workloads without intents is denied as well.
+type InternetEgressManager struct {
+	client                    client.Client
+	recorder                  *injectablerecorder.InjectableRecorder
+	restrictToNamespaces      []string
+	egressGateway             types.NamespacedName
+	enableEgressGatewayPolicy bool
+	enforcementDefaultState   bool
+}
+
+func NewInternetEgressManager(client client.Client, recorder *injectablerecorder.InjectableRecorder, restrictedNamespaces []string, egressGateway types.NamespacedName, enableEgressGatewayPolicy bool, enforcementDefaultState bool) *InternetEgressManager {
+	return &InternetEgressManager{
+		client:                    client,
+		recorder:                  recorder,
+		restrictToNamespaces:      restrictedNamespaces,
+		egressGateway:             egressGateway,
+		enableEgressGatewayPolicy: enableEgressGatewayPolicy,
+		enforcementDefaultState:   enforcementDefaultState,
+	}
+}
+
+// ParseEgressGateway parses the egress gateway service, given as namespace/name. A gateway without a namespace is
+// assumed to be in istio-system, and an empty value means no egress gateway is used.
+func ParseEgressGateway(value string) types.NamespacedName {
+	if value == "" {
+		return types.NamespacedName{}
+	}
+
+	namespace, name, found := strings.Cut(value, "/")
+	if !found {
+		return types.NamespacedName{Namespace: defaultEgressGatewayNamespace, Name: value}
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+func (m *InternetEgressManager) isEgressGatewayEnabled() bool {
+	return m.egressGateway.Name != ""
+}
+
+func (m *InternetEgressManager) DeleteAll(ctx context.Context, clientIntents *v1alpha3.ClientIntents) error {
+	err := m.client.Delete(ctx, &networkingv1beta1.ServiceEntry{ObjectMeta: v1.ObjectMeta{
+		Name:      fmt.Sprintf(OtterizeIstioServiceEntryNameTemplate, clientIntents.GetServiceName()),
+		Namespace: clientIntents.Namespace,
+	}})
+	if client.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err)
+	}
+
+	if !m.isEgressGatewayEnabled() {
+		return nil
+	}
+
+	return m.deleteEgressGatewayRouting(ctx, clientIntents)
+}
+
+// Create generates a ServiceEntry for the domains of the client's internet intents and, if an egress gateway is
+// configured, routes them through the gateway.
+func (m *InternetEgressManager) Create(ctx context.Context, clientIntents *v1alpha3.ClientIntents, clientServiceAccount string) error {
+	if len(m.restrictToNamespaces) != 0 && !lo.Contains(m.restrictToNamespaces, clientIntents.Namespace) {
+		logrus.Debugf("ClientIntents are in namespace %s but namespace is not allowed by configuration, Istio internet egress skipped", clientIntents.Namespace)
+		return nil
+	}
+
+	var gatewaySelector map[string]string
+	if m.isEgressGatewayEnabled() {
+		var err error
+		gatewaySelector, err = m.getEgressGatewaySelector(ctx, clientIntents)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	serviceEntry := m.generateServiceEntry(clientIntents, gatewaySelector != nil)
+	if serviceEntry == nil {
+		return m.DeleteAll(ctx, clientIntents)
+	}
+
+	err := m.createOrUpdate(ctx, serviceEntry, &networkingv1beta1.ServiceEntry{}, serviceEntrySpec)
+	if err != nil {
+		m.recorder.RecordWarningEventf(clientIntents, ReasonCreatingIstioServiceEntryFailed, "Failed to create Istio service entry: %s", err.Error())
+		return errors.Wrap(err)
+	}
+
+	if gatewaySelector == nil {
+		return nil
+	}
+
+	err = m.applyEgressGatewayRouting(ctx, clientIntents, clientServiceAccount, gatewaySelector)
+	if err != nil {
+		m.recorder.RecordWarningEventf(clientIntents, ReasonCreatingIstioEgressGatewayRoutingFailed, "Failed to route internet traffic through the Istio egress gateway: %s", err.Error())
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+// getEgressGatewaySelector returns the labels of the egress gateway's pods, taken from the selector of its service, or nil
+// if the gateway does not exist.
+func (m *InternetEgressManager) getEgressGatewaySelector(ctx context.Context, clientIntents *v1alpha3.ClientIntents) (map[string]string, error) {
+	gatewayService := &corev1.Service{}
+	err := m.client.Get(ctx, m.egressGateway, gatewayService)
+	if k8serrors.IsNotFound(err) || (err == nil && len(gatewayService.Spec.Selector) == 0) {
+		m.recorder.RecordWarningEventf(clientIntents, ReasonIstioEgressGatewayNotFound, "Istio egress gateway %s was not found, internet traffic is not routed through it", m.egressGateway.String())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	return gatewayService.Spec.Selector, nil
+}
+
+// internetDomains returns the domains of the client's internet intents.
+func internetDomains(clientIntents *v1alpha3.ClientIntents) []string {
+	domains := sets.New[string]()
+	for _, intent := range clientIntents.GetCallsList() {
+		if intent.Type != v1alpha3.IntentTypeInternet || intent.Internet == nil {
+			continue
+		}
+		domains.Insert(intent.Internet.Domains...)
+	}
+
+	return sets.List(domains)
+}
+
+// egressGatewayDomains returns the domains of the client's internet intents that can be routed through the egress
+// gateway. Wildcard domains cannot be resolved by the gateway, so they are reached directly.
+func egressGatewayDomains(clientIntents *v1alpha3.ClientIntents) ([]string, []string) {
+	routed := sets.New[string]()
+	notRouted := sets.New[string]()
+	for _, intent := range clientIntents.GetCallsList() {
+		if intent.Type != v1alpha3.IntentTypeInternet || intent.Internet == nil {
+			continue
+		}
+		if len(intent.Internet.Ports) != 0 && !lo.Contains(intent.Internet.Ports, egressGatewayPort) {
+			notRouted.Insert(intent.Internet.Domains...)
+			continue
+		}
+		for _, domain := range intent.Internet.Domains {
+			if strings.HasPrefix(domain, "*") {
+				notRouted.Insert(domain)
+				continue
+			}
+			routed.Insert(domain)
+		}
+	}
+
+	return sets.List(routed), sets.List(notRouted.Difference(routed))
+}
+
+// generateServiceEntry returns a ServiceEntry for the domains of the client's internet intents, or nil if there are none.
+// The ServiceEntry is visible only to the client's namespace, and to the egress gateway if traffic is routed through it.
+func (m *InternetEgressManager) generateServiceEntry(clientIntents *v1alpha3.ClientIntents, routeThroughEgressGateway bool) *networkingv1beta1.ServiceEntry {
+	domains := sets.New[string]()
+	ports := sets.New[int]()
+	for _, intent := range clientIntents.GetCallsList() {
+		if intent.Type != v1alpha3.IntentTypeInternet || intent.Internet == nil || len(intent.Internet.Domains) == 0 {
+			continue
+		}
+		domains.Insert(intent.Internet.Domains...)
+		if len(intent.Internet.Ports) == 0 {
+			ports.Insert(defaultInternetPorts...)
+		} else {
+			ports.Insert(intent.Internet.Ports...)
+		}
+	}
+
+	if domains.Len() == 0 {
+		return nil
+	}
+
+	// Wildcard hosts cannot be resolved through DNS, so traffic to them is forwarded to its original destination.
+	resolution := v1beta1networking.ServiceEntry_DNS
+	if lo.ContainsBy(sets.List(domains), func(domain string) bool { return strings.HasPrefix(domain, "*") }) {
+		resolution = v1beta1networking.ServiceEntry_NONE
+	}
+
+	servicePorts := lo.Map(sets.List(ports), func(port int, _ int) *v1beta1networking.ServicePort {
+		protocol := "TCP"
+		switch port {
+		case 443:
+			protocol = "TLS"
+		case 80:
+			protocol = "HTTP"
+		}
+		return &v1beta1networking.ServicePort{
+			Number:   uint32(port),
+			Protocol: protocol,
+			Name:     fmt.Sprintf("%s-%d", strings.ToLower(protocol), port),
+		}
+	})
+
+	exportTo := []string{"."}
+	if routeThroughEgressGateway && m.egressGateway.Namespace != clientIntents.Namespace {
+		exportTo = append(exportTo, m.egressGateway.Namespace)
+	}
+
+	return &networkingv1beta1.ServiceEntry{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf(OtterizeIstioServiceEntryNameTemplate, clientIntents.GetServiceName()),
+			Namespace: clientIntents.Namespace,
+			Labels:    internetEgressLabels(clientIntents),
+		},
+		Spec: v1beta1networking.ServiceEntry{
+			Hosts:      sets.List(domains),
+			Ports:      servicePorts,
+			Location:   v1beta1networking.ServiceEntry_MESH_EXTERNAL,
+			Resolution: resolution,
+			ExportTo:   exportTo,
+		},
+	}
+}
+
+func internetEgressLabels(clientIntents *v1alpha3.ClientIntents) map[string]string {
+	return map[string]string{
+		v1alpha3.OtterizeIstioClientAnnotationKey: v1alpha3.GetFormattedOtterizeIdentity(clientIntents.GetServiceName(), clientIntents.Namespace),
+	}
+}
+
+func (m *InternetEgressManager) egressGatewayHost() string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", m.egressGateway.Name, m.egressGateway.Namespace)
+}
+
+func egressGatewayDomainHash(domain string) string {
+	hash := md5.Sum([]byte(domain))
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+// applyEgressGatewayRouting routes traffic to the client's domains through the egress gateway, following Istio's egress
+// gateway pattern: the client's sidecar sends the traffic to the gateway over mutual TLS, with the domain as SNI, and the
+// gateway forwards it to the domain. This lets the gateway authorize the client's identity per domain.
+func (m *InternetEgressManager) applyEgressGatewayRouting(ctx context.Context, clientIntents *v1alpha3.ClientIntents, clientServiceAccount string, gatewaySelector map[string]string) error {
+	domains, notRoutedDomains := egressGatewayDomains(clientIntents)
+	if len(notRoutedDomains) != 0 {
+		m.recorder.RecordWarningEventf(clientIntents, ReasonIstioEgressGatewayDomainNotRouted, "Traffic to %s is not routed through the Istio egress gateway, only non-wildcard domains on port %d are supported", strings.Join(notRoutedDomains, ", "), egressGatewayPort)
+	}
+
+	err := m.syncEgressGatewayRoutes(ctx, gatewaySelector)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return m.applyEgressGatewayPolicy(ctx, clientIntents, clientServiceAccount, gatewaySelector, domains)
+}
+
+// getEgressGatewayClientNamespaces returns the namespaces of the clients that reach each domain through the egress
+// gateway. ClientIntents that are being deleted no longer reach their domains.
+func (m *InternetEgressManager) getEgressGatewayClientNamespaces(ctx context.Context) (map[string]sets.Set[string], error) {
+	var intentsList v1alpha3.ClientIntentsList
+	err := m.client.List(ctx, &intentsList)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	namespacesByDomain := make(map[string]sets.Set[string])
+	for _, clientIntents := range intentsList.Items {
+		if !clientIntents.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if len(m.restrictToNamespaces) != 0 && !lo.Contains(m.restrictToNamespaces, clientIntents.Namespace) {
+			continue
+		}
+
+		domains, _ := egressGatewayDomains(&clientIntents)
+		for _, domain := range domains {
+			if _, ok := namespacesByDomain[domain]; !ok {
+				namespacesByDomain[domain] = sets.New[string]()
+			}
+			namespacesByDomain[domain].Insert(clientIntents.Namespace)
+		}
+	}
+
+	return namespacesByDomain, nil
+}
+
+// syncEgressGatewayRoutes routes every domain that clients reach through the egress gateway, and removes the routing of
+// domains no client reaches anymore. Istio does not allow two gateway servers with the same host and port, so each
+// domain has a single Gateway, DestinationRule and VirtualService, which live in the gateway's namespace and are exported
+// to the namespaces of the domain's clients.
+func (m *InternetEgressManager) syncEgressGatewayRoutes(ctx context.Context, gatewaySelector map[string]string) error {
+	namespacesByDomain, err := m.getEgressGatewayClientNamespaces(ctx)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	routesThatShouldExist := sets.New[string]()
+	for _, domain := range sets.List(sets.KeySet(namespacesByDomain)) {
+		gateway, destinationRule, virtualService := m.generateEgressGatewayRouting(domain, namespacesByDomain[domain], gatewaySelector)
+		err = m.createOrUpdate(ctx, gateway, &networkingv1beta1.Gateway{}, gatewaySpec)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		err = m.createOrUpdate(ctx, destinationRule, &networkingv1beta1.DestinationRule{}, destinationRuleSpec)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		err = m.createOrUpdate(ctx, virtualService, &networkingv1beta1.VirtualService{}, virtualServiceSpec)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		routesThatShouldExist.Insert(gateway.Name)
+	}
+
+	return m.removeEgressGatewayRoutesThatShouldNotExist(ctx, routesThatShouldExist)
+}
+
+// generateEgressGatewayRouting returns the Gateway, DestinationRule and VirtualService that route the domain through the
+// egress gateway. They share the same name, so the subset of the DestinationRule is unique among the DestinationRules
+// of the other domains, which Istio merges.
+func (m *InternetEgressManager) generateEgressGatewayRouting(domain string, clientNamespaces sets.Set[string], gatewaySelector map[string]string) (*networkingv1beta1.Gateway, *networkingv1beta1.DestinationRule, *networkingv1beta1.VirtualService) {
+	domainHash := egressGatewayDomainHash(domain)
+	name := fmt.Sprintf(OtterizeIstioEgressGatewayNameTemplate, domainHash)
+	objectMeta := v1.ObjectMeta{
+		Name:      name,
+		Namespace: m.egressGateway.Namespace,
+		Labels:    map[string]string{v1alpha3.OtterizeIstioEgressGatewayDomainLabelKey: domainHash},
+	}
+	exportTo := append([]string{"."}, sets.List(clientNamespaces.Clone().Delete(m.egressGateway.Namespace))...)
+
+	gateway := &networkingv1beta1.Gateway{
+		ObjectMeta: *objectMeta.DeepCopy(),
+		Spec: v1beta1networking.Gateway{
+			Selector: gatewaySelector,
+			Servers: []*v1beta1networking.Server{{
+				Port:  &v1beta1networking.Port{Number: egressGatewayPort, Name: fmt.Sprintf("tls-%d", egressGatewayPort), Protocol: "TLS"},
+				Hosts: []string{domain},
+				Tls:   &v1beta1networking.ServerTLSSettings{Mode: v1beta1networking.ServerTLSSettings_ISTIO_MUTUAL},
+			}},
+		},
+	}
+
+	destinationRule := &networkingv1beta1.DestinationRule{
+		ObjectMeta: *objectMeta.DeepCopy(),
+		Spec: v1beta1networking.DestinationRule{
+			Host:     m.egressGatewayHost(),
+			ExportTo: exportTo,
+			Subsets: []*v1beta1networking.Subset{{
+				Name: name,
+				TrafficPolicy: &v1beta1networking.TrafficPolicy{
+					PortLevelSettings: []*v1beta1networking.TrafficPolicy_PortTrafficPolicy{{
+						Port: &v1beta1networking.PortSelector{Number: egressGatewayPort},
+						Tls:  &v1beta1networking.ClientTLSSettings{Mode: v1beta1networking.ClientTLSSettings_ISTIO_MUTUAL, Sni: domain},
+					}},
+				},
+			}},
+		},
+	}
+
+	gatewayReference := fmt.Sprintf("%s/%s", m.egressGateway.Namespace, name)
+	virtualService := &networkingv1beta1.VirtualService{
+		ObjectMeta: *objectMeta.DeepCopy(),
+		Spec: v1beta1networking.VirtualService{
+			Hosts:    []string{domain},
+			Gateways: []string{"mesh", gatewayReference},
+			ExportTo: exportTo,
+			Tls: []*v1beta1networking.TLSRoute{{
+				Match: []*v1beta1networking.TLSMatchAttributes{{Gateways: []string{"mesh"}, Port: egressGatewayPort, SniHosts: []string{domain}}},
+				Route: []*v1beta1networking.RouteDestination{{
+					Destination: &v1beta1networking.Destination{
+						Host:   m.egressGatewayHost(),
+						Subset: name,
+						Port:   &v1beta1networking.PortSelector{Number: egressGatewayPort},
+					},
+				}},
+			}},
+			Tcp: []*v1beta1networking.TCPRoute{{
+				Match: []*v1beta1networking.L4MatchAttributes{{Gateways: []string{gatewayReference}, Port: egressGatewayPort}},
+				Route: []*v1beta1networking.RouteDestination{{
+					Destination: &v1beta1networking.Destination{Host: domain, Port: &v1beta1networking.PortSelector{Number: egressGatewayPort}},
+				}},
+			}},
+		},
+	}
+
+	return gateway, destinationRule, virtualService
+}
+
+func (m *InternetEgressManager) egressGatewayPolicy(clientIntents *v1alpha3.ClientIntents) *v1beta1.AuthorizationPolicy {
+	return &v1beta1.AuthorizationPolicy{ObjectMeta: v1.ObjectMeta{
+		Name:      fmt.Sprintf(OtterizeIstioEgressGatewayPolicyNameTemplate, v1alpha3.GetFormattedOtterizeIdentity(clientIntents.GetServiceName(), clientIntents.Namespace)),
+		Namespace: m.egressGateway.Namespace,
+		Labels:    internetEgressLabels(clientIntents),
+	}}
+}
+
+// applyEgressGatewayPolicy allows the client to reach its domains through the egress gateway, if egress gateway policies
+// are enabled. Once the gateway has an allow policy, any traffic through it that no policy allows is denied.
+func (m *InternetEgressManager) applyEgressGatewayPolicy(ctx context.Context, clientIntents *v1alpha3.ClientIntents, clientServiceAccount string, gatewaySelector map[string]string, domains []string) error {
+	policy := m.egressGatewayPolicy(clientIntents)
+
+	if !m.enableEgressGatewayPolicy || !m.enforcementDefaultState || clientServiceAccount == "" || len(domains) == 0 {
+		if m.enableEgressGatewayPolicy && !m.enforcementDefaultState {
+			m.recorder.RecordNormalEvent(clientIntents, consts.ReasonEnforcementDefaultOff, "Enforcement is disabled globally, Istio egress gateway authorization policy skipped")
+		}
+		err := m.client.Delete(ctx, policy)
+		if client.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err)
+		}
+		return nil
+	}
+
+	policy.Spec = v1beta1security.AuthorizationPolicy{
+		Selector: &v1beta1type.WorkloadSelector{MatchLabels: gatewaySelector},
+		Action:   v1beta1security.AuthorizationPolicy_ALLOW,
+		Rules: []*v1beta1security.Rule{{
+			From: []*v1beta1security.Rule_From{{
+				Source: &v1beta1security.Source{
+					Principals: []string{fmt.Sprintf("cluster.local/ns/%s/sa/%s", clientIntents.Namespace, clientServiceAccount)},
+				},
+			}},
+			When: []*v1beta1security.Condition{{Key: "connection.sni", Values: domains}},
+		}},
+	}
+
+	return m.createOrUpdate(ctx, policy, &v1beta1.AuthorizationPolicy{}, authorizationPolicySpec)
+}
+
+// deleteEgressGatewayRouting removes the client's egress gateway policy, and the routing of domains that no other
+// client reaches.
+func (m *InternetEgressManager) deleteEgressGatewayRouting(ctx context.Context, clientIntents *v1alpha3.ClientIntents) error {
+	err := m.client.Delete(ctx, m.egressGatewayPolicy(clientIntents))
+	if client.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err)
+	}
+
+	namespacesByDomain, err := m.getEgressGatewayClientNamespaces(ctx)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	routesThatShouldExist := sets.New[string]()
+	for domain := range namespacesByDomain {
+		routesThatShouldExist.Insert(fmt.Sprintf(OtterizeIstioEgressGatewayNameTemplate, egressGatewayDomainHash(domain)))
+	}
+
+	return m.removeEgressGatewayRoutesThatShouldNotExist(ctx, routesThatShouldExist)
+}
+
+func (m *InternetEgressManager) removeEgressGatewayRoutesThatShouldNotExist(ctx context.Context, routesThatShouldExist sets.Set[string]) error {
+	existingLists := []client.ObjectList{
+		&networkingv1beta1.GatewayList{},
+		&networkingv1beta1.DestinationRuleList{},
+		&networkingv1beta1.VirtualServiceList{},
+	}
+	for _, existingList := range existingLists {
+		err := m.client.List(ctx, existingList, client.InNamespace(m.egressGateway.Namespace), client.HasLabels{v1alpha3.OtterizeIstioEgressGatewayDomainLabelKey})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+
+		existingObjects, err := meta.ExtractList(existingList)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+
+		for _, existingObject := range existingObjects {
+			object := existingObject.(client.Object)
+			if routesThatShouldExist.Has(object.GetName()) {
+				continue
+			}
+
+			logrus.Debugf("Removing orphaned Istio egress gateway routing: %s in namespace %s", object.GetName(), object.GetNamespace())
+			err = m.client.Delete(ctx, object)
+			if client.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func serviceEntrySpec(object client.Object) proto.Message {
+	return &object.(*networkingv1beta1.ServiceEntry).Spec
+}
+
+func gatewaySpec(object client.Object) proto.Message {
+	return &object.(*networkingv1beta1.Gateway).Spec
+}
+
+func destinationRuleSpec(object client.Object) proto.Message {
+	return &object.(*networkingv1beta1.DestinationRule).Spec
+}
+
+func virtualServiceSpec(object client.Object) proto.Message {
+	return &object.(*networkingv1beta1.VirtualService).Spec
+}
+
+func authorizationPolicySpec(object client.Object) proto.Message {
+	return &object.(*v1beta1.AuthorizationPolicy).Spec
+}
+
+// createOrUpdate creates the Istio object, or patches the existing object's spec and labels if they changed. The spec
+// function returns the protobuf spec of an object of the same type as newObject and existingObject.
+func (m *InternetEgressManager) createOrUpdate(ctx context.Context, newObject client.Object, existingObject client.Object, spec func(client.Object) proto.Message) error {
+	err := m.client.Get(ctx, client.ObjectKeyFromObject(newObject), existingObject)
+	if k8serrors.IsNotFound(err) {
+		err = m.client.Create(ctx, newObject)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if proto.Equal(spec(existingObject), spec(newObject)) && maps.Equal(existingObject.GetLabels(), newObject.GetLabels()) {
+		return nil
+	}
+
+	objectCopy := existingObject.DeepCopyObject().(client.Object)
+	objectCopy.SetLabels(newObject.GetLabels())
+	proto.Reset(spec(objectCopy))
+	proto.Merge(spec(objectCopy), spec(newObject))
+	err = m.client.Patch(ctx, objectCopy, client.MergeFrom(existingObject))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package istiopolicy
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	v1beta1networking "istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+var testEgressGateway = types.NamespacedName{Name: "istio-egressgateway", Namespace: "istio-system"}
+
+type InternetEgressManagerTestSuite struct {
+	testbase.MocksSuiteBase
+	manager *InternetEgressManager
+}
+
+func (s *InternetEgressManagerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.manager = NewInternetEgressManager(s.Client, &injectablerecorder.InjectableRecorder{Recorder: s.Recorder}, []string{}, types.NamespacedName{}, false, true)
+}
+
+func (s *InternetEgressManagerTestSuite) TearDownTest() {
+	s.manager = nil
+	s.MocksSuiteBase.TearDownTest()
+}
+
+func (s *InternetEgressManagerTestSuite) expectEgressGatewayService() {
+	s.Client.EXPECT().Get(gomock.Any(), testEgressGateway, gomock.AssignableToTypeOf(&corev1.Service{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, service *corev1.Service, _ ...client.GetOption) error {
+			service.Spec.Selector = map[string]string{"istio": "egressgateway"}
+			return nil
+		})
+}
+
+func (s *InternetEgressManagerTestSuite) TestParseEgressGateway() {
+	s.Equal(types.NamespacedName{}, ParseEgressGateway(""))
+	s.Equal(testEgressGateway, ParseEgressGateway("istio-egressgateway"))
+	s.Equal(types.NamespacedName{Name: "egress", Namespace: "gateways"}, ParseEgressGateway("gateways/egress"))
+}
+
+func (s *InternetEgressManagerTestSuite) TestCreateServiceEntry() {
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Name: "test-server"},
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "")
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).DoAndReturn(
+		func(_ context.Context, serviceEntry *networkingv1beta1.ServiceEntry, _ ...any) error {
+			s.Equal("otterize-internet-test-client", serviceEntry.Name)
+			s.Equal([]string{"api.example.com"}, serviceEntry.Spec.Hosts)
+			s.Equal(v1beta1networking.ServiceEntry_DNS, serviceEntry.Spec.Resolution)
+			s.Equal([]string{"."}, serviceEntry.Spec.ExportTo)
+			s.Len(serviceEntry.Spec.Ports, 2)
+			return nil
+		})
+
+	err := s.manager.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+	s.ExpectNoEvent()
+}
+
+func (s *InternetEgressManagerTestSuite) TestGenerateServiceEntryWildcardDomain() {
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"*.example.com"}, Ports: []int{8443}}},
+	)
+
+	serviceEntry := s.manager.generateServiceEntry(intents, false)
+	s.Require().NotNil(serviceEntry)
+	s.Equal(v1beta1networking.ServiceEntry_NONE, serviceEntry.Spec.Resolution)
+	s.Equal([]string{"."}, serviceEntry.Spec.ExportTo)
+	s.Require().Len(serviceEntry.Spec.Ports, 1)
+	s.Equal(uint32(8443), serviceEntry.Spec.Ports[0].Number)
+	s.Equal("TCP", serviceEntry.Spec.Ports[0].Protocol)
+}
+
+func (s *InternetEgressManagerTestSuite) TestNoServiceEntryWithoutInternetDomains() {
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Ips: []string{"1.1.1.1"}}},
+	)
+
+	s.Nil(s.manager.generateServiceEntry(intents, false))
+
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	err := s.manager.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+}
+
+func (s *InternetEgressManagerTestSuite) expectClientIntents(clientIntents ...*v1alpha3.ClientIntents) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1alpha3.ClientIntentsList{})).DoAndReturn(
+		func(_ context.Context, list *v1alpha3.ClientIntentsList, _ ...client.ListOption) error {
+			for _, intents := range clientIntents {
+				list.Items = append(list.Items, *intents)
+			}
+			return nil
+		})
+}
+
+// expectExistingEgressGatewayRoutes expects the listing of the routing objects in the egress gateway's namespace, and
+// returns the existing VirtualServices from the given ones.
+func (s *InternetEgressManagerTestSuite) expectExistingEgressGatewayRoutes(virtualServices ...*networkingv1beta1.VirtualService) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.GatewayList{}), client.InNamespace("istio-system"), client.HasLabels{v1alpha3.OtterizeIstioEgressGatewayDomainLabelKey}).Return(nil)
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.DestinationRuleList{}), client.InNamespace("istio-system"), client.HasLabels{v1alpha3.OtterizeIstioEgressGatewayDomainLabelKey}).Return(nil)
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.VirtualServiceList{}), client.InNamespace("istio-system"), client.HasLabels{v1alpha3.OtterizeIstioEgressGatewayDomainLabelKey}).DoAndReturn(
+		func(_ context.Context, list *networkingv1beta1.VirtualServiceList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, virtualServices...)
+			return nil
+		})
+}
+
+func (s *InternetEgressManagerTestSuite) TestRouteDomainsThroughEgressGateway() {
+	s.manager.egressGateway = testEgressGateway
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+	otherNamespaceIntents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+	otherNamespaceIntents.Namespace = "other-namespace"
+	routeName := types.NamespacedName{Name: "otterize-egress-gateway-" + egressGatewayDomainHash("api.example.com"), Namespace: "istio-system"}
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "")
+	s.expectEgressGatewayService()
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).DoAndReturn(
+		func(_ context.Context, serviceEntry *networkingv1beta1.ServiceEntry, _ ...any) error {
+			s.Equal([]string{".", "istio-system"}, serviceEntry.Spec.ExportTo)
+			return nil
+		})
+	s.expectClientIntents(intents, otherNamespaceIntents)
+
+	// Both clients reach the same domain, so they share a single Gateway, DestinationRule and VirtualService.
+	s.Client.EXPECT().Get(gomock.Any(), routeName, gomock.AssignableToTypeOf(&networkingv1beta1.Gateway{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Gateway{})).DoAndReturn(
+		func(_ context.Context, gateway *networkingv1beta1.Gateway, _ ...any) error {
+			s.Equal(map[string]string{"istio": "egressgateway"}, gateway.Spec.Selector)
+			s.Require().Len(gateway.Spec.Servers, 1)
+			s.Equal([]string{"api.example.com"}, gateway.Spec.Servers[0].Hosts)
+			s.Equal(v1beta1networking.ServerTLSSettings_ISTIO_MUTUAL, gateway.Spec.Servers[0].Tls.Mode)
+			return nil
+		})
+
+	s.Client.EXPECT().Get(gomock.Any(), routeName, gomock.AssignableToTypeOf(&networkingv1beta1.DestinationRule{})).Return(notFound)
+	var subsetName string
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.DestinationRule{})).DoAndReturn(
+		func(_ context.Context, destinationRule *networkingv1beta1.DestinationRule, _ ...any) error {
+			s.Equal("istio-egressgateway.istio-system.svc.cluster.local", destinationRule.Spec.Host)
+			s.Equal([]string{".", "other-namespace", "test-namespace"}, destinationRule.Spec.ExportTo)
+			s.Require().Len(destinationRule.Spec.Subsets, 1)
+			subsetName = destinationRule.Spec.Subsets[0].Name
+			s.Equal("api.example.com", destinationRule.Spec.Subsets[0].TrafficPolicy.PortLevelSettings[0].Tls.Sni)
+			return nil
+		})
+
+	s.Client.EXPECT().Get(gomock.Any(), routeName, gomock.AssignableToTypeOf(&networkingv1beta1.VirtualService{})).Return(notFound)
+	var createdVirtualService *networkingv1beta1.VirtualService
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.VirtualService{})).DoAndReturn(
+		func(_ context.Context, virtualService *networkingv1beta1.VirtualService, _ ...any) error {
+			createdVirtualService = virtualService
+			return nil
+		})
+	staleVirtualService := &networkingv1beta1.VirtualService{ObjectMeta: v1.ObjectMeta{Name: "otterize-egress-gateway-stale", Namespace: "istio-system"}}
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.GatewayList{}), gomock.Any(), gomock.Any()).Return(nil)
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.DestinationRuleList{}), gomock.Any(), gomock.Any()).Return(nil)
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.VirtualServiceList{}), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, list *networkingv1beta1.VirtualServiceList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, createdVirtualService, staleVirtualService)
+			return nil
+		})
+	s.Client.EXPECT().Delete(gomock.Any(), staleVirtualService).Return(nil)
+
+	// Egress gateway policies are disabled by default.
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(notFound)
+
+	err := s.manager.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+	s.ExpectNoEvent()
+
+	s.Require().NotNil(createdVirtualService)
+	s.Equal("istio-system", createdVirtualService.Namespace)
+	s.Equal([]string{"api.example.com"}, createdVirtualService.Spec.Hosts)
+	s.Equal([]string{".", "other-namespace", "test-namespace"}, createdVirtualService.Spec.ExportTo)
+	s.Equal([]string{"mesh", "istio-system/" + routeName.Name}, createdVirtualService.Spec.Gateways)
+	s.Equal(subsetName, createdVirtualService.Spec.Tls[0].Route[0].Destination.Subset)
+	s.Equal("api.example.com", createdVirtualService.Spec.Tcp[0].Route[0].Destination.Host)
+}
+
+func (s *InternetEgressManagerTestSuite) TestEgressGatewayPolicyWhenEnabled() {
+	s.manager.egressGateway = testEgressGateway
+	s.manager.enableEgressGatewayPolicy = true
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+	gateway, destinationRule, virtualService := s.manager.generateEgressGatewayRouting("api.example.com", sets.New("test-namespace"), map[string]string{"istio": "egressgateway"})
+
+	s.expectEgressGatewayService()
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).Return(nil)
+	s.Client.EXPECT().Patch(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{}), gomock.Any()).Return(nil)
+	s.expectClientIntents(intents)
+	s.Client.EXPECT().Get(gomock.Any(), client.ObjectKeyFromObject(gateway), gomock.AssignableToTypeOf(&networkingv1beta1.Gateway{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, existing *networkingv1beta1.Gateway, _ ...client.GetOption) error {
+			gateway.DeepCopyInto(existing)
+			return nil
+		})
+	s.Client.EXPECT().Get(gomock.Any(), client.ObjectKeyFromObject(destinationRule), gomock.AssignableToTypeOf(&networkingv1beta1.DestinationRule{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, existing *networkingv1beta1.DestinationRule, _ ...client.GetOption) error {
+			destinationRule.DeepCopyInto(existing)
+			return nil
+		})
+	s.Client.EXPECT().Get(gomock.Any(), client.ObjectKeyFromObject(virtualService), gomock.AssignableToTypeOf(&networkingv1beta1.VirtualService{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, existing *networkingv1beta1.VirtualService, _ ...client.GetOption) error {
+			virtualService.DeepCopyInto(existing)
+			return nil
+		})
+	s.expectExistingEgressGatewayRoutes(virtualService)
+
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, _ ...any) error {
+			s.Equal("istio-system", policy.Namespace)
+			s.Equal(map[string]string{"istio": "egressgateway"}, policy.Spec.Selector.MatchLabels)
+			s.Require().Len(policy.Spec.Rules, 1)
+			s.Equal([]string{generatePrincipal("test-namespace", "test-client-sa")}, policy.Spec.Rules[0].From[0].Source.Principals)
+			s.Equal("connection.sni", policy.Spec.Rules[0].When[0].Key)
+			s.Equal([]string{"api.example.com"}, policy.Spec.Rules[0].When[0].Values)
+			return nil
+		})
+
+	err := s.manager.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+	s.ExpectNoEvent()
+}
+
+func (s *InternetEgressManagerTestSuite) TestDeleteKeepsRoutingOfDomainsReachedByOtherClients() {
+	s.manager.egressGateway = testEgressGateway
+	deletedIntents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com", "only-deleted.example.com"}}},
+	)
+	deletedIntents.DeletionTimestamp = lo.ToPtr(v1.Now())
+	otherIntents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+	otherIntents.Name = "other-client-intents"
+	sharedRoute := &networkingv1beta1.VirtualService{ObjectMeta: v1.ObjectMeta{Name: "otterize-egress-gateway-" + egressGatewayDomainHash("api.example.com"), Namespace: "istio-system"}}
+	unusedRoute := &networkingv1beta1.VirtualService{ObjectMeta: v1.ObjectMeta{Name: "otterize-egress-gateway-" + egressGatewayDomainHash("only-deleted.example.com"), Namespace: "istio-system"}}
+
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).Return(nil)
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&v1beta1.AuthorizationPolicy{})).Return(k8serrors.NewNotFound(schema.GroupResource{}, ""))
+	s.expectClientIntents(deletedIntents, otherIntents)
+	s.expectExistingEgressGatewayRoutes(sharedRoute, unusedRoute)
+	s.Client.EXPECT().Delete(gomock.Any(), unusedRoute).Return(nil)
+
+	err := s.manager.DeleteAll(context.Background(), deletedIntents)
+	s.NoError(err)
+}
+
+func (s *InternetEgressManagerTestSuite) TestWildcardDomainsNotRoutedThroughEgressGateway() {
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"*.example.com", "api.example.com"}}},
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"plain.example.com"}, Ports: []int{80}}},
+	)
+
+	routed, notRouted := egressGatewayDomains(intents)
+	s.Equal([]string{"api.example.com"}, routed)
+	s.Equal([]string{"*.example.com", "plain.example.com"}, notRouted)
+}
+
+func (s *InternetEgressManagerTestSuite) TestMissingEgressGatewaySkipsRouting() {
+	s.manager.egressGateway = testEgressGateway
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "")
+	s.Client.EXPECT().Get(gomock.Any(), testEgressGateway, gomock.AssignableToTypeOf(&corev1.Service{})).Return(notFound)
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.ServiceEntry{})).DoAndReturn(
+		func(_ context.Context, serviceEntry *networkingv1beta1.ServiceEntry, _ ...any) error {
+			s.Equal([]string{"."}, serviceEntry.Spec.ExportTo)
+			return nil
+		})
+
+	err := s.manager.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+	s.ExpectEvent(ReasonIstioEgressGatewayNotFound)
+}
+
+func TestInternetEgressManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(InternetEgressManagerTestSuite))
+}
@@ -0,0 +1,146 @@
+package istiopolicy
+
+import (
+	"context"
+	"fmt"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/sirupsen/logrus"
+	v1beta1security "istio.io/api/security/v1beta1"
+	v1beta1type "istio.io/api/type/v1beta1"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	IstioPeerAuthenticationCRDName         = "peerauthentications.security.istio.io"
+	OtterizePeerAuthenticationNameTemplate = "otterize-strict-mtls-%s"
+)
+
+//+kubebuilder:rbac:groups="security.istio.io",resources=peerauthentications,verbs=get;update;patch;list;watch;delete;create
+
+// PeerAuthenticationManager enforces STRICT mTLS on protected services. The ALLOW policies created by the
+// EffectivePolicyReconciler match on the client's principal, which plaintext traffic does not carry - so unless mTLS is
+// strict, a client without a sidecar is not subject to them.
+type PeerAuthenticationManager struct {
+	client client.Client
+}
+
+func NewPeerAuthenticationManager(client client.Client) *PeerAuthenticationManager {
+	return &PeerAuthenticationManager{client: client}
+}
+
+func IsIstioPeerAuthenticationInstalled(ctx context.Context, client client.Client) (bool, error) {
+	crd := apiextensionsv1.CustomResourceDefinition{}
+	err := client.Get(ctx, types.NamespacedName{Name: IstioPeerAuthenticationCRDName}, &crd)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return false, errors.Wrap(err)
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// ReconcileNamespace makes sure each of the protected services in the namespace has a STRICT PeerAuthentication, and
+// removes the PeerAuthentications of services that are no longer protected.
+func (m *PeerAuthenticationManager) ReconcileNamespace(ctx context.Context, namespace string, protectedServiceNames []string) error {
+	desiredPolicies := make(map[string]*v1beta1.PeerAuthentication)
+	for _, serviceName := range protectedServiceNames {
+		formattedServerName := v1alpha3.GetFormattedOtterizeIdentity(serviceName, namespace)
+		desiredPolicies[formattedServerName] = m.generatePeerAuthentication(formattedServerName, serviceName, namespace)
+	}
+
+	var existingPolicies v1beta1.PeerAuthenticationList
+	err := m.client.List(ctx, &existingPolicies, client.InNamespace(namespace), client.HasLabels{v1alpha3.OtterizeIstioPeerAuthenticationLabelKey})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	for _, existingPolicy := range existingPolicies.Items {
+		formattedServerName := existingPolicy.Labels[v1alpha3.OtterizeIstioPeerAuthenticationLabelKey]
+		desiredPolicy, found := desiredPolicies[formattedServerName]
+		if !found {
+			err = m.client.Delete(ctx, existingPolicy)
+			if client.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err)
+			}
+			logrus.Debugf("Deleted Istio peer authentication %s", existingPolicy.Name)
+			continue
+		}
+
+		err = m.updateIfNeeded(ctx, existingPolicy, desiredPolicy)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		delete(desiredPolicies, formattedServerName)
+	}
+
+	for _, policy := range desiredPolicies {
+		err = m.client.Create(ctx, policy)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		logrus.Debugf("Created Istio peer authentication %s", policy.Name)
+	}
+
+	return nil
+}
+
+func (m *PeerAuthenticationManager) updateIfNeeded(ctx context.Context, existingPolicy *v1beta1.PeerAuthentication, newPolicy *v1beta1.PeerAuthentication) error {
+	if isPeerAuthenticationEqual(existingPolicy, newPolicy) {
+		return nil
+	}
+
+	policyCopy := existingPolicy.DeepCopy()
+	policyCopy.Spec.Selector = newPolicy.Spec.Selector
+	policyCopy.Spec.Mtls = newPolicy.Spec.Mtls
+	policyCopy.Spec.PortLevelMtls = nil
+
+	err := m.client.Patch(ctx, policyCopy, client.MergeFrom(existingPolicy))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	logrus.Debugf("Updated Istio peer authentication %s", existingPolicy.Name)
+	return nil
+}
+
+func isPeerAuthenticationEqual(existingPolicy *v1beta1.PeerAuthentication, newPolicy *v1beta1.PeerAuthentication) bool {
+	if existingPolicy.Spec.Selector == nil || existingPolicy.Spec.Mtls == nil || len(existingPolicy.Spec.PortLevelMtls) != 0 {
+		return false
+	}
+
+	sameServer := existingPolicy.Spec.Selector.MatchLabels[v1alpha3.OtterizeServiceLabelKey] == newPolicy.Spec.Selector.MatchLabels[v1alpha3.OtterizeServiceLabelKey]
+	sameMode := existingPolicy.Spec.Mtls.Mode == newPolicy.Spec.Mtls.Mode
+
+	return sameServer && sameMode
+}
+
+func (m *PeerAuthenticationManager) generatePeerAuthentication(formattedServerName string, serviceName string, namespace string) *v1beta1.PeerAuthentication {
+	return &v1beta1.PeerAuthentication{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf(OtterizePeerAuthenticationNameTemplate, serviceName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				v1alpha3.OtterizeIstioPeerAuthenticationLabelKey: formattedServerName,
+			},
+		},
+		Spec: v1beta1security.PeerAuthentication{
+			Selector: &v1beta1type.WorkloadSelector{
+				MatchLabels: map[string]string{
+					v1alpha3.OtterizeServiceLabelKey: formattedServerName,
+				},
+			},
+			Mtls: &v1beta1security.PeerAuthentication_MutualTLS{
+				Mode: v1beta1security.PeerAuthentication_MutualTLS_STRICT,
+			},
+		},
+	}
+}
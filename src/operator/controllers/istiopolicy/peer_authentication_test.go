@@ -0,0 +1,93 @@
+package istiopolicy
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	v1beta12 "istio.io/api/security/v1beta1"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+const (
+	peerAuthenticationNamespace = "test-namespace"
+	protectedServerName         = "test-server"
+	protectedServerFormatted    = "test-server-test-namespace-8ddecb"
+)
+
+type PeerAuthenticationManagerTestSuite struct {
+	testbase.MocksSuiteBase
+	manager *PeerAuthenticationManager
+}
+
+func (s *PeerAuthenticationManagerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.manager = NewPeerAuthenticationManager(s.Client)
+}
+
+func (s *PeerAuthenticationManagerTestSuite) TearDownTest() {
+	s.manager = nil
+	s.MocksSuiteBase.TearDownTest()
+}
+
+func (s *PeerAuthenticationManagerTestSuite) expectListPeerAuthentications(existing ...*v1beta1.PeerAuthentication) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.Eq(&v1beta1.PeerAuthenticationList{}), client.InNamespace(peerAuthenticationNamespace), client.HasLabels{v1alpha3.OtterizeIstioPeerAuthenticationLabelKey}).DoAndReturn(
+		func(_ context.Context, list *v1beta1.PeerAuthenticationList, _ ...client.ListOption) error {
+			list.Items = append(list.Items, existing...)
+			return nil
+		})
+}
+
+func (s *PeerAuthenticationManagerTestSuite) TestCreateStrictPeerAuthentication() {
+	expectedPolicy := s.manager.generatePeerAuthentication(protectedServerFormatted, protectedServerName, peerAuthenticationNamespace)
+	s.Equal("otterize-strict-mtls-test-server", expectedPolicy.Name)
+	s.Equal(v1beta12.PeerAuthentication_MutualTLS_STRICT, expectedPolicy.Spec.Mtls.Mode)
+	s.Equal(protectedServerFormatted, expectedPolicy.Spec.Selector.MatchLabels[v1alpha3.OtterizeServiceLabelKey])
+
+	s.expectListPeerAuthentications()
+	s.Client.EXPECT().Create(gomock.Any(), gomock.Eq(expectedPolicy)).Return(nil)
+
+	err := s.manager.ReconcileNamespace(context.Background(), peerAuthenticationNamespace, []string{protectedServerName})
+	s.NoError(err)
+}
+
+func (s *PeerAuthenticationManagerTestSuite) TestNothingToUpdate() {
+	existingPolicy := s.manager.generatePeerAuthentication(protectedServerFormatted, protectedServerName, peerAuthenticationNamespace)
+
+	s.expectListPeerAuthentications(existingPolicy)
+
+	err := s.manager.ReconcileNamespace(context.Background(), peerAuthenticationNamespace, []string{protectedServerName})
+	s.NoError(err)
+}
+
+func (s *PeerAuthenticationManagerTestSuite) TestUpdatePermissivePeerAuthentication() {
+	existingPolicy := s.manager.generatePeerAuthentication(protectedServerFormatted, protectedServerName, peerAuthenticationNamespace)
+	existingPolicy.Spec.Mtls.Mode = v1beta12.PeerAuthentication_MutualTLS_PERMISSIVE
+
+	s.expectListPeerAuthentications(existingPolicy)
+	s.Client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, policy *v1beta1.PeerAuthentication, _ client.Patch, _ ...client.PatchOption) error {
+			s.Equal(v1beta12.PeerAuthentication_MutualTLS_STRICT, policy.Spec.Mtls.Mode)
+			return nil
+		})
+
+	err := s.manager.ReconcileNamespace(context.Background(), peerAuthenticationNamespace, []string{protectedServerName})
+	s.NoError(err)
+}
+
+func (s *PeerAuthenticationManagerTestSuite) TestDeleteWhenServiceUnprotected() {
+	existingPolicy := s.manager.generatePeerAuthentication(protectedServerFormatted, protectedServerName, peerAuthenticationNamespace)
+
+	s.expectListPeerAuthentications(existingPolicy)
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.Eq(existingPolicy)).Return(nil)
+
+	err := s.manager.ReconcileNamespace(context.Background(), peerAuthenticationNamespace, []string{})
+	s.NoError(err)
+}
+
+func TestPeerAuthenticationManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(PeerAuthenticationManagerTestSuite))
+}
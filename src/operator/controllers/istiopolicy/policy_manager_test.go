@@ -5,15 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
-	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
 	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
 	"github.com/otterize/intents-operator/src/shared/testbase"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
-	v1beta12 "istio.io/api/security/v1beta1"
-	v1beta13 "istio.io/api/type/v1beta1"
-	"istio.io/client-go/pkg/apis/security/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
@@ -27,7 +23,7 @@ type PolicyManagerTestSuite struct {
 
 func (s *PolicyManagerTestSuite) SetupTest() {
 	s.MocksSuiteBase.SetupTest()
-	s.admin = NewPolicyManager(s.Client, &injectablerecorder.InjectableRecorder{Recorder: s.Recorder}, []string{}, true, true, nil)
+	s.admin = NewPolicyManager(s.Client, &injectablerecorder.InjectableRecorder{Recorder: s.Recorder})
 }
 
 func (s *PolicyManagerTestSuite) TearDownTest() {
@@ -35,1060 +31,6 @@ func (s *PolicyManagerTestSuite) TearDownTest() {
 	s.MocksSuiteBase.TearDownTest()
 }
 
-func (s *PolicyManagerTestSuite) TestCreateProtectedService() {
-	s.admin.enforcementDefaultState = false
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	newPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1alpha3.ProtectedServiceList{}), gomock.Any(), gomock.Any()).DoAndReturn(
-		func(ctx context.Context, protectedServices *v1alpha3.ProtectedServiceList, options ...client.ListOption) error {
-			svc := v1alpha3.ProtectedService{
-				ObjectMeta: v1.ObjectMeta{
-					Name:      "protected-service",
-					Namespace: clientIntentsNamespace,
-				},
-				Spec: v1alpha3.ProtectedServiceSpec{
-					Name: serverName,
-				},
-			}
-			protectedServices.Items = append(protectedServices.Items, svc)
-			return nil
-		})
-	s.Client.EXPECT().Create(gomock.Any(), newPolicy).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestCreateEnforcementDisabledNoProtectedService() {
-	s.admin.enforcementDefaultState = false
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1alpha3.ProtectedServiceList{}), gomock.Any(), gomock.Any()).DoAndReturn(
-		func(ctx context.Context, protectedServices *v1alpha3.ProtectedServiceList, options ...client.ListOption) error {
-			return nil
-		})
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(consts.ReasonEnforcementDefaultOff)
-}
-
-func (s *PolicyManagerTestSuite) TestCreateIstioEnforcementDisabledNoProtectedService() {
-	s.admin.enableIstioPolicyCreation = false
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(consts.ReasonIstioPolicyCreationDisabled)
-}
-
-func (s *PolicyManagerTestSuite) TestCreateIstioIgnoreK8sServiceIntents() {
-	s.admin.enableIstioPolicyCreation = false
-	clientName := "test-client"
-	serverName := "svc:otterservice.otternamespace"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-}
-
-func (s *PolicyManagerTestSuite) TestCreateProtectedServiceIstioEnforcementDisabled() {
-	s.admin.enableIstioPolicyCreation = false
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(consts.ReasonIstioPolicyCreationDisabled)
-}
-
-func (s *PolicyManagerTestSuite) TestCreate() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	newPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-	s.Client.EXPECT().Create(gomock.Any(), newPolicy).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestCreateHTTPResources() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-					Type: v1alpha3.IntentTypeHTTP,
-					HTTPResources: []v1alpha3.HTTPResource{
-						{
-							Path: "/login",
-							Methods: []v1alpha3.HTTPMethod{
-								v1alpha3.HTTPMethodGet,
-								v1alpha3.HTTPMethodPost,
-							},
-						},
-						{
-							Path: "/logout",
-							Methods: []v1alpha3.HTTPMethod{
-								v1alpha3.HTTPMethodPost,
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	newPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					To: []*v1beta12.Rule_To{
-						{
-							Operation: &v1beta12.Operation{
-								Paths: []string{
-									"/login",
-								},
-								Methods: []string{
-									"GET",
-									"POST",
-								},
-							},
-						},
-						{
-							Operation: &v1beta12.Operation{
-								Paths: []string{
-									"/logout",
-								},
-								Methods: []string{
-									"POST",
-								},
-							},
-						},
-					},
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-	s.Client.EXPECT().Create(gomock.Any(), newPolicy).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestUpdateHTTPResources() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-					Type: v1alpha3.IntentTypeHTTP,
-					HTTPResources: []v1alpha3.HTTPResource{
-						{
-							Path: "/login",
-							Methods: []v1alpha3.HTTPMethod{
-								v1alpha3.HTTPMethodGet,
-								v1alpha3.HTTPMethodPost,
-							},
-						},
-						{
-							Path: "/logout",
-							Methods: []v1alpha3.HTTPMethod{
-								v1alpha3.HTTPMethodPost,
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	newPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					To: []*v1beta12.Rule_To{
-						{
-							Operation: &v1beta12.Operation{
-								Paths: []string{
-									"/login",
-								},
-								Methods: []string{
-									"GET",
-									"POST",
-								},
-							},
-						},
-						{
-							Operation: &v1beta12.Operation{
-								Paths: []string{
-									"/logout",
-								},
-								Methods: []string{
-									"POST",
-								},
-							},
-						},
-					},
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	existingPolicyWithoutHTTP := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Do(
-		func(_ context.Context, policies *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) {
-			policies.Items = append(policies.Items, existingPolicyWithoutHTTP)
-		}).Return(nil)
-
-	s.Client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MergeFrom(existingPolicyWithoutHTTP))).Do(
-		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, patch client.Patch, _ ...client.PatchOption) {
-			s.Equal(newPolicy.Name, policy.Name)
-			s.Equal(newPolicy.Namespace, policy.Namespace)
-			s.Equal(newPolicy.Spec.Selector, policy.Spec.Selector)
-			s.Equal(newPolicy.Spec.Rules, policy.Spec.Rules)
-			s.Equal(newPolicy.Labels, policy.Labels)
-			s.Equal(newPolicy.Spec.Rules[0].To[0].Operation, policy.Spec.Rules[0].To[0].Operation)
-			s.Equal(newPolicy.Spec.Rules[0].To[1].Operation, policy.Spec.Rules[0].To[1].Operation)
-		}).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestNothingToUpdateHTTPResources() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-					Type: v1alpha3.IntentTypeHTTP,
-					HTTPResources: []v1alpha3.HTTPResource{
-						{
-							Path: "/login",
-							Methods: []v1alpha3.HTTPMethod{
-								v1alpha3.HTTPMethodGet,
-								v1alpha3.HTTPMethodPost,
-							},
-						},
-						{
-							Path: "/logout",
-							Methods: []v1alpha3.HTTPMethod{
-								v1alpha3.HTTPMethodPost,
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	existingPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					To: []*v1beta12.Rule_To{
-						{
-							Operation: &v1beta12.Operation{
-								Paths: []string{
-									"/login",
-								},
-								Methods: []string{
-									"GET",
-									"POST",
-								},
-							},
-						},
-						{
-							Operation: &v1beta12.Operation{
-								Paths: []string{
-									"/logout",
-								},
-								Methods: []string{
-									"POST",
-								},
-							},
-						},
-					},
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Do(
-		func(_ context.Context, policies *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) {
-			policies.Items = append(policies.Items, existingPolicy)
-		}).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestNamespaceNotAllowed() {
-	ctx := context.Background()
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-	s.admin.restrictToNamespaces = []string{fmt.Sprintf("this-is-not-%s", clientIntentsNamespace)}
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-	err := s.admin.Create(ctx, intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonNamespaceNotAllowed)
-}
-
-func (s *PolicyManagerTestSuite) TestNamespaceAllowed() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-	s.admin.restrictToNamespaces = []string{clientIntentsNamespace}
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	newPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MatchingLabels{})).Return(nil)
-	s.Client.EXPECT().Create(gomock.Any(), newPolicy).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestUpdatePolicy() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	newPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	outDatedServiceAccountPrincipal := generatePrincipal(clientIntentsNamespace, "outdated-service-account")
-	existingPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									outDatedServiceAccountPrincipal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Do(
-		func(_ context.Context, policies *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) {
-			policies.Items = append(policies.Items, existingPolicy)
-		}).Return(nil)
-
-	s.Client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(client.MergeFrom(existingPolicy))).Do(
-		func(_ context.Context, policy *v1beta1.AuthorizationPolicy, patch client.Patch, _ ...client.PatchOption) {
-			s.Equal(newPolicy.Name, policy.Name)
-			s.Equal(newPolicy.Namespace, policy.Namespace)
-			s.Equal(newPolicy.Spec.Selector, policy.Spec.Selector)
-			s.Equal(newPolicy.Spec.Rules, policy.Spec.Rules)
-			s.Equal(newPolicy.Labels, policy.Labels)
-		}).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestDeleteAllPoliciesForClientIntents() {
-	clientName := "test-client"
-	serverName1 := "test-server-1"
-	serverName2 := "test-server-2"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      "test-client-intents",
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName1,
-				},
-				{
-					Name: serverName2,
-				},
-			},
-		},
-	}
-
-	authzPol := &v1beta1.AuthorizationPolicy{ObjectMeta: v1.ObjectMeta{Name: "blah"}}
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), client.MatchingLabels{
-		v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-	}).SetArg(1, v1beta1.AuthorizationPolicyList{Items: []*v1beta1.AuthorizationPolicy{authzPol}}).Return(nil)
-
-	s.Client.EXPECT().Delete(gomock.Any(), authzPol).Return(nil)
-
-	err := s.admin.DeleteAll(context.Background(), intents)
-	s.NoError(err)
-}
-
-func (s *PolicyManagerTestSuite) TestNothingToUpdate() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	existingPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Do(
-		func(_ context.Context, policies *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) {
-			policies.Items = append(policies.Items, existingPolicy)
-		}).Return(nil)
-
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
-func (s *PolicyManagerTestSuite) TestDeletePolicy() {
-	clientName := "test-client"
-	serverName := "test-server"
-	policyName := "authorization-policy-to-test-server-from-test-client.test-namespace"
-	clientIntentsNamespace := "test-namespace"
-
-	intents := &v1alpha3.ClientIntents{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-		},
-		Spec: &v1alpha3.IntentsSpec{
-			Service: v1alpha3.Service{
-				Name: clientName,
-			},
-			Calls: []v1alpha3.Intent{
-				{
-					Name: serverName,
-				},
-			},
-		},
-	}
-	clientServiceAccountName := "test-client-sa"
-
-	principal := generatePrincipal(clientIntentsNamespace, clientServiceAccountName)
-	existingPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-test-namespace-8ddecb",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-			UID: "uid_1",
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-test-namespace-8ddecb",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	outdatedPolicy := &v1beta1.AuthorizationPolicy{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      policyName,
-			Namespace: clientIntentsNamespace,
-			Labels: map[string]string{
-				v1alpha3.OtterizeServiceLabelKey:          "test-server-from-old-intent-file",
-				v1alpha3.OtterizeIstioClientAnnotationKey: "test-client-test-namespace-537e87",
-			},
-			UID: "uid_2",
-		},
-		Spec: v1beta12.AuthorizationPolicy{
-			Selector: &v1beta13.WorkloadSelector{
-				MatchLabels: map[string]string{
-					v1alpha3.OtterizeServiceLabelKey: "test-server-from-old-intent-file",
-				},
-			},
-			Rules: []*v1beta12.Rule{
-				{
-					From: []*v1beta12.Rule_From{
-						{
-							Source: &v1beta12.Source{
-								Principals: []string{
-									principal,
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	s.Client.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).Do(
-		func(_ context.Context, policies *v1beta1.AuthorizationPolicyList, _ ...client.ListOption) {
-			policies.Items = append(policies.Items, outdatedPolicy, existingPolicy)
-		}).Return(nil)
-
-	s.Client.EXPECT().Delete(gomock.Any(), outdatedPolicy).Return(nil)
-	err := s.admin.Create(context.Background(), intents, clientServiceAccountName)
-	s.NoError(err)
-	s.ExpectEvent(ReasonCreatedIstioPolicy)
-}
-
 func (s *PolicyManagerTestSuite) TestUpdateStatusServiceAccount() {
 	clientName := "test-client"
 	serverName := "test-server"
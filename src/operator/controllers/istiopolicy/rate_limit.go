@@ -0,0 +1,314 @@
+package istiopolicy
+
+import (
+	"context"
+	"fmt"
+	"github.com/amit7itz/goset"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	istionetworking "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+)
+
+const (
+	OtterizeIstioRateLimitNameTemplate = "rate-limit-to-%s"
+	ReasonCreatingRateLimitFailed      = "CreatingIstioRateLimitFailed"
+	ReasonUpdatingRateLimitFailed      = "UpdatingIstioRateLimitFailed"
+	ReasonDeleteRateLimitFailed        = "DeleteIstioRateLimitFailed"
+	ReasonCreatedRateLimit             = "CreatedIstioRateLimit"
+	ReasonRateLimitNotSupported        = "IstioRateLimitNotSupported"
+	localRateLimitFilterName           = "envoy.filters.http.local_ratelimit"
+	localRateLimitTypeURL              = "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit"
+	rateLimitDescriptorKey             = "otterize_client"
+	// unlimitedTokens is the budget of clients without a rate limit - the local rate limit filter always requires one.
+	unlimitedTokens = 4294967295
+)
+
+//+kubebuilder:rbac:groups="networking.istio.io",resources=envoyfilters,verbs=get;update;patch;list;watch;delete;create
+
+var rateLimitFillIntervals = map[v1alpha3.RateLimitUnit]string{
+	v1alpha3.RateLimitUnitSecond: "1s",
+	v1alpha3.RateLimitUnitMinute: "60s",
+	v1alpha3.RateLimitUnitHour:   "3600s",
+}
+
+// buildRateLimitEnvoyFilter builds an EnvoyFilter that applies Envoy's local rate limiting on the server's sidecar, with
+// a separate token bucket for every client that has a rate limit. Istio sidecars add the client's SPIFFE identity to
+// the x-forwarded-client-cert header of inbound requests, which is used to tell the clients apart.
+func buildRateLimitEnvoyFilter(ep effectivepolicy.ServiceEffectivePolicy, policy *v1beta1.AuthorizationPolicy) (*istionetworking.EnvoyFilter, bool, error) {
+	limitsByPrincipal := make(map[string]v1alpha3.RateLimit)
+	for _, clientCall := range ep.CalledBy {
+		rateLimit := clientCall.IntendedCall.RateLimit
+		if rateLimit == nil || clientCall.IntendedCall.Type != v1alpha3.IntentTypeHTTP {
+			continue
+		}
+
+		principal, ok := clientPrincipal(clientCall)
+		if !ok {
+			continue
+		}
+
+		// Clients sharing a service account cannot be told apart, so the strictest limit applies to all of them.
+		existing, found := limitsByPrincipal[principal]
+		if !found || rateLimitPerHour(*rateLimit) < rateLimitPerHour(existing) {
+			limitsByPrincipal[principal] = *rateLimit
+		}
+	}
+
+	if len(limitsByPrincipal) == 0 {
+		return nil, false, nil
+	}
+
+	principals := lo.Keys(limitsByPrincipal)
+	sort.Strings(principals)
+
+	filterPatch, err := structpb.NewStruct(map[string]any{
+		"name": localRateLimitFilterName,
+		"typed_config": map[string]any{
+			"@type":    "type.googleapis.com/udpa.type.v1.TypedStruct",
+			"type_url": localRateLimitTypeURL,
+			"value":    map[string]any{"stat_prefix": "otterize_http_local_rate_limiter"},
+		},
+	})
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+
+	routePatch, err := structpb.NewStruct(map[string]any{
+		"route": map[string]any{
+			"rate_limits": lo.Map(principals, func(principal string, _ int) any {
+				return map[string]any{"actions": []any{principalRateLimitAction(principal)}}
+			}),
+		},
+		"typed_per_filter_config": map[string]any{
+			localRateLimitFilterName: map[string]any{
+				"@type":    "type.googleapis.com/udpa.type.v1.TypedStruct",
+				"type_url": localRateLimitTypeURL,
+				"value": map[string]any{
+					"stat_prefix":                         "otterize_http_local_rate_limiter",
+					"token_bucket":                        tokenBucket(unlimitedTokens, "1s"),
+					"filter_enabled":                      fullRuntimeFraction("local_rate_limit_enabled"),
+					"filter_enforced":                     fullRuntimeFraction("local_rate_limit_enforced"),
+					"always_consume_default_token_bucket": false,
+					"descriptors": lo.Map(principals, func(principal string, _ int) any {
+						rateLimit := limitsByPrincipal[principal]
+						return map[string]any{
+							"entries":      []any{map[string]any{"key": rateLimitDescriptorKey, "value": principal}},
+							"token_bucket": tokenBucket(rateLimit.Requests, rateLimitFillIntervals[rateLimitUnit(rateLimit)]),
+						}
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, false, errors.Wrap(err)
+	}
+
+	return &istionetworking.EnvoyFilter{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf(OtterizeIstioRateLimitNameTemplate, ep.Service.GetNameWithKind()),
+			Namespace: ep.Service.Namespace,
+			Labels:    policy.Labels,
+		},
+		Spec: networkingv1alpha3.EnvoyFilter{
+			WorkloadSelector: &networkingv1alpha3.WorkloadSelector{Labels: policy.Spec.Selector.MatchLabels},
+			ConfigPatches: []*networkingv1alpha3.EnvoyFilter_EnvoyConfigObjectPatch{
+				{
+					ApplyTo: networkingv1alpha3.EnvoyFilter_HTTP_FILTER,
+					Match: &networkingv1alpha3.EnvoyFilter_EnvoyConfigObjectMatch{
+						Context: networkingv1alpha3.EnvoyFilter_SIDECAR_INBOUND,
+						ObjectTypes: &networkingv1alpha3.EnvoyFilter_EnvoyConfigObjectMatch_Listener{
+							Listener: &networkingv1alpha3.EnvoyFilter_ListenerMatch{
+								FilterChain: &networkingv1alpha3.EnvoyFilter_ListenerMatch_FilterChainMatch{
+									Filter: &networkingv1alpha3.EnvoyFilter_ListenerMatch_FilterMatch{
+										Name:      "envoy.filters.network.http_connection_manager",
+										SubFilter: &networkingv1alpha3.EnvoyFilter_ListenerMatch_SubFilterMatch{Name: "envoy.filters.http.router"},
+									},
+								},
+							},
+						},
+					},
+					Patch: &networkingv1alpha3.EnvoyFilter_Patch{
+						Operation: networkingv1alpha3.EnvoyFilter_Patch_INSERT_BEFORE,
+						Value:     filterPatch,
+					},
+				},
+				{
+					ApplyTo: networkingv1alpha3.EnvoyFilter_HTTP_ROUTE,
+					Match: &networkingv1alpha3.EnvoyFilter_EnvoyConfigObjectMatch{
+						Context: networkingv1alpha3.EnvoyFilter_SIDECAR_INBOUND,
+					},
+					Patch: &networkingv1alpha3.EnvoyFilter_Patch{
+						Operation: networkingv1alpha3.EnvoyFilter_Patch_MERGE,
+						Value:     routePatch,
+					},
+				},
+			},
+		},
+	}, true, nil
+}
+
+// principalRateLimitAction matches requests of the principal by the x-forwarded-client-cert header. With Istio's default
+// APPEND_FORWARD mode, the header may contain elements forwarded from the client, so a client could add an element
+// with another principal's identity. Only the last element is added by the server's sidecar, from the certificate of
+// the connection, so the principal must be in it. Requests without mTLS, whose header is forwarded as is, are denied
+// by the authorization policy before reaching the rate limit filter.
+func principalRateLimitAction(principal string) map[string]any {
+	return map[string]any{
+		"header_value_match": map[string]any{
+			"descriptor_key":   rateLimitDescriptorKey,
+			"descriptor_value": principal,
+			"headers": []any{
+				map[string]any{
+					"name": "x-forwarded-client-cert",
+					"string_match": map[string]any{
+						"safe_regex": map[string]any{
+							"regex": principalXFCCRegex(principal),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// principalXFCCRegex matches an x-forwarded-client-cert header whose last element has the principal's URI. Elements
+// are separated by commas, and Istio workload certificates have no subject or DNS names that could contain one.
+func principalXFCCRegex(principal string) string {
+	return fmt.Sprintf("^(.*,)?([^,]*;)?URI=spiffe://%s(;[^,]*)?$", regexp.QuoteMeta(principal))
+}
+
+func tokenBucket(tokens int, fillInterval string) map[string]any {
+	return map[string]any{
+		"max_tokens":      tokens,
+		"tokens_per_fill": tokens,
+		"fill_interval":   fillInterval,
+	}
+}
+
+func fullRuntimeFraction(runtimeKey string) map[string]any {
+	return map[string]any{
+		"runtime_key":   runtimeKey,
+		"default_value": map[string]any{"numerator": 100, "denominator": "HUNDRED"},
+	}
+}
+
+func rateLimitUnit(rateLimit v1alpha3.RateLimit) v1alpha3.RateLimitUnit {
+	if rateLimit.Per == "" {
+		return v1alpha3.RateLimitUnitSecond
+	}
+	return rateLimit.Per
+}
+
+func rateLimitPerHour(rateLimit v1alpha3.RateLimit) int {
+	switch rateLimitUnit(rateLimit) {
+	case v1alpha3.RateLimitUnitMinute:
+		return rateLimit.Requests * 60
+	case v1alpha3.RateLimitUnitHour:
+		return rateLimit.Requests
+	default:
+		return rateLimit.Requests * 3600
+	}
+}
+
+// applyServerRateLimit applies the rate limits of the server's clients. Rate limits are enforced by the server's
+// sidecar, so they are not supported for servers whose policy is enforced by an ambient waypoint.
+func (r *EffectivePolicyReconciler) applyServerRateLimit(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, policy *v1beta1.AuthorizationPolicy) (types.NamespacedName, bool, error) {
+	hasRateLimits := lo.SomeBy(ep.CalledBy, func(clientCall effectivepolicy.ClientCall) bool { return clientCall.IntendedCall.RateLimit != nil })
+	if !hasRateLimits {
+		return types.NamespacedName{}, false, nil
+	}
+
+	if policy.Spec.Selector == nil {
+		ep.RecordOnClientsWarningEventf(ReasonRateLimitNotSupported, "Server %s is in the Istio ambient mesh, rate limits are only supported for servers with a sidecar", ep.Service.Name)
+		return types.NamespacedName{}, false, nil
+	}
+
+	envoyFilter, shouldCreate, err := buildRateLimitEnvoyFilter(ep, policy)
+	if err != nil {
+		return types.NamespacedName{}, false, errors.Wrap(err)
+	}
+	if !shouldCreate {
+		return types.NamespacedName{}, false, nil
+	}
+
+	name, err := r.applyRateLimit(ctx, ep, envoyFilter)
+	if err != nil {
+		return types.NamespacedName{}, false, errors.Wrap(err)
+	}
+
+	return name, true, nil
+}
+
+func (r *EffectivePolicyReconciler) applyRateLimit(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, newEnvoyFilter *istionetworking.EnvoyFilter) (types.NamespacedName, error) {
+	name := types.NamespacedName{Name: newEnvoyFilter.Name, Namespace: newEnvoyFilter.Namespace}
+	existing := &istionetworking.EnvoyFilter{}
+	err := r.Get(ctx, name, existing)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return types.NamespacedName{}, errors.Wrap(err)
+	}
+
+	if k8serrors.IsNotFound(err) {
+		err = r.Create(ctx, newEnvoyFilter)
+		if err != nil {
+			ep.RecordOnClientsWarningEventf(ReasonCreatingRateLimitFailed, "Failed to create Istio rate limit: %s", err.Error())
+			return types.NamespacedName{}, errors.Wrap(err)
+		}
+		ep.RecordOnClientsNormalEventf(ReasonCreatedRateLimit, "Istio rate limit created for %s", ep.Service.Name)
+		return name, nil
+	}
+
+	if proto.Equal(&existing.Spec, &newEnvoyFilter.Spec) && reflect.DeepEqual(existing.Labels, newEnvoyFilter.Labels) {
+		return name, nil
+	}
+
+	existingCopy := existing.DeepCopy()
+	existingCopy.Labels = newEnvoyFilter.Labels
+	existingCopy.Spec.WorkloadSelector = newEnvoyFilter.Spec.WorkloadSelector
+	existingCopy.Spec.ConfigPatches = newEnvoyFilter.Spec.ConfigPatches
+	err = r.Patch(ctx, existingCopy, client.MergeFrom(existing))
+	if err != nil {
+		ep.RecordOnClientsWarningEventf(ReasonUpdatingRateLimitFailed, "Failed to update Istio rate limit: %s", err.Error())
+		return types.NamespacedName{}, errors.Wrap(err)
+	}
+
+	ep.RecordOnClientsNormalEventf(ReasonCreatedRateLimit, "Istio rate limit updated for %s", ep.Service.Name)
+	return name, nil
+}
+
+func (r *EffectivePolicyReconciler) removeRateLimitsThatShouldNotExist(ctx context.Context, rateLimitsThatShouldExist *goset.Set[types.NamespacedName]) error {
+	var existingEnvoyFilters istionetworking.EnvoyFilterList
+	err := r.List(ctx, &existingEnvoyFilters, client.HasLabels{v1alpha3.OtterizeServiceLabelKey})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	for _, envoyFilter := range existingEnvoyFilters.Items {
+		if rateLimitsThatShouldExist.Contains(types.NamespacedName{Name: envoyFilter.Name, Namespace: envoyFilter.Namespace}) {
+			continue
+		}
+
+		logrus.Debugf("Removing orphaned Istio rate limit: %s in namespace %s", envoyFilter.Name, envoyFilter.Namespace)
+		err = r.Delete(ctx, envoyFilter)
+		if client.IgnoreNotFound(err) != nil {
+			r.RecordWarningEventf(envoyFilter, ReasonDeleteRateLimitFailed, "Failed to delete Istio rate limit: %s", err.Error())
+			return errors.Wrap(err)
+		}
+	}
+
+	return nil
+}
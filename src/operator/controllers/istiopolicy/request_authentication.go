@@ -0,0 +1,204 @@
+package istiopolicy
+
+import (
+	"context"
+	"fmt"
+	"github.com/amit7itz/goset"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	v1beta1security "istio.io/api/security/v1beta1"
+	"istio.io/client-go/pkg/apis/security/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+)
+
+const (
+	OtterizeIstioRequestAuthenticationNameTemplate = "request-authentication-to-%s"
+	ReasonCreatingRequestAuthenticationFailed      = "CreatingIstioRequestAuthenticationFailed"
+	ReasonUpdatingRequestAuthenticationFailed      = "UpdatingIstioRequestAuthenticationFailed"
+	ReasonDeleteRequestAuthenticationFailed        = "DeleteIstioRequestAuthenticationFailed"
+	ReasonCreatedRequestAuthentication             = "CreatedIstioRequestAuthentication"
+)
+
+//+kubebuilder:rbac:groups="security.istio.io",resources=requestauthentications,verbs=get;update;patch;list;watch;delete;create
+
+// getServerJWTRequirement returns the token required by the ProtectedService of the server, if any.
+func (r *EffectivePolicyReconciler) getServerJWTRequirement(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy) (*v1alpha3.JWTRequirement, error) {
+	var protectedServices v1alpha3.ProtectedServiceList
+	err := r.List(ctx, &protectedServices,
+		client.MatchingFields{v1alpha3.OtterizeProtectedServiceNameIndexField: ep.Service.Name},
+		client.InNamespace(ep.Service.Namespace))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	for _, protectedService := range protectedServices.Items {
+		if protectedService.Spec.JWT != nil {
+			return protectedService.Spec.JWT, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// applyServerJWTRequirement requires the server's token in every rule of the policy. Rules may already require a token
+// of their own through request principals, so the server's requirement is added as conditions, which must all be met.
+func applyServerJWTRequirement(policy *v1beta1.AuthorizationPolicy, serverJWT *v1alpha3.JWTRequirement) {
+	if serverJWT == nil {
+		return
+	}
+
+	conditions := append([]*v1beta1security.Condition{
+		{Key: "request.auth.principal", Values: jwtRequirementToRequestPrincipals(serverJWT)},
+	}, jwtRequirementToIstioConditions(serverJWT)...)
+
+	for _, rule := range policy.Spec.Rules {
+		rule.When = append(rule.When, conditions...)
+	}
+}
+
+func jwtRequirementToRequestPrincipals(requirement *v1alpha3.JWTRequirement) []string {
+	if requirement == nil {
+		return nil
+	}
+
+	if len(requirement.Subjects) == 0 {
+		return []string{fmt.Sprintf("%s/*", requirement.Issuer)}
+	}
+
+	return lo.Map(requirement.Subjects, func(subject string, _ int) string {
+		return fmt.Sprintf("%s/%s", requirement.Issuer, subject)
+	})
+}
+
+func jwtRequirementToIstioConditions(requirement *v1alpha3.JWTRequirement) []*v1beta1security.Condition {
+	if requirement == nil {
+		return nil
+	}
+
+	conditions := make([]*v1beta1security.Condition, 0)
+	if len(requirement.Audiences) != 0 {
+		conditions = append(conditions, &v1beta1security.Condition{Key: "request.auth.audiences", Values: requirement.Audiences})
+	}
+
+	for _, claim := range requirement.Claims {
+		conditions = append(conditions, &v1beta1security.Condition{
+			Key:    fmt.Sprintf("request.auth.claims[%s]", claim.Name),
+			Values: claim.Values,
+		})
+	}
+
+	return conditions
+}
+
+// buildRequestAuthentication builds the RequestAuthentication that validates the tokens required by the server's policy.
+// It applies to the same workloads as the policy. Audiences are checked by the policy, per rule, so the JWT rules
+// accept any audience.
+func buildRequestAuthentication(ep effectivepolicy.ServiceEffectivePolicy, policy *v1beta1.AuthorizationPolicy, serverJWT *v1alpha3.JWTRequirement) (*v1beta1.RequestAuthentication, bool) {
+	jwksURIByIssuer := make(map[string]string)
+	addRequirement := func(requirement *v1alpha3.JWTRequirement) {
+		if requirement == nil {
+			return
+		}
+		if jwksURIByIssuer[requirement.Issuer] == "" {
+			jwksURIByIssuer[requirement.Issuer] = requirement.JWKSURI
+		}
+	}
+
+	addRequirement(serverJWT)
+	for _, clientCall := range ep.CalledBy {
+		for _, resource := range clientCall.IntendedCall.HTTPResources {
+			addRequirement(resource.JWT)
+		}
+	}
+
+	if len(jwksURIByIssuer) == 0 {
+		return nil, false
+	}
+
+	issuers := lo.Keys(jwksURIByIssuer)
+	sort.Strings(issuers)
+
+	return &v1beta1.RequestAuthentication{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf(OtterizeIstioRequestAuthenticationNameTemplate, ep.Service.GetNameWithKind()),
+			Namespace: ep.Service.Namespace,
+			Labels:    policy.Labels,
+		},
+		Spec: v1beta1security.RequestAuthentication{
+			Selector:   policy.Spec.Selector,
+			TargetRefs: policy.Spec.TargetRefs,
+			JwtRules: lo.Map(issuers, func(issuer string, _ int) *v1beta1security.JWTRule {
+				return &v1beta1security.JWTRule{Issuer: issuer, JwksUri: jwksURIByIssuer[issuer]}
+			}),
+		},
+	}, true
+}
+
+func (r *EffectivePolicyReconciler) applyRequestAuthentication(ctx context.Context, ep effectivepolicy.ServiceEffectivePolicy, newRequestAuthentication *v1beta1.RequestAuthentication) (types.NamespacedName, error) {
+	name := types.NamespacedName{Name: newRequestAuthentication.Name, Namespace: newRequestAuthentication.Namespace}
+	existing := &v1beta1.RequestAuthentication{}
+	err := r.Get(ctx, name, existing)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return types.NamespacedName{}, errors.Wrap(err)
+	}
+
+	if k8serrors.IsNotFound(err) {
+		err = r.Create(ctx, newRequestAuthentication)
+		if err != nil {
+			ep.RecordOnClientsWarningEventf(ReasonCreatingRequestAuthenticationFailed, "Failed to create Istio request authentication: %s", err.Error())
+			return types.NamespacedName{}, errors.Wrap(err)
+		}
+		ep.RecordOnClientsNormalEventf(ReasonCreatedRequestAuthentication, "Istio request authentication created for %s", ep.Service.Name)
+		return name, nil
+	}
+
+	if proto.Equal(&existing.Spec, &newRequestAuthentication.Spec) && reflect.DeepEqual(existing.Labels, newRequestAuthentication.Labels) {
+		return name, nil
+	}
+
+	existingCopy := existing.DeepCopy()
+	existingCopy.Labels = newRequestAuthentication.Labels
+	existingCopy.Spec.Selector = newRequestAuthentication.Spec.Selector
+	existingCopy.Spec.TargetRefs = newRequestAuthentication.Spec.TargetRefs
+	existingCopy.Spec.JwtRules = newRequestAuthentication.Spec.JwtRules
+	err = r.Patch(ctx, existingCopy, client.MergeFrom(existing))
+	if err != nil {
+		ep.RecordOnClientsWarningEventf(ReasonUpdatingRequestAuthenticationFailed, "Failed to update Istio request authentication: %s", err.Error())
+		return types.NamespacedName{}, errors.Wrap(err)
+	}
+
+	ep.RecordOnClientsNormalEventf(ReasonCreatedRequestAuthentication, "Istio request authentication updated for %s", ep.Service.Name)
+	return name, nil
+}
+
+func (r *EffectivePolicyReconciler) removeRequestAuthenticationsThatShouldNotExist(ctx context.Context, requestAuthenticationsThatShouldExist *goset.Set[types.NamespacedName]) error {
+	var existingRequestAuthentications v1beta1.RequestAuthenticationList
+	err := r.List(ctx, &existingRequestAuthentications, client.HasLabels{v1alpha3.OtterizeServiceLabelKey})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	for _, requestAuthentication := range existingRequestAuthentications.Items {
+		if requestAuthenticationsThatShouldExist.Contains(types.NamespacedName{Name: requestAuthentication.Name, Namespace: requestAuthentication.Namespace}) {
+			continue
+		}
+
+		logrus.Debugf("Removing orphaned Istio request authentication: %s in namespace %s", requestAuthentication.Name, requestAuthentication.Namespace)
+		err = r.Delete(ctx, requestAuthentication)
+		if client.IgnoreNotFound(err) != nil {
+			r.RecordWarningEventf(requestAuthentication, ReasonDeleteRequestAuthenticationFailed, "Failed to delete Istio request authentication: %s", err.Error())
+			return errors.Wrap(err)
+		}
+	}
+
+	return nil
+}
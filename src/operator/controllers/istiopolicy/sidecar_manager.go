@@ -0,0 +1,235 @@
+package istiopolicy
+
+import (
+	"context"
+	"fmt"
+	"github.com/amit7itz/goset"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/protected_services"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	v1beta1networking "istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ReasonCreatingIstioSidecarFailed     = "CreatingIstioSidecarFailed"
+	ReasonIstioSidecarEgressUnrestricted = "IstioSidecarEgressUnrestricted"
+	OtterizeIstioSidecarNameTemplate     = "otterize-egress-%s"
+	// istioControlPlaneHosts keeps the workload's access to the control plane namespace, as in Istio's default Sidecar.
+	istioControlPlaneHosts = "istio-system/*"
+)
+
+//+kubebuilder:rbac:groups="networking.istio.io",resources=sidecars,verbs=get;update;patch;list;watch;delete;create
+
+// SidecarManager restricts the egress of a client's sidecar proxy to the targets of its intents. Besides constraining
+// egress at the proxy, this limits the mesh configuration pushed to each sidecar to the services it actually calls.
+type SidecarManager struct {
+	client                  client.Client
+	recorder                *injectablerecorder.InjectableRecorder
+	serviceIdResolver       serviceidresolver.ServiceResolver
+	restrictToNamespaces    []string
+	egressGateway           types.NamespacedName
+	enforcementDefaultState bool
+	enforcedNamespaces      *goset.Set[string]
+}
+
+func NewSidecarManager(client client.Client, recorder *injectablerecorder.InjectableRecorder, serviceIdResolver serviceidresolver.ServiceResolver, restrictedNamespaces []string, egressGateway types.NamespacedName, enforcementDefaultState bool, enforcedNamespaces *goset.Set[string]) *SidecarManager {
+	return &SidecarManager{
+		client:                  client,
+		recorder:                recorder,
+		serviceIdResolver:       serviceIdResolver,
+		restrictToNamespaces:    restrictedNamespaces,
+		egressGateway:           egressGateway,
+		enforcementDefaultState: enforcementDefaultState,
+		enforcedNamespaces:      enforcedNamespaces,
+	}
+}
+
+func (m *SidecarManager) DeleteAll(ctx context.Context, clientIntents *v1alpha3.ClientIntents) error {
+	clientName := clientIntents.GetServiceName()
+	sidecar := &networkingv1beta1.Sidecar{ObjectMeta: v1.ObjectMeta{
+		Name:      fmt.Sprintf(OtterizeIstioSidecarNameTemplate, clientName),
+		Namespace: clientIntents.Namespace,
+	}}
+	err := m.client.Delete(ctx, sidecar)
+	if client.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+// Create generates the client's Sidecar. The domains of the client's internet intents are registered in the mesh by
+// InternetEgressManager, and are reachable through the Sidecar as well.
+func (m *SidecarManager) Create(ctx context.Context, clientIntents *v1alpha3.ClientIntents) error {
+	// In shadow mode, egress is restricted for clients in enforced namespaces or that are protected services themselves.
+	shouldEnforce, err := protected_services.IsServerEnforcementEnabledDueToProtectionOrDefaultState(ctx, m.client, clientIntents.GetServiceName(), clientIntents.Namespace, m.enforcementDefaultState, m.enforcedNamespaces)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if !shouldEnforce {
+		m.recorder.RecordNormalEvent(clientIntents, consts.ReasonEnforcementDefaultOff, "Enforcement is disabled globally and the client is not protected, Istio sidecar egress restriction skipped")
+		return m.DeleteAll(ctx, clientIntents)
+	}
+
+	if len(m.restrictToNamespaces) != 0 && !lo.Contains(m.restrictToNamespaces, clientIntents.Namespace) {
+		m.recorder.RecordWarningEventf(clientIntents, ReasonNamespaceNotAllowed, "ClientIntents are in namespace %s but namespace is not allowed by configuration, Istio sidecar egress restriction skipped", clientIntents.Namespace)
+		return nil
+	}
+
+	hosts, err := m.getEgressHosts(ctx, clientIntents)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	domains := internetDomains(clientIntents)
+	hosts.Insert(lo.Map(domains, func(domain string, _ int) string { return "./" + domain })...)
+	if len(domains) != 0 && m.egressGateway.Name != "" {
+		hosts.Insert(formatSidecarHost(m.egressGateway.Name, m.egressGateway.Namespace))
+		// The VirtualServices routing domains through the egress gateway live in the gateway's namespace.
+		routedDomains, _ := egressGatewayDomains(clientIntents)
+		hosts.Insert(lo.Map(routedDomains, func(domain string, _ int) string { return m.egressGateway.Namespace + "/" + domain })...)
+	}
+
+	// The Sidecar can only express egress to hosts registered in the mesh, so clients with intents to IPs, cloud
+	// resources, databases or external Kafka servers keep passthrough egress and only get the configuration-scoping
+	// benefit.
+	outboundMode := v1beta1networking.OutboundTrafficPolicy_REGISTRY_ONLY
+	if lo.ContainsBy(clientIntents.GetCallsList(), requiresUnrestrictedEgress) {
+		outboundMode = v1beta1networking.OutboundTrafficPolicy_ALLOW_ANY
+		m.recorder.RecordWarningEvent(clientIntents, ReasonIstioSidecarEgressUnrestricted, "Intents to IPs, cloud resources, databases or external Kafka servers cannot be enforced by the Istio sidecar, egress to unknown destinations is allowed")
+	}
+
+	sidecar := m.generateSidecar(clientIntents, sets.List(hosts), outboundMode)
+	err = m.createOrUpdateSidecar(ctx, sidecar)
+	if err != nil {
+		m.recorder.RecordWarningEventf(clientIntents, ReasonCreatingIstioSidecarFailed, "Failed to create Istio sidecar: %s", err.Error())
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+// getEgressHosts returns the in-cluster hosts the client may call, in the Sidecar `namespace/dnsName` format. Servers are
+// mapped to the Kubernetes services targeting their pods; if the server's pods cannot be found, a service named after the
+// server is assumed.
+func (m *SidecarManager) getEgressHosts(ctx context.Context, clientIntents *v1alpha3.ClientIntents) (sets.Set[string], error) {
+	hosts := sets.New[string](istioControlPlaneHosts)
+	for _, intent := range clientIntents.GetCallsList() {
+		if !intent.IsTargetInCluster() {
+			continue
+		}
+
+		serverNamespace := intent.GetTargetServerNamespace(clientIntents.Namespace)
+		if intent.IsTargetServerKubernetesService() || intent.IsTargetTheKubernetesAPIServer(clientIntents.Namespace) {
+			hosts.Insert(formatSidecarHost(intent.GetTargetServerName(), serverNamespace))
+			continue
+		}
+
+		pod, err := m.serviceIdResolver.ResolveIntentServerToPod(ctx, intent, serverNamespace)
+		if err != nil && !errors.Is(err, serviceidresolver.ErrPodNotFound) {
+			return nil, errors.Wrap(err)
+		}
+		if errors.Is(err, serviceidresolver.ErrPodNotFound) {
+			hosts.Insert(formatSidecarHost(intent.GetTargetServerName(), serverNamespace))
+			continue
+		}
+
+		services, err := m.serviceIdResolver.GetKubernetesServicesTargetingPod(ctx, &pod)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if len(services) == 0 {
+			logrus.Debugf("No services target server %s in namespace %s, assuming a service of the same name", intent.GetTargetServerName(), serverNamespace)
+			hosts.Insert(formatSidecarHost(intent.GetTargetServerName(), serverNamespace))
+			continue
+		}
+		for _, service := range services {
+			hosts.Insert(formatSidecarHost(service.Name, service.Namespace))
+		}
+	}
+
+	return hosts, nil
+}
+
+// requiresUnrestrictedEgress returns whether the intent's target is outside the cluster and has no host the Sidecar can
+// list, such as IPs, cloud provider APIs and databases.
+func requiresUnrestrictedEgress(intent v1alpha3.Intent) bool {
+	if intent.IsTargetInCluster() {
+		return false
+	}
+	if intent.Type == v1alpha3.IntentTypeInternet {
+		return intent.Internet != nil && len(intent.Internet.Ips) != 0
+	}
+	return true
+}
+
+func formatSidecarHost(serviceName string, namespace string) string {
+	return fmt.Sprintf("%s/%s.%s.svc.cluster.local", namespace, serviceName, namespace)
+}
+
+func (m *SidecarManager) generateSidecar(clientIntents *v1alpha3.ClientIntents, hosts []string, outboundMode v1beta1networking.OutboundTrafficPolicy_Mode) *networkingv1beta1.Sidecar {
+	clientFormattedIdentity := v1alpha3.GetFormattedOtterizeIdentity(clientIntents.GetServiceName(), clientIntents.Namespace)
+	return &networkingv1beta1.Sidecar{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf(OtterizeIstioSidecarNameTemplate, clientIntents.GetServiceName()),
+			Namespace: clientIntents.Namespace,
+			Labels: map[string]string{
+				v1alpha3.OtterizeIstioClientAnnotationKey: clientFormattedIdentity,
+			},
+		},
+		Spec: v1beta1networking.Sidecar{
+			WorkloadSelector: &v1beta1networking.WorkloadSelector{
+				Labels: map[string]string{
+					v1alpha3.OtterizeServiceLabelKey: clientFormattedIdentity,
+				},
+			},
+			Egress: []*v1beta1networking.IstioEgressListener{
+				{Hosts: hosts},
+			},
+			OutboundTrafficPolicy: &v1beta1networking.OutboundTrafficPolicy{
+				Mode: outboundMode,
+			},
+		},
+	}
+}
+
+func (m *SidecarManager) createOrUpdateSidecar(ctx context.Context, newSidecar *networkingv1beta1.Sidecar) error {
+	existingSidecar := &networkingv1beta1.Sidecar{}
+	err := m.client.Get(ctx, types.NamespacedName{Namespace: newSidecar.Namespace, Name: newSidecar.Name}, existingSidecar)
+	if k8serrors.IsNotFound(err) {
+		err = m.client.Create(ctx, newSidecar)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if proto.Equal(&existingSidecar.Spec, &newSidecar.Spec) {
+		return nil
+	}
+
+	sidecarCopy := existingSidecar.DeepCopy()
+	newSidecar.Spec.DeepCopyInto(&sidecarCopy.Spec)
+	err = m.client.Patch(ctx, sidecarCopy, client.MergeFrom(existingSidecar))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package istiopolicy
+
+import (
+	"context"
+	"github.com/amit7itz/goset"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver"
+	serviceidresolvermocks "github.com/otterize/intents-operator/src/shared/serviceidresolver/mocks"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	v1beta1networking "istio.io/api/networking/v1beta1"
+	networkingv1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+type SidecarManagerTestSuite struct {
+	testbase.MocksSuiteBase
+	serviceResolver *serviceidresolvermocks.MockServiceResolver
+	manager         *SidecarManager
+}
+
+func (s *SidecarManagerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.serviceResolver = serviceidresolvermocks.NewMockServiceResolver(s.Controller)
+	s.manager = NewSidecarManager(s.Client, &injectablerecorder.InjectableRecorder{Recorder: s.Recorder}, s.serviceResolver, []string{}, types.NamespacedName{}, true, nil)
+}
+
+func (s *SidecarManagerTestSuite) TearDownTest() {
+	s.manager = nil
+	s.serviceResolver = nil
+	s.MocksSuiteBase.TearDownTest()
+}
+
+func sidecarTestIntents(calls ...v1alpha3.Intent) *v1alpha3.ClientIntents {
+	return &v1alpha3.ClientIntents{
+		ObjectMeta: v1.ObjectMeta{Name: "client-intents", Namespace: "test-namespace"},
+		Spec: &v1alpha3.IntentsSpec{
+			Service: v1alpha3.Service{Name: "test-client"},
+			Calls:   calls,
+		},
+	}
+}
+
+func (s *SidecarManagerTestSuite) TestCreate() {
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Name: "test-server"},
+		v1alpha3.Intent{Name: "svc:other-service.other-namespace"},
+		v1alpha3.Intent{Name: "missing-server"},
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+
+	serverPod := corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "test-server-pod", Namespace: "test-namespace"}}
+	s.serviceResolver.EXPECT().ResolveIntentServerToPod(gomock.Any(), intents.Spec.Calls[0], "test-namespace").Return(serverPod, nil)
+	s.serviceResolver.EXPECT().GetKubernetesServicesTargetingPod(gomock.Any(), &serverPod).Return([]corev1.Service{
+		{ObjectMeta: v1.ObjectMeta{Name: "test-server-svc", Namespace: "test-namespace"}},
+	}, nil)
+	s.serviceResolver.EXPECT().ResolveIntentServerToPod(gomock.Any(), intents.Spec.Calls[2], "test-namespace").Return(corev1.Pod{}, serviceidresolver.ErrPodNotFound)
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "")
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).DoAndReturn(
+		func(_ context.Context, sidecar *networkingv1beta1.Sidecar, _ ...any) error {
+			s.Equal("otterize-egress-test-client", sidecar.Name)
+			s.Equal([]string{
+				"./api.example.com",
+				"istio-system/*",
+				"other-namespace/other-service.other-namespace.svc.cluster.local",
+				"test-namespace/missing-server.test-namespace.svc.cluster.local",
+				"test-namespace/test-server-svc.test-namespace.svc.cluster.local",
+			}, sidecar.Spec.Egress[0].Hosts)
+			s.Equal(v1beta1networking.OutboundTrafficPolicy_REGISTRY_ONLY, sidecar.Spec.OutboundTrafficPolicy.Mode)
+			return nil
+		})
+
+	err := s.manager.Create(context.Background(), intents)
+	s.NoError(err)
+	s.ExpectNoEvent()
+}
+
+func (s *SidecarManagerTestSuite) TestEgressGatewayReachableWithInternetDomains() {
+	s.manager.egressGateway = types.NamespacedName{Name: "istio-egressgateway", Namespace: "istio-system"}
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeInternet, Internet: &v1alpha3.Internet{Domains: []string{"api.example.com"}}},
+	)
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "")
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).DoAndReturn(
+		func(_ context.Context, sidecar *networkingv1beta1.Sidecar, _ ...any) error {
+			s.Equal([]string{
+				"./api.example.com",
+				"istio-system/*",
+				"istio-system/api.example.com",
+				"istio-system/istio-egressgateway.istio-system.svc.cluster.local",
+			}, sidecar.Spec.Egress[0].Hosts)
+			return nil
+		})
+
+	err := s.manager.Create(context.Background(), intents)
+	s.NoError(err)
+	s.ExpectNoEvent()
+}
+
+func (s *SidecarManagerTestSuite) TestCloudIntentsKeepEgressUnrestricted() {
+	intents := sidecarTestIntents(
+		v1alpha3.Intent{Type: v1alpha3.IntentTypeAWS, Name: "arn:aws:s3:::bucket", AWSActions: []string{"s3:GetObject"}},
+	)
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "")
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).DoAndReturn(
+		func(_ context.Context, sidecar *networkingv1beta1.Sidecar, _ ...any) error {
+			s.Equal(v1beta1networking.OutboundTrafficPolicy_ALLOW_ANY, sidecar.Spec.OutboundTrafficPolicy.Mode)
+			return nil
+		})
+
+	err := s.manager.Create(context.Background(), intents)
+	s.NoError(err)
+	s.ExpectEvent(ReasonIstioSidecarEgressUnrestricted)
+}
+
+func (s *SidecarManagerTestSuite) TestEnforcedNamespaceRestrictsEgressInShadowMode() {
+	s.manager.enforcementDefaultState = false
+	s.manager.enforcedNamespaces = goset.NewSet[string]("test-namespace")
+	intents := sidecarTestIntents(v1alpha3.Intent{Name: "svc:test-server"})
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{}, "")
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).Return(notFound)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).Return(nil)
+
+	err := s.manager.Create(context.Background(), intents)
+	s.NoError(err)
+	s.ExpectNoEvent()
+}
+
+func (s *SidecarManagerTestSuite) TestEnforcementDefaultOffDeletesSidecar() {
+	s.manager.enforcementDefaultState = false
+	intents := sidecarTestIntents(v1alpha3.Intent{Name: "test-server"})
+
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&v1alpha3.ProtectedServiceList{}), gomock.Any(), gomock.Any()).Return(nil)
+	s.Client.EXPECT().Delete(gomock.Any(), gomock.AssignableToTypeOf(&networkingv1beta1.Sidecar{})).Return(nil)
+
+	err := s.manager.Create(context.Background(), intents)
+	s.NoError(err)
+	s.ExpectEvent(consts.ReasonEnforcementDefaultOff)
+}
+
+func TestSidecarManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(SidecarManagerTestSuite))
+}
@@ -100,7 +100,7 @@ func (s *KafkaServerConfigReconcilerTestSuite) setupServerStore(serviceName stri
 }
 
 func getMockIntentsAdminFactory(mockIntentsAdmin *kafkaaclsmocks.MockKafkaIntentsAdmin) kafkaacls.IntentsAdminFactoryFunction {
-	return func(kafkaServer otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementDefaultState bool) (kafkaacls.KafkaIntentsAdmin, error) {
+	return func(_ context.Context, kafkaServer otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementDefaultState bool) (kafkaacls.KafkaIntentsAdmin, error) {
 		return mockIntentsAdmin, nil
 	}
 }
@@ -489,6 +489,62 @@ func (s *KafkaServerConfigReconcilerTestSuite) TestUpdateIntentsGeneratedForOper
 	s.ExpectEvent(ReasonSuccessfullyAppliedKafkaServerConfig)
 }
 
+func (s *KafkaServerConfigReconcilerTestSuite) TestCredentialsRotationRecordsEvent() {
+	kafkaServerConfig := s.generateKafkaServerConfig()
+	kafkaServerConfig.Spec.TLS.SecretRef = &otterizev1alpha3.SecretReference{Name: "kafka-tls"}
+	objectName := types.NamespacedName{Name: kafkaServiceName, Namespace: testNamespace}
+	secretName := types.NamespacedName{Name: "kafka-tls", Namespace: testNamespace}
+
+	for _, resourceVersion := range []string{"1", "1", "2"} {
+		s.Client.EXPECT().Get(gomock.Any(), objectName, &otterizev1alpha3.KafkaServerConfig{}).DoAndReturn(
+			func(ctx context.Context, name types.NamespacedName, actualKSC *otterizev1alpha3.KafkaServerConfig, _ ...client.GetOption) error {
+				kafkaServerConfig.DeepCopyInto(actualKSC)
+				return nil
+			})
+		s.Client.EXPECT().Get(gomock.Any(), secretName, &corev1.Secret{}).DoAndReturn(
+			func(ctx context.Context, name types.NamespacedName, secret *corev1.Secret, _ ...client.GetOption) error {
+				secret.ResourceVersion = resourceVersion
+				return nil
+			})
+		s.mockIntentsAdmin.EXPECT().ApplyServerTopicsConf(kafkaServerConfig.Spec.Topics).Return(nil)
+		s.mockIntentsAdmin.EXPECT().Close()
+		s.Client.EXPECT().List(gomock.Any(), &otterizev1alpha3.KafkaServerConfigList{}, client.InNamespace(testNamespace), &client.ListOptions{Namespace: testNamespace}).DoAndReturn(
+			func(ctx context.Context, list *otterizev1alpha3.KafkaServerConfigList, _ ...client.ListOption) error {
+				list.Items = append(list.Items, kafkaServerConfig)
+				return nil
+			})
+		s.mockCloudClient.EXPECT().ReportKafkaServerConfig(gomock.Any(), testNamespace, gomock.Any()).Return(nil)
+
+		_, err := s.reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: objectName})
+		s.Require().NoError(err)
+		if resourceVersion == "2" {
+			s.ExpectEvent(ReasonKafkaServerCredentialsRotated)
+		}
+		s.ExpectEvent(ReasonSuccessfullyAppliedKafkaServerConfig)
+	}
+}
+
+func (s *KafkaServerConfigReconcilerTestSuite) TestMissingCredentialsSecret() {
+	kafkaServerConfig := s.generateKafkaServerConfig()
+	kafkaServerConfig.Spec.Authentication = &otterizev1alpha3.KafkaAuthentication{
+		Mechanism: otterizev1alpha3.KafkaSASLMechanismSCRAMSHA512,
+		SecretRef: otterizev1alpha3.SecretReference{Name: "kafka-credentials"},
+	}
+	objectName := types.NamespacedName{Name: kafkaServiceName, Namespace: testNamespace}
+
+	s.Client.EXPECT().Get(gomock.Any(), objectName, &otterizev1alpha3.KafkaServerConfig{}).DoAndReturn(
+		func(ctx context.Context, name types.NamespacedName, actualKSC *otterizev1alpha3.KafkaServerConfig, _ ...client.GetOption) error {
+			kafkaServerConfig.DeepCopyInto(actualKSC)
+			return nil
+		})
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "kafka-credentials", Namespace: testNamespace}, &corev1.Secret{}).Return(
+		k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "kafka-credentials"))
+
+	_, err := s.reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: objectName})
+	s.Require().Error(err)
+	s.ExpectEvent(ReasonLoadingKafkaServerCredentialsFailed)
+}
+
 func TestKafkaACLReconcilerTestSuite(t *testing.T) {
 	suite.Run(t, new(KafkaServerConfigReconcilerTestSuite))
 }
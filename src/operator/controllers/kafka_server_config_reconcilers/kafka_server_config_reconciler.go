@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 	"time"
 )
 
@@ -29,6 +30,8 @@ const (
 	ReasonIntentsOperatorIdentityResolveFailed = "IntentsOperatorIdentityResolveFailed"
 	ReasonApplyingKafkaServerConfigFailed      = "ApplyingKafkaServerConfigFailed"
 	ReasonSuccessfullyAppliedKafkaServerConfig = "SuccessfullyAppliedKafkaServerConfig"
+	ReasonLoadingKafkaServerCredentialsFailed  = "LoadingKafkaServerCredentialsFailed"
+	ReasonKafkaServerCredentialsRotated        = "KafkaServerCredentialsRotated"
 )
 
 // KafkaServerConfigReconciler reconciles a KafkaServerConfig object
@@ -41,6 +44,9 @@ type KafkaServerConfigReconciler struct {
 	otterizeClient       operator_cloud_client.CloudClient
 	injectablerecorder.InjectableRecorder
 	serviceResolver serviceidresolver.ServiceResolver
+	// credentialVersions holds the resource versions of the credential Secrets each KafkaServerConfig was last applied
+	// with, to tell a credential rotation apart from other reconciles.
+	credentialVersions map[types.NamespacedName]string
 }
 
 func NewKafkaServerConfigReconciler(
@@ -60,6 +66,7 @@ func NewKafkaServerConfigReconciler(
 		operatorPodNamespace: operatorPodNameSpace,
 		otterizeClient:       cloudClient,
 		serviceResolver:      serviceResolver,
+		credentialVersions:   make(map[types.NamespacedName]string),
 	}
 }
 
@@ -68,7 +75,7 @@ func NewKafkaServerConfigReconciler(
 //+kubebuilder:rbac:groups=k8s.otterize.com,resources=kafkaserverconfigs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=k8s.otterize.com,resources=kafkaserverconfigs/finalizers,verbs=update
 
-func (r *KafkaServerConfigReconciler) removeKafkaServerFromStore(kafkaServerConfig *otterizev1alpha3.KafkaServerConfig) error {
+func (r *KafkaServerConfigReconciler) removeKafkaServerFromStore(ctx context.Context, kafkaServerConfig *otterizev1alpha3.KafkaServerConfig) error {
 	logger := logrus.WithFields(
 		logrus.Fields{
 			"name":      kafkaServerConfig.Name,
@@ -76,7 +83,8 @@ func (r *KafkaServerConfigReconciler) removeKafkaServerFromStore(kafkaServerConf
 		},
 	)
 
-	intentsAdmin, err := r.ServersStore.Get(kafkaServerConfig.Spec.Service.Name, kafkaServerConfig.Namespace)
+	serverName := kafkaServerConfig.GetServerName()
+	intentsAdmin, err := r.ServersStore.Get(ctx, serverName.Name, serverName.Namespace)
 	if err != nil && errors.Is(err, kafkaacls.ServerSpecNotFound) {
 		logger.Info("Kafka server not registered to servers store")
 		return nil
@@ -92,7 +100,8 @@ func (r *KafkaServerConfigReconciler) removeKafkaServerFromStore(kafkaServerConf
 	}
 
 	logger.Info("Removing Kafka server from store")
-	r.ServersStore.Remove(kafkaServerConfig.Spec.Service.Name, kafkaServerConfig.Namespace)
+	r.ServersStore.Remove(serverName.Name, serverName.Namespace)
+	delete(r.credentialVersions, client.ObjectKeyFromObject(kafkaServerConfig))
 	return nil
 }
 
@@ -116,7 +125,7 @@ func (r *KafkaServerConfigReconciler) removeIntentsFromOperatorToKafkaServer(ctx
 }
 
 func (r *KafkaServerConfigReconciler) handleResourceDeletion(ctx context.Context, kafkaServerConfig *otterizev1alpha3.KafkaServerConfig) (ctrl.Result, error) {
-	if err := r.removeKafkaServerFromStore(kafkaServerConfig); err != nil {
+	if err := r.removeKafkaServerFromStore(ctx, kafkaServerConfig); err != nil {
 		return ctrl.Result{}, errors.Wrap(err)
 	}
 
@@ -154,7 +163,7 @@ func (r *KafkaServerConfigReconciler) createIntentsFromOperatorToKafkaServer(ctx
 			},
 			Calls: []otterizev1alpha3.Intent{{
 				Type: otterizev1alpha3.IntentTypeKafka,
-				Name: fmt.Sprintf("%s.%s", config.Spec.Service.Name, config.Namespace),
+				Name: config.GetIntentTargetName(),
 				Topics: []otterizev1alpha3.KafkaTopic{{
 					Name: "*",
 					Operations: []otterizev1alpha3.KafkaOperation{
@@ -227,9 +236,14 @@ func (r *KafkaServerConfigReconciler) reconcileObject(ctx context.Context, kafka
 		return r.handleResourceDeletion(ctx, kafkaServerConfig)
 	}
 
+	if err := r.checkCredentialsRotation(ctx, kafkaServerConfig); err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
 	r.ServersStore.Add(kafkaServerConfig)
 
-	kafkaIntentsAdmin, err := r.ServersStore.Get(kafkaServerConfig.Spec.Service.Name, kafkaServerConfig.Namespace)
+	serverName := kafkaServerConfig.GetServerName()
+	kafkaIntentsAdmin, err := r.ServersStore.Get(ctx, serverName.Name, serverName.Namespace)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err)
 	}
@@ -244,6 +258,33 @@ func (r *KafkaServerConfigReconciler) reconcileObject(ctx context.Context, kafka
 	return ctrl.Result{}, nil
 }
 
+// checkCredentialsRotation records an event when the Secrets holding the credentials of the Kafka server changed since
+// the last reconcile. Kafka admin clients are built from the store with the current contents of the Secrets, so the
+// connection made by this reconcile already uses the rotated credentials.
+func (r *KafkaServerConfigReconciler) checkCredentialsRotation(ctx context.Context, kafkaServerConfig *otterizev1alpha3.KafkaServerConfig) error {
+	secretNames := kafkaacls.ReferencedSecrets(*kafkaServerConfig)
+	versions := make([]string, 0, len(secretNames))
+	for _, secretName := range secretNames {
+		secret := &v1.Secret{}
+		err := r.Get(ctx, secretName, secret)
+		if err != nil {
+			r.RecordWarningEventf(kafkaServerConfig, ReasonLoadingKafkaServerCredentialsFailed, "failed to read credentials secret %s: %s", secretName.String(), err.Error())
+			return errors.Wrap(err)
+		}
+		versions = append(versions, fmt.Sprintf("%s@%s", secretName.String(), secret.ResourceVersion))
+	}
+
+	key := client.ObjectKeyFromObject(kafkaServerConfig)
+	currentVersion := strings.Join(versions, ",")
+	previousVersion, ok := r.credentialVersions[key]
+	r.credentialVersions[key] = currentVersion
+	if ok && previousVersion != currentVersion {
+		logrus.WithField("name", key.String()).Info("Kafka server credentials rotated, reconnecting")
+		r.RecordNormalEventf(kafkaServerConfig, ReasonKafkaServerCredentialsRotated, "credentials changed, reconnecting to Kafka server %s", kafkaServerConfig.Spec.Addr)
+	}
+	return nil
+}
+
 func (r *KafkaServerConfigReconciler) uploadKafkaServerConfigs(ctx context.Context, namespace string) error {
 	if r.otterizeClient == nil {
 		return nil
@@ -290,8 +331,13 @@ func kafkaServerConfigCRDToCloudModel(kafkaServerConfig otterizev1alpha3.KafkaSe
 		})
 	}
 
+	name := kafkaServerConfig.Spec.Service.Name
+	if kafkaServerConfig.Spec.External != nil {
+		name = kafkaServerConfig.Spec.External.Alias
+	}
+
 	input := graphqlclient.KafkaServerConfigInput{
-		Name:      kafkaServerConfig.Spec.Service.Name,
+		Name:      name,
 		Namespace: kafkaServerConfig.Namespace,
 		Address:   kafkaServerConfig.Spec.Addr,
 		Topics:    topics,
@@ -0,0 +1,104 @@
+package kafkaacls
+
+import (
+	"context"
+	"crypto/tls"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"os"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	TLSCertKey   = "tls.crt"
+	TLSKeyKey    = "tls.key"
+	TLSRootCAKey = "ca.crt"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// ReferencedSecrets returns the Secrets holding the credentials of the Kafka server. Secrets are always looked up in the
+// namespace of the KafkaServerConfig, so that creating a KafkaServerConfig does not grant access to the Secrets of other
+// namespaces.
+func ReferencedSecrets(kafkaServer otterizev1alpha3.KafkaServerConfig) []types.NamespacedName {
+	secretRefs := make([]otterizev1alpha3.SecretReference, 0)
+	if kafkaServer.Spec.TLS.SecretRef != nil {
+		secretRefs = append(secretRefs, *kafkaServer.Spec.TLS.SecretRef)
+	}
+	if kafkaServer.Spec.Authentication != nil {
+		secretRefs = append(secretRefs, kafkaServer.Spec.Authentication.SecretRef)
+	}
+
+	secretNames := make([]types.NamespacedName, 0, len(secretRefs))
+	for _, secretRef := range secretRefs {
+		secretNames = append(secretNames, types.NamespacedName{Name: secretRef.Name, Namespace: kafkaServer.Namespace})
+	}
+	return secretNames
+}
+
+func getSecretData(ctx context.Context, k8sClient client.Client, secretRef otterizev1alpha3.SecretReference, namespace string) (map[string][]byte, error) {
+	if secretRef.Namespace != "" && secretRef.Namespace != namespace {
+		return nil, errors.Errorf("secret %s must be in the namespace of the KafkaServerConfig, %s", secretRef.Name, namespace)
+	}
+	name := types.NamespacedName{Name: secretRef.Name, Namespace: namespace}
+	if k8sClient == nil {
+		return nil, errors.Errorf("cannot read secret %s without a Kubernetes client", name.String())
+	}
+
+	secret := &corev1.Secret{}
+	err := k8sClient.Get(ctx, name, secret)
+	if err != nil {
+		return nil, errors.Errorf("failed reading kafka server credentials from secret %s: %w", name.String(), err)
+	}
+
+	return secret.Data, nil
+}
+
+// tlsCredentials holds the PEM encoded client certificate, private key and root CA of a TLS source, which are all
+// optional, as SASL authentication does not require a client certificate, and the system's root CAs are used if no
+// root CA is configured.
+type tlsCredentials struct {
+	certPEM   []byte
+	keyPEM    []byte
+	rootCAPEM []byte
+}
+
+func (c tlsCredentials) tlsConfig() (*tls.Config, error) {
+	return newTLSConfig(c.certPEM, c.keyPEM, c.rootCAPEM)
+}
+
+// loadTLSCredentials reads the TLS credentials from the Secret of the TLS source, in the format used by cert-manager and
+// by Secrets of type kubernetes.io/tls, or from the files of the TLS source.
+func loadTLSCredentials(ctx context.Context, k8sClient client.Client, tlsSource otterizev1alpha3.TLSSource, namespace string) (tlsCredentials, error) {
+	if tlsSource.SecretRef != nil {
+		data, err := getSecretData(ctx, k8sClient, *tlsSource.SecretRef, namespace)
+		if err != nil {
+			return tlsCredentials{}, errors.Wrap(err)
+		}
+		return tlsCredentials{certPEM: data[TLSCertKey], keyPEM: data[TLSKeyKey], rootCAPEM: data[TLSRootCAKey]}, nil
+	}
+
+	var creds tlsCredentials
+	var err error
+	if tlsSource.CertFile != "" || tlsSource.KeyFile != "" {
+		creds.certPEM, err = os.ReadFile(tlsSource.CertFile)
+		if err != nil {
+			return tlsCredentials{}, errors.Errorf("failed loading x509 key pair: %w", err)
+		}
+		creds.keyPEM, err = os.ReadFile(tlsSource.KeyFile)
+		if err != nil {
+			return tlsCredentials{}, errors.Errorf("failed loading x509 key pair: %w", err)
+		}
+	}
+
+	if tlsSource.RootCAFile != "" {
+		creds.rootCAPEM, err = os.ReadFile(tlsSource.RootCAFile)
+		if err != nil {
+			return tlsCredentials{}, errors.Errorf("failed loading root CA PEM file: %w ", err)
+		}
+	}
+
+	return creds, nil
+}
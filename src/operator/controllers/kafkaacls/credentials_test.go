@@ -0,0 +1,106 @@
+package kafkaacls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"math/big"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+	"time"
+)
+
+type CredentialsSuite struct {
+	testbase.MocksSuiteBase
+}
+
+func generateCertificatePEM(s *CredentialsSuite) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "intents-operator.otterize-system", Organization: []string{"otterize"}},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.Require().NoError(err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	s.Require().NoError(err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (s *CredentialsSuite) TestReferencedSecrets() {
+	kafkaServer := otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: kafkaServerConfigResourceName, Namespace: testNamespace},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			TLS: otterizev1alpha3.TLSSource{SecretRef: &otterizev1alpha3.SecretReference{Name: "kafka-tls"}},
+			Authentication: &otterizev1alpha3.KafkaAuthentication{
+				Mechanism: otterizev1alpha3.KafkaSASLMechanismPlain,
+				SecretRef: otterizev1alpha3.SecretReference{Name: "kafka-credentials"},
+			},
+		},
+	}
+
+	s.Equal([]types.NamespacedName{
+		{Name: "kafka-tls", Namespace: testNamespace},
+		{Name: "kafka-credentials", Namespace: testNamespace},
+	}, ReferencedSecrets(kafkaServer))
+}
+
+func (s *CredentialsSuite) TestTLSConfigFromSecret() {
+	certPEM, keyPEM := generateCertificatePEM(s)
+	secretRef := otterizev1alpha3.SecretReference{Name: "kafka-tls"}
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "kafka-tls", Namespace: testNamespace}, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, secret *corev1.Secret, _ ...client.GetOption) error {
+			secret.Data = map[string][]byte{TLSCertKey: certPEM, TLSKeyKey: keyPEM, TLSRootCAKey: certPEM}
+			return nil
+		})
+
+	tlsCreds, err := loadTLSCredentials(context.Background(), s.Client, otterizev1alpha3.TLSSource{SecretRef: &secretRef}, testNamespace)
+	s.Require().NoError(err)
+	tlsConfig, err := tlsCreds.tlsConfig()
+	s.Require().NoError(err)
+	s.Require().Len(tlsConfig.Certificates, 1)
+	s.NotNil(tlsConfig.RootCAs)
+
+	userNameMapping, err := getUserPrincipalMapping(tlsConfig.Certificates[0])
+	s.Require().NoError(err)
+	s.Equal("CN=$ServiceName.$Namespace,O=otterize", userNameMapping)
+}
+
+func (s *CredentialsSuite) TestTLSConfigFromSecretWithInvalidKeyPair() {
+	certPEM, _ := generateCertificatePEM(s)
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "kafka-tls", Namespace: testNamespace}, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, secret *corev1.Secret, _ ...client.GetOption) error {
+			secret.Data = map[string][]byte{TLSCertKey: certPEM}
+			return nil
+		})
+
+	tlsCreds, err := loadTLSCredentials(context.Background(), s.Client, otterizev1alpha3.TLSSource{SecretRef: &otterizev1alpha3.SecretReference{Name: "kafka-tls"}}, testNamespace)
+	s.Require().NoError(err)
+	_, err = tlsCreds.tlsConfig()
+	s.ErrorContains(err, "failed loading x509 key pair")
+}
+
+func (s *CredentialsSuite) TestSecretInOtherNamespaceNotRead() {
+	secretRef := otterizev1alpha3.SecretReference{Name: "kafka-tls", Namespace: "other-namespace"}
+
+	_, err := loadTLSCredentials(context.Background(), s.Client, otterizev1alpha3.TLSSource{SecretRef: &secretRef}, testNamespace)
+	s.ErrorContains(err, "must be in the namespace of the KafkaServerConfig")
+}
+
+func TestCredentialsSuite(t *testing.T) {
+	suite.Run(t, new(CredentialsSuite))
+}
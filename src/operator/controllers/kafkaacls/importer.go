@@ -0,0 +1,346 @@
+package kafkaacls
+
+import (
+	"context"
+	"fmt"
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// k8sNamePattern matches the names of Kubernetes services and namespaces, which cannot contain the dots that
+	// usually separate them in usernames.
+	k8sNamePattern = `[a-z0-9]([-a-z0-9]*[a-z0-9])?`
+)
+
+// UnsupportedACL is an ACL found on the Kafka server that cannot be expressed as ClientIntents.
+type UnsupportedACL struct {
+	Resource sarama.Resource
+	ACL      sarama.Acl
+	Reason   string
+}
+
+func (u UnsupportedACL) String() string {
+	return fmt.Sprintf("%s %s %s on %s '%s' (%s) from host %s: %s",
+		u.ACL.Principal, u.ACL.PermissionType.String(), u.ACL.Operation.String(), u.Resource.ResourceType.String(), u.Resource.ResourceName, u.Resource.ResourcePatternType.String(), u.ACL.Host, u.Reason)
+}
+
+// ImportedClientIntents are the ClientIntents converted from the ACLs of a client principal, along with those of its
+// ACLs that could not be converted.
+type ImportedClientIntents struct {
+	Intents     otterizev1alpha3.ClientIntents
+	Unsupported []UnsupportedACL
+}
+
+// ACLImportResult is the outcome of importing the ACLs of a Kafka server. ACLs of principals that do not match the
+// username mapping of the server cannot be attributed to a client, and are listed in UnmappedACLs.
+type ACLImportResult struct {
+	Clients      []ImportedClientIntents
+	UnmappedACLs []UnsupportedACL
+}
+
+// importedKafkaIntent collects the ACLs of a client before they are converted to an intent.
+type importedKafkaIntent struct {
+	topics           map[string][]otterizev1alpha3.KafkaOperation
+	groups           map[sarama.Resource][]sarama.Acl
+	transactionalIDs map[sarama.Resource][]otterizev1alpha3.KafkaOperation
+	cluster          []otterizev1alpha3.KafkaOperation
+	unsupported      []UnsupportedACL
+}
+
+func newImportedKafkaIntent() *importedKafkaIntent {
+	return &importedKafkaIntent{
+		topics:           map[string][]otterizev1alpha3.KafkaOperation{},
+		groups:           map[sarama.Resource][]sarama.Acl{},
+		transactionalIDs: map[sarama.Resource][]otterizev1alpha3.KafkaOperation{},
+	}
+}
+
+// userNameMappingToPrincipalRE returns a regexp that matches the principals of clients, capturing their service name
+// and namespace.
+func userNameMappingToPrincipalRE(userNameMapping string) (*regexp.Regexp, error) {
+	if !serviceNameRE.MatchString(userNameMapping) || !namespaceRE.MatchString(userNameMapping) {
+		return nil, errors.Errorf("username mapping %s must contain both $ServiceName and $Namespace to import ACLs", userNameMapping)
+	}
+	pattern := regexp.QuoteMeta(userNameMapping)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("$ServiceName"), fmt.Sprintf("(?P<service>%s)", k8sNamePattern), 1)
+	pattern = strings.Replace(pattern, regexp.QuoteMeta("$Namespace"), fmt.Sprintf("(?P<namespace>%s)", k8sNamePattern), 1)
+	return regexp.Compile("^User:" + pattern + "$")
+}
+
+func principalToClient(principalRE *regexp.Regexp, principal string) (types.NamespacedName, bool) {
+	match := principalRE.FindStringSubmatch(principal)
+	if match == nil {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{
+		Name:      match[principalRE.SubexpIndex("service")],
+		Namespace: match[principalRE.SubexpIndex("namespace")],
+	}, true
+}
+
+func saramaPatternTypeToIntentsPatternType(patternType sarama.AclResourcePatternType) (otterizev1alpha3.ResourcePatternType, bool) {
+	switch patternType {
+	case sarama.AclPatternLiteral:
+		return "", true
+	case sarama.AclPatternPrefixed:
+		return otterizev1alpha3.ResourcePatternTypePrefix, true
+	default:
+		return "", false
+	}
+}
+
+// ImportClientIntents reads the ACLs on the Kafka server, and converts the allow ACLs of each client principal into
+// ClientIntents towards the server. Principals are mapped back to clients using the username mapping of the server.
+func (a *KafkaIntentsAdminImpl) ImportClientIntents() (ACLImportResult, error) {
+	principalRE, err := userNameMappingToPrincipalRE(a.userNameMapping)
+	if err != nil {
+		return ACLImportResult{}, errors.Wrap(err)
+	}
+
+	resourceACLsList, err := a.kafkaAdminClient.ListAcls(sarama.AclFilter{
+		ResourceType:              sarama.AclResourceAny,
+		ResourcePatternTypeFilter: sarama.AclPatternAny,
+		PermissionType:            sarama.AclPermissionAny,
+		Operation:                 sarama.AclOperationAny,
+	})
+	if err != nil {
+		return ACLImportResult{}, errors.Errorf("failed listing ACLs on server: %w", err)
+	}
+
+	result := ACLImportResult{}
+	intentsByClient := map[types.NamespacedName]*importedKafkaIntent{}
+	for _, resourceACLs := range resourceACLsList {
+		for _, acl := range resourceACLs.Acls {
+			unsupportedACL := UnsupportedACL{Resource: resourceACLs.Resource, ACL: *acl}
+			if acl.Principal == AnonymousUserPrincipalName || acl.Principal == AnyUserPrincipalName {
+				unsupportedACL.Reason = "ACLs of all users are configured by the topics of the KafkaServerConfig"
+				result.UnmappedACLs = append(result.UnmappedACLs, unsupportedACL)
+				continue
+			}
+			clientName, ok := principalToClient(principalRE, acl.Principal)
+			if !ok {
+				unsupportedACL.Reason = fmt.Sprintf("principal does not match the username mapping %s", a.userNameMapping)
+				result.UnmappedACLs = append(result.UnmappedACLs, unsupportedACL)
+				continue
+			}
+			if _, ok := intentsByClient[clientName]; !ok {
+				intentsByClient[clientName] = newImportedKafkaIntent()
+			}
+			intentsByClient[clientName].addACL(resourceACLs.Resource, *acl)
+		}
+	}
+
+	for clientName, importedIntent := range intentsByClient {
+		intent := importedIntent.toIntent()
+		intent.Name = a.kafkaServer.GetIntentTargetName()
+		imported := ImportedClientIntents{Unsupported: importedIntent.unsupported}
+		imported.Intents.Name = clientName.Name
+		imported.Intents.Namespace = clientName.Namespace
+		imported.Intents.Spec = &otterizev1alpha3.IntentsSpec{Service: otterizev1alpha3.Service{Name: clientName.Name}}
+		if intent.Topics != nil || intent.ConsumerGroups != nil || intent.TransactionalIDs != nil || intent.ClusterOperations != nil {
+			imported.Intents.Spec.Calls = []otterizev1alpha3.Intent{intent}
+		}
+		result.Clients = append(result.Clients, imported)
+	}
+	slices.SortFunc(result.Clients, func(a, b ImportedClientIntents) int {
+		return strings.Compare(a.Intents.Namespace+"/"+a.Intents.Name, b.Intents.Namespace+"/"+b.Intents.Name)
+	})
+
+	return result, nil
+}
+
+func (i *importedKafkaIntent) addACL(resource sarama.Resource, acl sarama.Acl) {
+	unsupportedACL := UnsupportedACL{Resource: resource, ACL: acl}
+	operation, ok := KafkaOperationToAclOperationBMap.GetInverse(acl.Operation)
+	_, patternOK := saramaPatternTypeToIntentsPatternType(resource.ResourcePatternType)
+	switch {
+	case acl.PermissionType != sarama.AclPermissionAllow:
+		unsupportedACL.Reason = "intents can only allow access"
+	case acl.Host != "*":
+		unsupportedACL.Reason = "intents cannot restrict the hosts clients connect from"
+	case !ok:
+		unsupportedACL.Reason = "the operation is not supported by intents"
+	case !patternOK:
+		unsupportedACL.Reason = "the resource pattern type is not supported by intents"
+	case resource.ResourceType == sarama.AclResourceTopic && resource.ResourcePatternType != sarama.AclPatternLiteral:
+		unsupportedACL.Reason = "intents only support literal topic names"
+	case resource.ResourceType == sarama.AclResourceTopic:
+		i.topics[resource.ResourceName] = append(i.topics[resource.ResourceName], operation)
+		return
+	case resource.ResourceType == sarama.AclResourceGroup && lo.Contains(consumerGroupOperations, acl.Operation):
+		i.groups[resource] = append(i.groups[resource], acl)
+		return
+	case resource.ResourceType == sarama.AclResourceGroup:
+		unsupportedACL.Reason = "consumer group intents only grant read and describe"
+	case resource.ResourceType == sarama.AclResourceTransactionalID:
+		i.transactionalIDs[resource] = append(i.transactionalIDs[resource], operation)
+		return
+	case resource.ResourceType == sarama.AclResourceCluster:
+		i.cluster = append(i.cluster, operation)
+		return
+	default:
+		unsupportedACL.Reason = "the resource type is not supported by intents"
+	}
+	i.unsupported = append(i.unsupported, unsupportedACL)
+}
+
+// toIntent converts the collected ACLs into a Kafka intent, sorted so that the same ACLs always result in the same
+// intent. Consumer groups are only imported if the client may read from them, as intents grant read and describe on
+// consumer groups together.
+func (i *importedKafkaIntent) toIntent() otterizev1alpha3.Intent {
+	intent := otterizev1alpha3.Intent{Type: otterizev1alpha3.IntentTypeKafka}
+
+	for _, topicName := range sortedKeys(i.topics) {
+		intent.Topics = append(intent.Topics, otterizev1alpha3.KafkaTopic{Name: topicName, Operations: sortedOperations(i.topics[topicName])})
+	}
+
+	for _, resource := range sortedResources(lo.Keys(i.groups)) {
+		acls := i.groups[resource]
+		if !lo.ContainsBy(acls, func(acl sarama.Acl) bool { return acl.Operation == sarama.AclOperationRead }) {
+			for _, acl := range acls {
+				i.unsupported = append(i.unsupported, UnsupportedACL{Resource: resource, ACL: acl, Reason: "consumer group intents grant read and describe together"})
+			}
+			continue
+		}
+		pattern, _ := saramaPatternTypeToIntentsPatternType(resource.ResourcePatternType)
+		intent.ConsumerGroups = append(intent.ConsumerGroups, otterizev1alpha3.KafkaConsumerGroup{Name: resource.ResourceName, Pattern: pattern})
+	}
+
+	for _, resource := range sortedResources(lo.Keys(i.transactionalIDs)) {
+		pattern, _ := saramaPatternTypeToIntentsPatternType(resource.ResourcePatternType)
+		intent.TransactionalIDs = append(intent.TransactionalIDs, otterizev1alpha3.KafkaTransactionalID{
+			Name:       resource.ResourceName,
+			Pattern:    pattern,
+			Operations: sortedOperations(i.transactionalIDs[resource]),
+		})
+	}
+
+	if len(i.cluster) > 0 {
+		intent.ClusterOperations = sortedOperations(i.cluster)
+	}
+
+	return intent
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+func sortedOperations(operations []otterizev1alpha3.KafkaOperation) []otterizev1alpha3.KafkaOperation {
+	operations = lo.Uniq(operations)
+	slices.Sort(operations)
+	return operations
+}
+
+func sortedResources(resources []sarama.Resource) []sarama.Resource {
+	slices.SortFunc(resources, func(a, b sarama.Resource) int {
+		if a.ResourceName != b.ResourceName {
+			return strings.Compare(a.ResourceName, b.ResourceName)
+		}
+		return int(a.ResourcePatternType) - int(b.ResourcePatternType)
+	})
+	return resources
+}
+
+// clientIntentsManifest is the YAML written for imported ClientIntents, without the server-populated fields of the
+// ClientIntents resource.
+type clientIntentsManifest struct {
+	APIVersion string                        `json:"apiVersion"`
+	Kind       string                        `json:"kind"`
+	Metadata   clientIntentsManifestMetadata `json:"metadata"`
+	Spec       *otterizev1alpha3.IntentsSpec `json:"spec"`
+}
+
+type clientIntentsManifestMetadata struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// FormatImportedClientIntents returns the YAML manifest of the imported ClientIntents, preceded by comments listing the
+// ACLs of the client that could not be imported.
+func FormatImportedClientIntents(imported ImportedClientIntents) ([]byte, error) {
+	manifest, err := yaml.Marshal(clientIntentsManifest{
+		APIVersion: otterizev1alpha3.GroupVersion.String(),
+		Kind:       "ClientIntents",
+		Metadata:   clientIntentsManifestMetadata{Name: imported.Intents.Name, Namespace: imported.Intents.Namespace},
+		Spec:       imported.Intents.Spec,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	var output strings.Builder
+	if len(imported.Unsupported) > 0 {
+		output.WriteString("# The following ACLs of this client could not be imported, and are removed once these intents are applied:\n")
+		for _, unsupportedACL := range imported.Unsupported {
+			output.WriteString(fmt.Sprintf("#   %s\n", unsupportedACL))
+		}
+	}
+	output.Write(manifest)
+	return []byte(output.String()), nil
+}
+
+// RunACLImport connects to the Kafka server configured by the KafkaServerConfig, and writes the ClientIntents imported
+// from its ACLs to the output directory, as a file per client. ACLs that cannot be imported are logged as warnings.
+func RunACLImport(ctx context.Context, k8sClient client.Client, kafkaServerConfigName types.NamespacedName, defaultTls otterizev1alpha3.TLSSource, outputDir string) error {
+	kafkaServerConfig := otterizev1alpha3.KafkaServerConfig{}
+	if err := k8sClient.Get(ctx, kafkaServerConfigName, &kafkaServerConfig); err != nil {
+		return errors.Wrap(err)
+	}
+	if kafkaServerConfig.Spec.Strimzi != nil {
+		return errors.Errorf("importing ACLs is not supported for Strimzi Kafka clusters, as their ACLs are managed by KafkaUser resources")
+	}
+
+	pool := NewClusterAdminPool(time.Minute, 1)
+	defer pool.closeAll()
+	intentsAdmin, err := NewKafkaIntentsAdmin(ctx, k8sClient, pool, kafkaServerConfig, defaultTls, false, false)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	defer intentsAdmin.Close()
+
+	result, err := intentsAdmin.(*KafkaIntentsAdminImpl).ImportClientIntents()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return errors.Wrap(err)
+	}
+	for _, imported := range result.Clients {
+		manifest, err := FormatImportedClientIntents(imported)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		path := filepath.Join(outputDir, fmt.Sprintf("%s.%s.yaml", imported.Intents.Name, imported.Intents.Namespace))
+		if err := os.WriteFile(path, manifest, 0644); err != nil {
+			return errors.Wrap(err)
+		}
+		logger := logrus.WithField("path", path)
+		for _, unsupportedACL := range imported.Unsupported {
+			logger.Warnf("ACL could not be imported: %s", unsupportedACL)
+		}
+		logger.Info("Wrote imported ClientIntents")
+	}
+	for _, unmappedACL := range result.UnmappedACLs {
+		logrus.Warnf("ACL could not be attributed to a client: %s", unmappedACL)
+	}
+
+	logrus.Infof("Imported ACLs of %d clients, %d ACLs could not be attributed to a client", len(result.Clients), len(result.UnmappedACLs))
+	return nil
+}
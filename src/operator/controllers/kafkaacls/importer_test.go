@@ -0,0 +1,145 @@
+package kafkaacls
+
+import (
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	kafkaaclsmocks "github.com/otterize/intents-operator/src/operator/controllers/kafkaacls/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+type ImporterSuite struct {
+	suite.Suite
+	mockClusterAdmin *kafkaaclsmocks.MockClusterAdmin
+	intentsAdmin     *KafkaIntentsAdminImpl
+}
+
+func (s *ImporterSuite) SetupTest() {
+	controller := gomock.NewController(s.T())
+	s.mockClusterAdmin = kafkaaclsmocks.NewMockClusterAdmin(controller)
+	kafkaServerConfig := otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: kafkaServerConfigResourceName, Namespace: testNamespace},
+		Spec:       otterizev1alpha3.KafkaServerConfigSpec{Service: otterizev1alpha3.Service{Name: serverName}, Addr: serverAddress},
+	}
+	s.intentsAdmin = NewKafkaIntentsAdminImpl(kafkaServerConfig, s.mockClusterAdmin, "CN=$ServiceName.$Namespace,O=Otterize", false, false).(*KafkaIntentsAdminImpl)
+}
+
+func allowACL(principal string, operation sarama.AclOperation) *sarama.Acl {
+	return &sarama.Acl{Principal: principal, Host: "*", Operation: operation, PermissionType: sarama.AclPermissionAllow}
+}
+
+func (s *ImporterSuite) TestImportClientIntents() {
+	clientPrincipal := "User:CN=client.client-namespace,O=Otterize"
+	literalResource := func(resourceType sarama.AclResourceType, name string) sarama.Resource {
+		return sarama.Resource{ResourceType: resourceType, ResourceName: name, ResourcePatternType: sarama.AclPatternLiteral}
+	}
+	s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{
+		{Resource: literalResource(sarama.AclResourceTopic, "payments"), Acls: []*sarama.Acl{allowACL(clientPrincipal, sarama.AclOperationWrite)}},
+		{Resource: literalResource(sarama.AclResourceTopic, "orders"), Acls: []*sarama.Acl{
+			allowACL(clientPrincipal, sarama.AclOperationRead),
+			allowACL(clientPrincipal, sarama.AclOperationDescribe),
+			{Principal: clientPrincipal, Host: "10.0.0.1", Operation: sarama.AclOperationWrite, PermissionType: sarama.AclPermissionAllow},
+			allowACL(AnonymousUserPrincipalName, sarama.AclOperationRead),
+		}},
+		{Resource: sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "logs-", ResourcePatternType: sarama.AclPatternPrefixed}, Acls: []*sarama.Acl{allowACL(clientPrincipal, sarama.AclOperationRead)}},
+		{Resource: sarama.Resource{ResourceType: sarama.AclResourceGroup, ResourceName: "client-", ResourcePatternType: sarama.AclPatternPrefixed}, Acls: []*sarama.Acl{
+			allowACL(clientPrincipal, sarama.AclOperationRead),
+			allowACL(clientPrincipal, sarama.AclOperationDescribe),
+		}},
+		{Resource: literalResource(sarama.AclResourceGroup, "describe-only"), Acls: []*sarama.Acl{allowACL(clientPrincipal, sarama.AclOperationDescribe)}},
+		{Resource: literalResource(sarama.AclResourceTransactionalID, "client-tx"), Acls: []*sarama.Acl{
+			allowACL(clientPrincipal, sarama.AclOperationWrite),
+			allowACL(clientPrincipal, sarama.AclOperationDescribe),
+		}},
+		{Resource: literalResource(sarama.AclResourceCluster, kafkaClusterResourceName), Acls: []*sarama.Acl{
+			allowACL(clientPrincipal, sarama.AclOperationIdempotentWrite),
+			allowACL("User:admin", sarama.AclOperationAll),
+		}},
+	}, nil)
+
+	result, err := s.intentsAdmin.ImportClientIntents()
+	s.Require().NoError(err)
+
+	s.Require().Len(result.Clients, 1)
+	imported := result.Clients[0]
+	s.Equal("client", imported.Intents.Name)
+	s.Equal("client-namespace", imported.Intents.Namespace)
+	s.Equal(&otterizev1alpha3.IntentsSpec{
+		Service: otterizev1alpha3.Service{Name: "client"},
+		Calls: []otterizev1alpha3.Intent{{
+			Name: serverName + "." + testNamespace,
+			Type: otterizev1alpha3.IntentTypeKafka,
+			Topics: []otterizev1alpha3.KafkaTopic{
+				{Name: "orders", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationConsume, otterizev1alpha3.KafkaOperationDescribe}},
+				{Name: "payments", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationProduce}},
+			},
+			ConsumerGroups:    []otterizev1alpha3.KafkaConsumerGroup{{Name: "client-", Pattern: otterizev1alpha3.ResourcePatternTypePrefix}},
+			TransactionalIDs:  []otterizev1alpha3.KafkaTransactionalID{{Name: "client-tx", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationDescribe, otterizev1alpha3.KafkaOperationProduce}}},
+			ClusterOperations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationIdempotentWrite},
+		}},
+	}, imported.Intents.Spec)
+
+	unsupportedResources := make([]string, 0)
+	for _, unsupportedACL := range imported.Unsupported {
+		unsupportedResources = append(unsupportedResources, unsupportedACL.Resource.ResourceName)
+	}
+	s.ElementsMatch([]string{"orders", "logs-", "describe-only"}, unsupportedResources)
+
+	s.Require().Len(result.UnmappedACLs, 2)
+	s.ElementsMatch([]string{AnonymousUserPrincipalName, "User:admin"}, []string{result.UnmappedACLs[0].ACL.Principal, result.UnmappedACLs[1].ACL.Principal})
+}
+
+func (s *ImporterSuite) TestImportClientIntentsRequiresMappingWithNamespace() {
+	s.intentsAdmin.userNameMapping = "$ServiceName"
+
+	_, err := s.intentsAdmin.ImportClientIntents()
+	s.Require().ErrorContains(err, "must contain both $ServiceName and $Namespace")
+}
+
+func (s *ImporterSuite) TestFormatImportedClientIntents() {
+	imported := ImportedClientIntents{
+		Intents: otterizev1alpha3.ClientIntents{
+			ObjectMeta: metav1.ObjectMeta{Name: "client", Namespace: "client-namespace"},
+			Spec: &otterizev1alpha3.IntentsSpec{
+				Service: otterizev1alpha3.Service{Name: "client"},
+				Calls: []otterizev1alpha3.Intent{{
+					Name:   "kafka.kafka",
+					Type:   otterizev1alpha3.IntentTypeKafka,
+					Topics: []otterizev1alpha3.KafkaTopic{{Name: "orders", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationConsume}}},
+				}},
+			},
+		},
+		Unsupported: []UnsupportedACL{{
+			Resource: sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "logs-", ResourcePatternType: sarama.AclPatternPrefixed},
+			ACL:      *allowACL("User:client.client-namespace", sarama.AclOperationRead),
+			Reason:   "intents only support literal topic names",
+		}},
+	}
+
+	manifest, err := FormatImportedClientIntents(imported)
+	s.Require().NoError(err)
+	s.Equal(`# The following ACLs of this client could not be imported, and are removed once these intents are applied:
+#   User:client.client-namespace Allow Read on Topic 'logs-' (Prefixed) from host *: intents only support literal topic names
+apiVersion: k8s.otterize.com/v1alpha3
+kind: ClientIntents
+metadata:
+  name: client
+  namespace: client-namespace
+spec:
+  calls:
+  - kafkaTopics:
+    - name: orders
+      operations:
+      - consume
+    name: kafka.kafka
+    type: kafka
+  service:
+    name: client
+`, string(manifest))
+}
+
+func TestImporterSuite(t *testing.T) {
+	suite.Run(t, new(ImporterSuite))
+}
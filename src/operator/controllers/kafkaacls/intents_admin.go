@@ -1,6 +1,7 @@
 package kafkaacls
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -11,13 +12,15 @@ import (
 	"github.com/samber/lo"
 	"github.com/sirupsen/logrus"
 	"github.com/vishalkuo/bimap"
+	"k8s.io/apimachinery/pkg/types"
 	"log"
 	"os"
 	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 )
 
-type IntentsAdminFactoryFunction func(serverConfig otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementEnabledForServer bool) (KafkaIntentsAdmin, error)
+type IntentsAdminFactoryFunction func(ctx context.Context, serverConfig otterizev1alpha3.KafkaServerConfig, _ otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementEnabledForServer bool) (KafkaIntentsAdmin, error)
 
 type TopicToACLList map[sarama.Resource][]sarama.Acl
 
@@ -25,6 +28,8 @@ const (
 	intentsOperatorClientID    = "intents-operator"
 	AnonymousUserPrincipalName = "User:ANONYMOUS"
 	AnyUserPrincipalName       = "User:*"
+	// kafkaClusterResourceName is the name of the only cluster resource in Kafka ACLs.
+	kafkaClusterResourceName = "kafka-cluster"
 )
 
 var (
@@ -37,6 +42,8 @@ type KafkaIntentsAdmin interface {
 	ApplyClientIntents(clientName string, clientNamespace string, intents []otterizev1alpha3.Intent) error
 	RemoveClientIntents(clientName string, clientNamespace string) error
 	RemoveServerIntents(topicsConf []otterizev1alpha3.TopicConfig) error
+	ApplyClientQuota(clientName string, clientNamespace string, quota *otterizev1alpha3.KafkaQuota) (*otterizev1alpha3.KafkaQuota, error)
+	ResyncACLs(topicsConf []otterizev1alpha3.TopicConfig, intentsByClient map[types.NamespacedName][]otterizev1alpha3.Intent, repair bool) (missing int, unexpected int, err error)
 	Close()
 }
 
@@ -64,29 +71,31 @@ var (
 	}
 	KafkaOperationToAclOperationBMap = bimap.NewBiMapFromMap(kafkaOperationToAclOperation)
 
+	consumerGroupOperations = []sarama.AclOperation{sarama.AclOperationRead, sarama.AclOperationDescribe}
+
 	kafkaPatternTypeToSaramaPatternType = map[otterizev1alpha3.ResourcePatternType]sarama.AclResourcePatternType{
 		otterizev1alpha3.ResourcePatternTypeLiteral: sarama.AclPatternLiteral,
 		otterizev1alpha3.ResourcePatternTypePrefix:  sarama.AclPatternPrefixed,
 	}
 )
 
-func getTLSConfig(tlsSource otterizev1alpha3.TLSSource) (*tls.Config, error) {
-	cert, err := tls.LoadX509KeyPair(tlsSource.CertFile, tlsSource.KeyFile)
-	if err != nil {
-		return nil, errors.Errorf("failed loading x509 key pair: %w", err)
+func newTLSConfig(certPEM []byte, keyPEM []byte, rootCAPEM []byte) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if len(certPEM) != 0 || len(keyPEM) != 0 {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, errors.Errorf("failed loading x509 key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	pool := x509.NewCertPool()
-	rootCAPEM, err := os.ReadFile(tlsSource.RootCAFile)
-	if err != nil {
-		return nil, errors.Errorf("failed loading root CA PEM file: %w ", err)
+	if len(rootCAPEM) != 0 {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(rootCAPEM)
+		tlsConfig.RootCAs = pool
 	}
-	pool.AppendCertsFromPEM(rootCAPEM)
 
-	return &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		RootCAs:      pool,
-	}, nil
+	return tlsConfig, nil
 }
 
 func getUserPrincipalMapping(tlsCert tls.Certificate) (string, error) {
@@ -122,9 +131,20 @@ func getUserPrincipalMapping(tlsCert tls.Certificate) (string, error) {
 
 }
 
-func NewKafkaIntentsAdmin(kafkaServer otterizev1alpha3.KafkaServerConfig, defaultTls otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementEnabledForServer bool) (KafkaIntentsAdmin, error) {
+// NewKafkaIntentsAdminFactory returns an IntentsAdminFactoryFunction that reads the SASL credentials of Kafka servers
+// from their Secrets using the given client, and shares connections to Kafka servers using the pool.
+func NewKafkaIntentsAdminFactory(k8sClient client.Client, pool *ClusterAdminPool) IntentsAdminFactoryFunction {
+	return func(ctx context.Context, kafkaServer otterizev1alpha3.KafkaServerConfig, defaultTls otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementEnabledForServer bool) (KafkaIntentsAdmin, error) {
+		return NewKafkaIntentsAdmin(ctx, k8sClient, pool, kafkaServer, defaultTls, enableKafkaACLCreation, enforcementEnabledForServer)
+	}
+}
+
+func NewKafkaIntentsAdmin(ctx context.Context, k8sClient client.Client, pool *ClusterAdminPool, kafkaServer otterizev1alpha3.KafkaServerConfig, defaultTls otterizev1alpha3.TLSSource, enableKafkaACLCreation bool, enforcementEnabledForServer bool) (KafkaIntentsAdmin, error) {
+	if kafkaServer.Spec.Strimzi != nil {
+		return NewStrimziKafkaIntentsAdmin(k8sClient, kafkaServer, enableKafkaACLCreation, enforcementEnabledForServer), nil
+	}
+
 	logger := logrus.WithField("addr", kafkaServer.Spec.Addr)
-	logger.Info("Connecting to kafka server")
 	addrs := []string{kafkaServer.Spec.Addr}
 
 	config := sarama.NewConfig()
@@ -133,29 +153,62 @@ func NewKafkaIntentsAdmin(kafkaServer otterizev1alpha3.KafkaServerConfig, defaul
 	var tlsSource otterizev1alpha3.TLSSource
 	if lo.IsEmpty(kafkaServer.Spec.TLS) {
 		tlsSource = defaultTls
-		logger.Info("Using TLS configuration from default")
+		logger.Debug("Using TLS configuration from default")
 	} else {
 		tlsSource = kafkaServer.Spec.TLS
-		logger.Info("Using TLS configuration from KafkaServerConfig")
+		logger.Debug("Using TLS configuration from KafkaServerConfig")
 	}
 
-	tlsConfig, err := getTLSConfig(tlsSource)
+	tlsCreds, err := loadTLSCredentials(ctx, k8sClient, tlsSource, kafkaServer.Namespace)
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
-
-	usernameMapping, err := getUserPrincipalMapping(tlsConfig.Certificates[0])
+	tlsConfig, err := tlsCreds.tlsConfig()
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
 
+	usernameMapping := kafkaServer.Spec.UserNameMapping
+	var saslCredentials map[string][]byte
+	if kafkaServer.Spec.Authentication != nil {
+		logger.WithField("mechanism", kafkaServer.Spec.Authentication.Mechanism).Debug("Using SASL authentication")
+		saslCredentials, err = getSASLCredentials(ctx, k8sClient, kafkaServer)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		err = configureSASL(ctx, config, *kafkaServer.Spec.Authentication, saslCredentials)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if usernameMapping == "" {
+			usernameMapping = defaultSASLUserNameMapping
+		}
+	} else {
+		if len(tlsConfig.Certificates) == 0 {
+			return nil, errors.Errorf("failed loading x509 key pair: no client certificate configured for kafka server %s", kafkaServer.Spec.Addr)
+		}
+		if usernameMapping == "" {
+			usernameMapping, err = getUserPrincipalMapping(tlsConfig.Certificates[0])
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+		}
+	}
+
 	config.Net.TLS.Config = tlsConfig
 	config.Net.TLS.Enable = true
 	config.ClientID = intentsOperatorClientID
 
 	sarama.Logger = log.New(os.Stdout, "[sarama] ", log.LstdFlags)
 
-	saramaAdminClient, err := sarama.NewClusterAdmin(addrs, config)
+	poolKey, err := clusterAdminPoolKey(kafkaServer, tlsCreds, saslCredentials)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	saramaAdminClient, err := pool.Acquire(poolKey, func() (sarama.ClusterAdmin, error) {
+		logger.Info("Connecting to kafka server")
+		return sarama.NewClusterAdmin(addrs, config)
+	})
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
@@ -167,6 +220,7 @@ func NewKafkaIntentsAdminImpl(kafkaServer otterizev1alpha3.KafkaServerConfig, sa
 	return &KafkaIntentsAdminImpl{kafkaServer: kafkaServer, kafkaAdminClient: saramaAdminClient, userNameMapping: usernameMapping, enableKafkaACLCreation: enableKafkaACLCreation, enforcementEnabledForServer: enforcementEnabledForServer}
 }
 
+// Close releases the connection to the Kafka server, which is closed by the pool once idle if it was pooled.
 func (a *KafkaIntentsAdminImpl) Close() {
 	if err := a.kafkaAdminClient.Close(); err != nil {
 		logrus.WithError(err).Error("Error closing kafka admin client")
@@ -174,10 +228,12 @@ func (a *KafkaIntentsAdminImpl) Close() {
 }
 
 func (a *KafkaIntentsAdminImpl) formatPrincipal(clientName string, clientNamespace string) string {
-	username := a.userNameMapping
-	username = serviceNameRE.ReplaceAllString(username, clientName)
-	username = namespaceRE.ReplaceAllString(username, clientNamespace)
-	return fmt.Sprintf("User:%s", username)
+	return fmt.Sprintf("User:%s", formatUserName(a.userNameMapping, clientName, clientNamespace))
+}
+
+func formatUserName(userNameMapping string, clientName string, clientNamespace string) string {
+	username := serviceNameRE.ReplaceAllString(userNameMapping, clientName)
+	return namespaceRE.ReplaceAllString(username, clientNamespace)
 }
 
 func (a *KafkaIntentsAdminImpl) queryAppliedIntentKafkaTopics(principal string) ([]otterizev1alpha3.KafkaTopic, error) {
@@ -211,7 +267,36 @@ func (a *KafkaIntentsAdminImpl) queryAppliedIntentKafkaTopics(principal string)
 	return resourceAppliedKafkaTopics, nil
 }
 
-func (a *KafkaIntentsAdminImpl) collectTopicsToACLList(principal string, topics []otterizev1alpha3.KafkaTopic) (TopicToACLList, error) {
+// queryAppliedIntentResourceACLs returns the ACLs of the principal on the resources other than topics, which intents
+// declare as consumer groups, transactional IDs and cluster operations.
+func (a *KafkaIntentsAdminImpl) queryAppliedIntentResourceACLs(principal string) (TopicToACLList, error) {
+	resourceToACLList := TopicToACLList{}
+	for _, resourceType := range []sarama.AclResourceType{sarama.AclResourceGroup, sarama.AclResourceTransactionalID, sarama.AclResourceCluster} {
+		principalAcls, err := a.kafkaAdminClient.ListAcls(sarama.AclFilter{
+			ResourceType:              resourceType,
+			Principal:                 &principal,
+			ResourcePatternTypeFilter: sarama.AclPatternAny,
+			PermissionType:            sarama.AclPermissionAllow,
+			Operation:                 sarama.AclOperationAny,
+		})
+		if err != nil {
+			return nil, errors.Errorf("failed listing ACLs on server: %w", err)
+		}
+
+		for _, resourceAcls := range principalAcls {
+			resourceToACLList[resourceAcls.Resource] = append(
+				resourceToACLList[resourceAcls.Resource],
+				lo.Map(resourceAcls.Acls, func(acl *sarama.Acl, _ int) sarama.Acl {
+					return lo.FromPtr(acl)
+				})...,
+			)
+		}
+	}
+
+	return resourceToACLList, nil
+}
+
+func (a *KafkaIntentsAdminImpl) collectTopicsToACLList(principal string, topics []otterizev1alpha3.KafkaTopic, consumerGroups []otterizev1alpha3.KafkaConsumerGroup) (TopicToACLList, error) {
 	topicToACLList := TopicToACLList{}
 
 	for _, topic := range topics {
@@ -220,43 +305,111 @@ func (a *KafkaIntentsAdminImpl) collectTopicsToACLList(principal string, topics
 			ResourceName:        topic.Name,
 			ResourcePatternType: sarama.AclPatternLiteral,
 		}
-		acls := make([]sarama.Acl, 0)
-		for _, operation := range topic.Operations {
-			operation, ok := KafkaOperationToAclOperationBMap.Get(otterizev1alpha3.KafkaOperation(operation))
-			if !ok {
-				return nil, errors.Errorf("unknown operation '%v'", operation)
-			}
+		acls, err := operationsToACLs(principal, topic.Operations)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		topicToACLList[resource] = acls
+	}
 
-			acl := sarama.Acl{
+	for _, consumerGroup := range consumerGroups {
+		resource := sarama.Resource{
+			ResourceType:        sarama.AclResourceGroup,
+			ResourceName:        consumerGroup.Name,
+			ResourcePatternType: saramaPatternType(consumerGroup.Pattern),
+		}
+		// read is required for joining the group and committing offsets, and describe for fetching them.
+		topicToACLList[resource] = lo.Map(consumerGroupOperations, func(operation sarama.AclOperation, _ int) sarama.Acl {
+			return sarama.Acl{
 				Principal:      principal,
 				Host:           "*",
 				Operation:      operation,
 				PermissionType: sarama.AclPermissionAllow,
 			}
-			acls = append(acls, acl)
-		}
-		topicToACLList[resource] = acls
+		})
 	}
 
 	return topicToACLList, nil
 }
 
-func (a *KafkaIntentsAdminImpl) deleteACLsByPrincipal(principal string) (int, error) {
-	aclFilter := sarama.AclFilter{
-		ResourceType:              sarama.AclResourceTopic,
-		ResourcePatternTypeFilter: sarama.AclPatternAny,
-		PermissionType:            sarama.AclPermissionAllow,
-		Operation:                 sarama.AclOperationAny,
-		Principal:                 lo.ToPtr(principal),
-		Host:                      lo.ToPtr("*"),
+// collectTransactionalIDsAndClusterToACLList adds the ACLs on transactional IDs and on the cluster to the ACL list.
+// Exactly-once producers need produce and describe on their transactional IDs, and IdempotentWrite on the cluster.
+func (a *KafkaIntentsAdminImpl) collectTransactionalIDsAndClusterToACLList(aclList TopicToACLList, principal string, transactionalIDs []otterizev1alpha3.KafkaTransactionalID, clusterOperations []otterizev1alpha3.KafkaOperation) error {
+	for _, transactionalID := range transactionalIDs {
+		resource := sarama.Resource{
+			ResourceType:        sarama.AclResourceTransactionalID,
+			ResourceName:        transactionalID.Name,
+			ResourcePatternType: saramaPatternType(transactionalID.Pattern),
+		}
+		acls, err := operationsToACLs(principal, transactionalID.Operations)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		aclList[resource] = acls
 	}
 
-	matchedAcls, err := a.kafkaAdminClient.DeleteACL(aclFilter, true)
-	if err != nil {
-		return 0, errors.Errorf("failed deleting ACLs on server: %w", err)
+	if len(clusterOperations) > 0 {
+		resource := sarama.Resource{
+			ResourceType:        sarama.AclResourceCluster,
+			ResourceName:        kafkaClusterResourceName,
+			ResourcePatternType: sarama.AclPatternLiteral,
+		}
+		acls, err := operationsToACLs(principal, lo.Uniq(clusterOperations))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		aclList[resource] = acls
 	}
 
-	return len(matchedAcls), nil
+	return nil
+}
+
+func operationsToACLs(principal string, operations []otterizev1alpha3.KafkaOperation) ([]sarama.Acl, error) {
+	acls := make([]sarama.Acl, 0)
+	for _, operation := range operations {
+		aclOperation, ok := KafkaOperationToAclOperationBMap.Get(operation)
+		if !ok {
+			return nil, errors.Errorf("unknown operation '%v'", operation)
+		}
+
+		acl := sarama.Acl{
+			Principal:      principal,
+			Host:           "*",
+			Operation:      aclOperation,
+			PermissionType: sarama.AclPermissionAllow,
+		}
+		acls = append(acls, acl)
+	}
+	return acls, nil
+}
+
+func saramaPatternType(pattern otterizev1alpha3.ResourcePatternType) sarama.AclResourcePatternType {
+	if pattern == "" {
+		return sarama.AclPatternLiteral
+	}
+	return kafkaPatternTypeToSaramaPatternType[pattern]
+}
+
+func (a *KafkaIntentsAdminImpl) deleteACLsByPrincipal(principal string) (int, error) {
+	countDeleted := 0
+	for _, resourceType := range []sarama.AclResourceType{sarama.AclResourceTopic, sarama.AclResourceGroup, sarama.AclResourceTransactionalID, sarama.AclResourceCluster} {
+		aclFilter := sarama.AclFilter{
+			ResourceType:              resourceType,
+			ResourcePatternTypeFilter: sarama.AclPatternAny,
+			PermissionType:            sarama.AclPermissionAllow,
+			Operation:                 sarama.AclOperationAny,
+			Principal:                 lo.ToPtr(principal),
+			Host:                      lo.ToPtr("*"),
+		}
+
+		matchedAcls, err := a.kafkaAdminClient.DeleteACL(aclFilter, true)
+		if err != nil {
+			return 0, errors.Errorf("failed deleting ACLs on server: %w", err)
+		}
+		countDeleted += len(matchedAcls)
+	}
+
+	return countDeleted, nil
 }
 
 func (a *KafkaIntentsAdminImpl) logACLs() error {
@@ -300,33 +453,81 @@ func (a *KafkaIntentsAdminImpl) logACLs() error {
 	return nil
 }
 
-func (a *KafkaIntentsAdminImpl) ApplyClientIntents(clientName string, clientNamespace string, intents []otterizev1alpha3.Intent) error {
-	principal := a.formatPrincipal(clientName, clientNamespace)
-	logger := logrus.WithFields(
-		logrus.Fields{
-			"principal":       principal,
-			"serverName":      a.kafkaServer.Spec.Service,
-			"serverNamespace": a.kafkaServer.Namespace,
-		})
-
+// getAppliedClientACLs returns the ACLs of the principal on the Kafka server.
+func (a *KafkaIntentsAdminImpl) getAppliedClientACLs(principal string) (TopicToACLList, error) {
 	appliedIntentKafkaTopics, err := a.queryAppliedIntentKafkaTopics(principal)
 	if err != nil {
-		return errors.Errorf("failed getting applied ACL rules %w", err)
+		return nil, errors.Errorf("failed getting applied ACL rules %w", err)
 	}
 
-	appliedIntentKafkaAcls, err := a.collectTopicsToACLList(principal, appliedIntentKafkaTopics)
+	appliedIntentKafkaAcls, err := a.collectTopicsToACLList(principal, appliedIntentKafkaTopics, nil)
 	if err != nil {
-		return errors.Errorf("failed collecting topics to ACL list %w", err)
+		return nil, errors.Errorf("failed collecting topics to ACL list %w", err)
 	}
 
+	appliedResourceAcls, err := a.queryAppliedIntentResourceACLs(principal)
+	if err != nil {
+		return nil, errors.Errorf("failed getting applied ACL rules %w", err)
+	}
+	for resource, acls := range appliedResourceAcls {
+		appliedIntentKafkaAcls[resource] = acls
+	}
+
+	return appliedIntentKafkaAcls, nil
+}
+
+// getExpectedClientACLs returns the ACLs the intents of the principal require on the Kafka server.
+func (a *KafkaIntentsAdminImpl) getExpectedClientACLs(principal string, intents []otterizev1alpha3.Intent) (TopicToACLList, error) {
 	expectedIntentKafkaTopics := lo.Flatten(
 		lo.Map(intents, func(intent otterizev1alpha3.Intent, _ int) []otterizev1alpha3.KafkaTopic {
 			return intent.Topics
 		}),
 	)
-	expectedIntentsKafkaTopicsAcls, err := a.collectTopicsToACLList(principal, expectedIntentKafkaTopics)
+	expectedConsumerGroups := lo.Flatten(
+		lo.Map(intents, func(intent otterizev1alpha3.Intent, _ int) []otterizev1alpha3.KafkaConsumerGroup {
+			return intent.ConsumerGroups
+		}),
+	)
+	expectedIntentsKafkaTopicsAcls, err := a.collectTopicsToACLList(principal, expectedIntentKafkaTopics, expectedConsumerGroups)
+	if err != nil {
+		return nil, errors.Errorf("failed collecting topics to ACL list %w", err)
+	}
+
+	expectedTransactionalIDs := lo.Flatten(
+		lo.Map(intents, func(intent otterizev1alpha3.Intent, _ int) []otterizev1alpha3.KafkaTransactionalID {
+			return intent.TransactionalIDs
+		}),
+	)
+	expectedClusterOperations := lo.Flatten(
+		lo.Map(intents, func(intent otterizev1alpha3.Intent, _ int) []otterizev1alpha3.KafkaOperation {
+			return intent.ClusterOperations
+		}),
+	)
+	err = a.collectTransactionalIDsAndClusterToACLList(expectedIntentsKafkaTopicsAcls, principal, expectedTransactionalIDs, expectedClusterOperations)
+	if err != nil {
+		return nil, errors.Errorf("failed collecting transactional IDs and cluster operations to ACL list %w", err)
+	}
+
+	return expectedIntentsKafkaTopicsAcls, nil
+}
+
+func (a *KafkaIntentsAdminImpl) ApplyClientIntents(clientName string, clientNamespace string, intents []otterizev1alpha3.Intent) error {
+	principal := a.formatPrincipal(clientName, clientNamespace)
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"principal":       principal,
+			"serverName":      a.kafkaServer.Spec.Service,
+			"serverNamespace": a.kafkaServer.Namespace,
+		})
+
+	appliedIntentKafkaAcls, err := a.getAppliedClientACLs(principal)
 	if err != nil {
-		return errors.Errorf("failed collecting topics to ACL list %w", err)
+		return errors.Wrap(err)
+	}
+
+	expectedIntentsKafkaTopicsAcls, err := a.getExpectedClientACLs(principal, intents)
+	if err != nil {
+		return errors.Wrap(err)
 	}
 
 	resourceAclsCreate, resourceAclsDelete := a.kafkaResourceAclsDiff(expectedIntentsKafkaTopicsAcls, appliedIntentKafkaAcls)
@@ -535,6 +736,112 @@ func (a *KafkaIntentsAdminImpl) kafkaResourceAclsDiff(expected map[sarama.Resour
 	return resourceAclsToCreate, resourceAclsToDelete
 }
 
+// ResyncACLs compares the ACLs and client quotas on the Kafka server with those required by the topic configuration and
+// by the intents of each client, returns the number of missing and unexpected ACLs and quota values, and repairs them if
+// repair is set. Only the ACLs and quotas of the clients' principals and the topic ACLs of the ANONYMOUS and *
+// principals are compared, so those of principals the operator does not manage are left untouched. Missing ACLs and
+// quotas are only counted if the operator would create them.
+func (a *KafkaIntentsAdminImpl) ResyncACLs(topicsConf []otterizev1alpha3.TopicConfig, intentsByClient map[types.NamespacedName][]otterizev1alpha3.Intent, repair bool) (missing int, unexpected int, err error) {
+	quotasMissing, quotasUnexpected, err := a.resyncClientQuotas(intentsByClient, repair)
+	if err != nil {
+		return quotasMissing, quotasUnexpected, errors.Wrap(err)
+	}
+
+	aclsMissing, aclsUnexpected, err := a.resyncACLs(topicsConf, intentsByClient, repair)
+	return quotasMissing + aclsMissing, quotasUnexpected + aclsUnexpected, errors.Wrap(err)
+}
+
+func (a *KafkaIntentsAdminImpl) resyncACLs(topicsConf []otterizev1alpha3.TopicConfig, intentsByClient map[types.NamespacedName][]otterizev1alpha3.Intent, repair bool) (missing int, unexpected int, err error) {
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"serverName":      a.kafkaServer.Spec.Service,
+			"serverNamespace": a.kafkaServer.Namespace,
+		})
+
+	expectedResourceAcls := a.getExpectedTopicsConfAcls(topicsConf)
+	clientPrincipals := make(map[string]bool, len(intentsByClient))
+	for clientName, intents := range intentsByClient {
+		principal := a.formatPrincipal(clientName.Name, clientName.Namespace)
+		clientPrincipals[principal] = true
+		expectedClientAcls, err := a.getExpectedClientACLs(principal, intents)
+		if err != nil {
+			return 0, 0, errors.Wrap(err)
+		}
+		for resource, acls := range expectedClientAcls {
+			expectedResourceAcls[resource] = append(expectedResourceAcls[resource], acls...)
+		}
+	}
+
+	appliedResourceAcls, err := a.queryManagedACLs(clientPrincipals)
+	if err != nil {
+		return 0, 0, errors.Wrap(err)
+	}
+
+	resourceAclsToCreate, resourceAclsToDelete := a.kafkaResourceAclsDiff(expectedResourceAcls, appliedResourceAcls)
+	unexpected = countACLs(resourceAclsToDelete)
+	if a.enforcementEnabledForServer && a.enableKafkaACLCreation {
+		missing = countACLs(resourceAclsToCreate)
+	}
+
+	if missing == 0 && unexpected == 0 {
+		logger.Debug("No Kafka ACL drift found")
+		return 0, 0, nil
+	}
+
+	if !repair {
+		logger.Warnf("Found %d missing and %d unexpected ACLs, skipping repair because Kafka ACL drift repair is disabled", missing, unexpected)
+		return missing, unexpected, nil
+	}
+
+	logger.Infof("Repairing %d missing and %d unexpected ACLs", missing, unexpected)
+	if missing > 0 {
+		if err := a.kafkaAdminClient.CreateACLs(resourceAclsToCreate); err != nil {
+			return missing, unexpected, errors.Errorf("failed creating ACLs: %w", err)
+		}
+	}
+	if unexpected > 0 {
+		if err := a.deleteResourceAcls(resourceAclsToDelete); err != nil {
+			return missing, unexpected, errors.Errorf("failed deleting ACLs: %w", err)
+		}
+	}
+
+	return missing, unexpected, nil
+}
+
+// queryManagedACLs lists the ACLs on the Kafka server and keeps the allow ACLs of the client principals, and the topic
+// ACLs of the ANONYMOUS and * principals, which the topic configuration manages.
+func (a *KafkaIntentsAdminImpl) queryManagedACLs(clientPrincipals map[string]bool) (TopicToACLList, error) {
+	resourceAclsList, err := a.kafkaAdminClient.ListAcls(sarama.AclFilter{
+		ResourceType:              sarama.AclResourceAny,
+		ResourcePatternTypeFilter: sarama.AclPatternAny,
+		PermissionType:            sarama.AclPermissionAny,
+		Operation:                 sarama.AclOperationAny,
+	})
+	if err != nil {
+		return nil, errors.Errorf("failed listing ACLs on server: %w", err)
+	}
+
+	resourceToAcls := TopicToACLList{}
+	for _, resourceAcls := range resourceAclsList {
+		for _, acl := range resourceAcls.Acls {
+			isTopicsConfACL := resourceAcls.ResourceType == sarama.AclResourceTopic &&
+				(acl.Principal == AnonymousUserPrincipalName || acl.Principal == AnyUserPrincipalName)
+			isClientACL := clientPrincipals[acl.Principal] && acl.PermissionType == sarama.AclPermissionAllow
+			if isTopicsConfACL || isClientACL {
+				resourceToAcls[resourceAcls.Resource] = append(resourceToAcls[resourceAcls.Resource], lo.FromPtr(acl))
+			}
+		}
+	}
+
+	return resourceToAcls, nil
+}
+
+func countACLs(resourceAclsList []*sarama.ResourceAcls) int {
+	return lo.SumBy(resourceAclsList, func(resourceAcls *sarama.ResourceAcls) int {
+		return len(resourceAcls.Acls)
+	})
+}
+
 func (a *KafkaIntentsAdminImpl) deleteResourceAcls(resourceAclsToDelete []*sarama.ResourceAcls) error {
 	for _, resourceAcls := range resourceAclsToDelete {
 		for _, acl := range resourceAcls.Acls {
@@ -596,9 +903,18 @@ func (a *KafkaIntentsAdminImpl) ApplyServerTopicsConf(topicsConf []otterizev1alp
 		logger.Info("No existing ACLs to delete for topic configuration")
 	}
 
-	logger.Infof("ensuring consumer group permissions")
-	if err := a.ensureConsumerGroupWildcardACLs(); err != nil {
-		logger.WithError(err).Error("failed ensuring Consumer group permissions")
+	if a.kafkaServer.Spec.AllowAllConsumerGroups {
+		logger.Infof("ensuring consumer group permissions")
+		if err := a.ensureConsumerGroupWildcardACLs(); err != nil {
+			logger.WithError(err).Error("failed ensuring Consumer group permissions")
+		}
+	} else {
+		deletedRulesCount, err := a.deleteConsumerGroupWildcardACLs()
+		if err != nil {
+			logger.WithError(err).Error("failed deleting Consumer group permissions")
+		} else if deletedRulesCount > 0 {
+			logger.Infof("%d group acl rules were deleted, consumer groups are now granted according to intents", deletedRulesCount)
+		}
 	}
 
 	if err := a.logACLs(); err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"testing"
 	"time"
 )
@@ -43,7 +44,8 @@ func (s *IntentAdminSuite) TestApplyServerConfig() {
 			Service: otterizev1alpha3.Service{
 				Name: serverName,
 			},
-			Addr: serverAddress,
+			Addr:                   serverAddress,
+			AllowAllConsumerGroups: true,
 			Topics: []otterizev1alpha3.TopicConfig{
 				{
 					Topic:                  topicName,
@@ -108,7 +110,8 @@ func (s *IntentAdminSuite) TestApplyServerConfigPermissionExists() {
 			Service: otterizev1alpha3.Service{
 				Name: serverName,
 			},
-			Addr: serverAddress,
+			Addr:                   serverAddress,
+			AllowAllConsumerGroups: true,
 			Topics: []otterizev1alpha3.TopicConfig{
 				{
 					Topic:                  topicName,
@@ -302,6 +305,245 @@ func (s *IntentAdminSuite) TestDeleteServerConfig() {
 	s.Require().NoError(err)
 }
 
+func (s *IntentAdminSuite) TestApplyServerConfigRemovesWildcardConsumerGroupACLs() {
+	kafkaServerConfig := otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kafkaServerConfigResourceName,
+			Namespace: testNamespace,
+		},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			Service: otterizev1alpha3.Service{
+				Name: serverName,
+			},
+			Addr: serverAddress,
+		},
+	}
+
+	s.intentsAdmin = NewKafkaIntentsAdminImpl(kafkaServerConfig, s.mockClusterAdmin, "user-name-mapping", true, true)
+
+	defaultTopicConf := getAclAuthenticatedOnly("*", anonymousUsersPrincipal, allUsersPrincipal)
+	defaultTopicConf.Acls = defaultTopicConf.Acls[:1]
+	aclDeleteFilterOperatorGroup := sarama.AclFilter{
+		ResourceType:              sarama.AclResourceGroup,
+		ResourceName:              lo.ToPtr("*"),
+		ResourcePatternTypeFilter: sarama.AclPatternLiteral,
+		PermissionType:            sarama.AclPermissionAllow,
+		Principal:                 lo.ToPtr(allUsersPrincipal),
+		Operation:                 sarama.AclOperationAny,
+	}
+	groupPermission := getAclOperatorGroupPermission()
+	groupAcl := lo.Map(groupPermission.Acls, func(acl *sarama.Acl, _ int) sarama.MatchingAcl {
+		return sarama.MatchingAcl{Resource: groupPermission.Resource, Acl: *acl}
+	})
+
+	gomock.InOrder(
+		s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{defaultTopicConf}, nil),
+		s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil),
+		s.mockClusterAdmin.EXPECT().DeleteACL(aclDeleteFilterOperatorGroup, false).Return(groupAcl, nil),
+		s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{defaultTopicConf}, nil),
+	)
+	err := s.intentsAdmin.ApplyServerTopicsConf(kafkaServerConfig.Spec.Topics)
+	s.Require().NoError(err)
+}
+
+func (s *IntentAdminSuite) TestApplyClientIntentsWithConsumerGroups() {
+	kafkaServerConfig := otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kafkaServerConfigResourceName,
+			Namespace: testNamespace,
+		},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			Service: otterizev1alpha3.Service{
+				Name: serverName,
+			},
+			Addr: serverAddress,
+		},
+	}
+
+	s.intentsAdmin = NewKafkaIntentsAdminImpl(kafkaServerConfig, s.mockClusterAdmin, "$ServiceName.$Namespace", true, true)
+	principal := "User:client.client-namespace"
+	staleGroupACL := sarama.ResourceAcls{
+		Resource: sarama.Resource{ResourceType: sarama.AclResourceGroup, ResourceName: "old-group", ResourcePatternType: sarama.AclPatternLiteral},
+		Acls:     []*sarama.Acl{{Principal: principal, Host: "*", Operation: sarama.AclOperationRead, PermissionType: sarama.AclPermissionAllow}},
+	}
+	expectedGroupACL := sarama.ResourceAcls{
+		Resource: sarama.Resource{ResourceType: sarama.AclResourceGroup, ResourceName: "billing-", ResourcePatternType: sarama.AclPatternPrefixed},
+		Acls: []*sarama.Acl{
+			{Principal: principal, Host: "*", Operation: sarama.AclOperationRead, PermissionType: sarama.AclPermissionAllow},
+			{Principal: principal, Host: "*", Operation: sarama.AclOperationDescribe, PermissionType: sarama.AclPermissionAllow},
+		},
+	}
+	consumerGroupListFilter := sarama.AclFilter{
+		ResourceType:              sarama.AclResourceGroup,
+		Principal:                 lo.ToPtr(principal),
+		ResourcePatternTypeFilter: sarama.AclPatternAny,
+		PermissionType:            sarama.AclPermissionAllow,
+		Operation:                 sarama.AclOperationAny,
+	}
+	staleGroupDeleteFilter := sarama.AclFilter{
+		ResourceType:              sarama.AclResourceGroup,
+		ResourceName:              lo.ToPtr("old-group"),
+		ResourcePatternTypeFilter: sarama.AclPatternLiteral,
+		PermissionType:            sarama.AclPermissionAllow,
+		Operation:                 sarama.AclOperationRead,
+		Principal:                 lo.ToPtr(principal),
+		Host:                      lo.ToPtr("*"),
+	}
+
+	gomock.InOrder(
+		s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil),
+		s.mockClusterAdmin.EXPECT().ListAcls(consumerGroupListFilter).Return([]sarama.ResourceAcls{staleGroupACL}, nil),
+		s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil).Times(2),
+		s.mockClusterAdmin.EXPECT().CreateACLs(MatchResourceAcls([]*sarama.ResourceAcls{&expectedGroupACL})).Return(nil),
+		s.mockClusterAdmin.EXPECT().DeleteACL(staleGroupDeleteFilter, false).Return(nil, nil),
+		s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{expectedGroupACL}, nil),
+	)
+	err := s.intentsAdmin.ApplyClientIntents("client", "client-namespace", []otterizev1alpha3.Intent{{
+		Name:           serverName,
+		Type:           otterizev1alpha3.IntentTypeKafka,
+		ConsumerGroups: []otterizev1alpha3.KafkaConsumerGroup{{Name: "billing-", Pattern: otterizev1alpha3.ResourcePatternTypePrefix}},
+	}})
+	s.Require().NoError(err)
+}
+
+func (s *IntentAdminSuite) TestTransactionalIDAndClusterACLs() {
+	intentsAdmin := NewKafkaIntentsAdminImpl(otterizev1alpha3.KafkaServerConfig{}, s.mockClusterAdmin, "$ServiceName.$Namespace", true, true).(*KafkaIntentsAdminImpl)
+	principal := "User:client.client-namespace"
+	aclList := TopicToACLList{}
+
+	err := intentsAdmin.collectTransactionalIDsAndClusterToACLList(aclList, principal,
+		[]otterizev1alpha3.KafkaTransactionalID{{
+			Name:       "payments-",
+			Pattern:    otterizev1alpha3.ResourcePatternTypePrefix,
+			Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationProduce, otterizev1alpha3.KafkaOperationDescribe},
+		}},
+		[]otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationIdempotentWrite, otterizev1alpha3.KafkaOperationIdempotentWrite},
+	)
+	s.Require().NoError(err)
+
+	s.Equal(TopicToACLList{
+		{ResourceType: sarama.AclResourceTransactionalID, ResourceName: "payments-", ResourcePatternType: sarama.AclPatternPrefixed}: {
+			{Principal: principal, Host: "*", Operation: sarama.AclOperationWrite, PermissionType: sarama.AclPermissionAllow},
+			{Principal: principal, Host: "*", Operation: sarama.AclOperationDescribe, PermissionType: sarama.AclPermissionAllow},
+		},
+		{ResourceType: sarama.AclResourceCluster, ResourceName: "kafka-cluster", ResourcePatternType: sarama.AclPatternLiteral}: {
+			{Principal: principal, Host: "*", Operation: sarama.AclOperationIdempotentWrite, PermissionType: sarama.AclPermissionAllow},
+		},
+	}, aclList)
+
+	err = intentsAdmin.collectTransactionalIDsAndClusterToACLList(aclList, principal, nil, []otterizev1alpha3.KafkaOperation{"unknown"})
+	s.Require().ErrorContains(err, "unknown operation")
+}
+
+func (s *IntentAdminSuite) TestRemoveClientIntentsDeletesAllResourceTypes() {
+	s.intentsAdmin = NewKafkaIntentsAdminImpl(otterizev1alpha3.KafkaServerConfig{}, s.mockClusterAdmin, "$ServiceName.$Namespace", true, true)
+	principal := "User:client.client-namespace"
+
+	for _, resourceType := range []sarama.AclResourceType{sarama.AclResourceTopic, sarama.AclResourceGroup, sarama.AclResourceTransactionalID, sarama.AclResourceCluster} {
+		s.mockClusterAdmin.EXPECT().DeleteACL(sarama.AclFilter{
+			ResourceType:              resourceType,
+			ResourcePatternTypeFilter: sarama.AclPatternAny,
+			PermissionType:            sarama.AclPermissionAllow,
+			Operation:                 sarama.AclOperationAny,
+			Principal:                 lo.ToPtr(principal),
+			Host:                      lo.ToPtr("*"),
+		}, true).Return([]sarama.MatchingAcl{}, nil)
+	}
+	s.mockClusterAdmin.EXPECT().ListAcls(gomock.Any()).Return([]sarama.ResourceAcls{}, nil)
+
+	err := s.intentsAdmin.RemoveClientIntents("client", "client-namespace")
+	s.Require().NoError(err)
+}
+
+func (s *IntentAdminSuite) expectListAllACLsWithDrift(principal string) {
+	defaultTopicConf := sarama.ResourceAcls{
+		Resource: sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "*", ResourcePatternType: sarama.AclPatternLiteral},
+		Acls:     []*sarama.Acl{{Principal: anonymousUsersPrincipal, Host: "*", Operation: sarama.AclOperationAll, PermissionType: sarama.AclPermissionDeny}},
+	}
+	manuallyAddedACL := sarama.ResourceAcls{
+		Resource: sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "payments", ResourcePatternType: sarama.AclPatternLiteral},
+		Acls: []*sarama.Acl{
+			{Principal: principal, Host: "*", Operation: sarama.AclOperationWrite, PermissionType: sarama.AclPermissionAllow},
+			// ACLs of principals not managed by the operator, and deny ACLs of clients, are not compared.
+			{Principal: "User:admin", Host: "*", Operation: sarama.AclOperationAll, PermissionType: sarama.AclPermissionAllow},
+			{Principal: principal, Host: "*", Operation: sarama.AclOperationDelete, PermissionType: sarama.AclPermissionDeny},
+		},
+	}
+
+	s.mockClusterAdmin.EXPECT().ListAcls(sarama.AclFilter{
+		ResourceType:              sarama.AclResourceAny,
+		ResourcePatternTypeFilter: sarama.AclPatternAny,
+		PermissionType:            sarama.AclPermissionAny,
+		Operation:                 sarama.AclOperationAny,
+	}).Return([]sarama.ResourceAcls{defaultTopicConf, manuallyAddedACL}, nil)
+}
+
+func (s *IntentAdminSuite) expectDescribeNoClientQuotas() {
+	s.mockClusterAdmin.EXPECT().DescribeClientQuotas(gomock.Any(), true).Return(nil, nil)
+}
+
+func (s *IntentAdminSuite) TestResyncACLsReportsDrift() {
+	principal := "User:client.client-namespace"
+	intentsByClient := map[types.NamespacedName][]otterizev1alpha3.Intent{
+		{Name: "client", Namespace: "client-namespace"}: {{
+			Name:   serverName,
+			Type:   otterizev1alpha3.IntentTypeKafka,
+			Topics: []otterizev1alpha3.KafkaTopic{{Name: "orders", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationConsume}}},
+		}},
+	}
+
+	s.intentsAdmin = NewKafkaIntentsAdminImpl(otterizev1alpha3.KafkaServerConfig{}, s.mockClusterAdmin, "$ServiceName.$Namespace", true, true)
+	s.expectDescribeNoClientQuotas()
+	s.expectListAllACLsWithDrift(principal)
+	missing, unexpected, err := s.intentsAdmin.ResyncACLs(nil, intentsByClient, false)
+	s.Require().NoError(err)
+	s.Equal(1, missing)
+	s.Equal(1, unexpected)
+
+	// missing ACLs are not counted when the operator would not create them
+	s.intentsAdmin = NewKafkaIntentsAdminImpl(otterizev1alpha3.KafkaServerConfig{}, s.mockClusterAdmin, "$ServiceName.$Namespace", false, true)
+	s.expectDescribeNoClientQuotas()
+	s.expectListAllACLsWithDrift(principal)
+	missing, unexpected, err = s.intentsAdmin.ResyncACLs(nil, intentsByClient, false)
+	s.Require().NoError(err)
+	s.Equal(0, missing)
+	s.Equal(1, unexpected)
+}
+
+func (s *IntentAdminSuite) TestResyncACLsRepairsDrift() {
+	principal := "User:client.client-namespace"
+	intentsByClient := map[types.NamespacedName][]otterizev1alpha3.Intent{
+		{Name: "client", Namespace: "client-namespace"}: {{
+			Name:   serverName,
+			Type:   otterizev1alpha3.IntentTypeKafka,
+			Topics: []otterizev1alpha3.KafkaTopic{{Name: "orders", Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationConsume}}},
+		}},
+	}
+	missingACL := sarama.ResourceAcls{
+		Resource: sarama.Resource{ResourceType: sarama.AclResourceTopic, ResourceName: "orders", ResourcePatternType: sarama.AclPatternLiteral},
+		Acls:     []*sarama.Acl{{Principal: principal, Host: "*", Operation: sarama.AclOperationRead, PermissionType: sarama.AclPermissionAllow}},
+	}
+
+	s.intentsAdmin = NewKafkaIntentsAdminImpl(otterizev1alpha3.KafkaServerConfig{}, s.mockClusterAdmin, "$ServiceName.$Namespace", true, true)
+	s.expectDescribeNoClientQuotas()
+	s.expectListAllACLsWithDrift(principal)
+	s.mockClusterAdmin.EXPECT().CreateACLs(MatchResourceAcls([]*sarama.ResourceAcls{&missingACL})).Return(nil)
+	s.mockClusterAdmin.EXPECT().DeleteACL(sarama.AclFilter{
+		ResourceType:              sarama.AclResourceTopic,
+		ResourceName:              lo.ToPtr("payments"),
+		ResourcePatternTypeFilter: sarama.AclPatternLiteral,
+		PermissionType:            sarama.AclPermissionAllow,
+		Operation:                 sarama.AclOperationWrite,
+		Principal:                 lo.ToPtr(principal),
+		Host:                      lo.ToPtr("*"),
+	}, false).Return(nil, nil)
+
+	missing, unexpected, err := s.intentsAdmin.ResyncACLs(nil, intentsByClient, true)
+	s.Require().NoError(err)
+	s.Equal(1, missing)
+	s.Equal(1, unexpected)
+}
+
 func getAclOperatorGroupPermission() sarama.ResourceAcls {
 	return sarama.ResourceAcls{
 		Resource: sarama.Resource{
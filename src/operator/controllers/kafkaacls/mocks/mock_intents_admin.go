@@ -9,6 +9,7 @@ import (
 
 	v1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
 	gomock "go.uber.org/mock/gomock"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // MockKafkaIntentsAdmin is a mock of KafkaIntentsAdmin interface.
@@ -48,6 +49,21 @@ func (mr *MockKafkaIntentsAdminMockRecorder) ApplyClientIntents(clientName, clie
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyClientIntents", reflect.TypeOf((*MockKafkaIntentsAdmin)(nil).ApplyClientIntents), clientName, clientNamespace, intents)
 }
 
+// ApplyClientQuota mocks base method.
+func (m *MockKafkaIntentsAdmin) ApplyClientQuota(clientName, clientNamespace string, quota *v1alpha3.KafkaQuota) (*v1alpha3.KafkaQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyClientQuota", clientName, clientNamespace, quota)
+	ret0, _ := ret[0].(*v1alpha3.KafkaQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyClientQuota indicates an expected call of ApplyClientQuota.
+func (mr *MockKafkaIntentsAdminMockRecorder) ApplyClientQuota(clientName, clientNamespace, quota interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyClientQuota", reflect.TypeOf((*MockKafkaIntentsAdmin)(nil).ApplyClientQuota), clientName, clientNamespace, quota)
+}
+
 // ApplyServerTopicsConf mocks base method.
 func (m *MockKafkaIntentsAdmin) ApplyServerTopicsConf(topicsConf []v1alpha3.TopicConfig) error {
 	m.ctrl.T.Helper()
@@ -101,3 +117,19 @@ func (mr *MockKafkaIntentsAdminMockRecorder) RemoveServerIntents(topicsConf inte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveServerIntents", reflect.TypeOf((*MockKafkaIntentsAdmin)(nil).RemoveServerIntents), topicsConf)
 }
+
+// ResyncACLs mocks base method.
+func (m *MockKafkaIntentsAdmin) ResyncACLs(topicsConf []v1alpha3.TopicConfig, intentsByClient map[types.NamespacedName][]v1alpha3.Intent, repair bool) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResyncACLs", topicsConf, intentsByClient, repair)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ResyncACLs indicates an expected call of ResyncACLs.
+func (mr *MockKafkaIntentsAdminMockRecorder) ResyncACLs(topicsConf, intentsByClient, repair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResyncACLs", reflect.TypeOf((*MockKafkaIntentsAdmin)(nil).ResyncACLs), topicsConf, intentsByClient, repair)
+}
@@ -0,0 +1,208 @@
+package kafkaacls
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	// clusterAdminHealthCheckInterval is how long a pooled connection may go unchecked before it is checked again on use.
+	clusterAdminHealthCheckInterval = 30 * time.Second
+)
+
+// ClusterAdminPool shares sarama ClusterAdmin connections between reconciles, so that reconciling many ClientIntents
+// does not open a connection to the Kafka server for each of them. Connections are keyed by the address and credentials
+// they use, so changed or rotated credentials open a new connection, while the previous one is closed once idle.
+// Connections that were not used for a while are health checked before use, and reopened if the check fails.
+type ClusterAdminPool struct {
+	lock                  sync.Mutex
+	connections           map[string]*pooledClusterAdmin
+	idleTimeout           time.Duration
+	maxConcurrentRequests int
+}
+
+type pooledClusterAdmin struct {
+	// lock guards admin and lastHealthCheck, and is held while connecting, so that concurrent reconciles wait for a
+	// single connection instead of opening one each.
+	lock            sync.Mutex
+	admin           sarama.ClusterAdmin
+	lastHealthCheck time.Time
+	// leases bounds the number of reconciles using the connection concurrently.
+	leases chan struct{}
+	// inUse and lastUsed are guarded by the lock of the pool.
+	inUse    int
+	lastUsed time.Time
+}
+
+// leasedClusterAdmin is a pooled connection, which is released back to the pool when closed.
+type leasedClusterAdmin struct {
+	sarama.ClusterAdmin
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (l *leasedClusterAdmin) Close() error {
+	l.releaseOnce.Do(l.release)
+	return nil
+}
+
+// NewClusterAdminPool creates a pool that closes connections unused for idleTimeout, which must be positive.
+func NewClusterAdminPool(idleTimeout time.Duration, maxConcurrentRequests int) *ClusterAdminPool {
+	return &ClusterAdminPool{
+		connections:           map[string]*pooledClusterAdmin{},
+		idleTimeout:           idleTimeout,
+		maxConcurrentRequests: max(maxConcurrentRequests, 1),
+	}
+}
+
+// clusterAdminPoolKey identifies the connections that can be shared by the address of the Kafka server and the
+// credentials used to connect to it.
+func clusterAdminPoolKey(kafkaServer otterizev1alpha3.KafkaServerConfig, tlsCreds tlsCredentials, saslCredentials map[string][]byte) (string, error) {
+	keyJSON, err := json.Marshal(struct {
+		Addr            string
+		Authentication  *otterizev1alpha3.KafkaAuthentication
+		CertPEM         []byte
+		KeyPEM          []byte
+		RootCAPEM       []byte
+		SASLCredentials map[string][]byte
+	}{
+		Addr:            kafkaServer.Spec.Addr,
+		Authentication:  kafkaServer.Spec.Authentication,
+		CertPEM:         tlsCreds.certPEM,
+		KeyPEM:          tlsCreds.keyPEM,
+		RootCAPEM:       tlsCreds.rootCAPEM,
+		SASLCredentials: saslCredentials,
+	})
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+
+	hash := sha256.Sum256(keyJSON)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Acquire returns the pooled connection for the key, connecting if there is none or if it failed its health check. It
+// blocks while the connection is used by the maximum number of concurrent reconciles. The returned ClusterAdmin must be
+// closed to release it back to the pool.
+func (p *ClusterAdminPool) Acquire(key string, connect func() (sarama.ClusterAdmin, error)) (sarama.ClusterAdmin, error) {
+	p.lock.Lock()
+	connection, ok := p.connections[key]
+	if !ok {
+		connection = &pooledClusterAdmin{leases: make(chan struct{}, p.maxConcurrentRequests)}
+		p.connections[key] = connection
+	}
+	// counting the connection as in use before waiting for a lease keeps it from being evicted meanwhile
+	connection.inUse++
+	p.lock.Unlock()
+
+	connection.leases <- struct{}{}
+	release := func() {
+		<-connection.leases
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		connection.inUse--
+		connection.lastUsed = time.Now()
+	}
+
+	admin, err := connection.getHealthyAdmin(connect)
+	if err != nil {
+		release()
+		return nil, errors.Wrap(err)
+	}
+
+	return &leasedClusterAdmin{ClusterAdmin: admin, release: release}, nil
+}
+
+func (c *pooledClusterAdmin) getHealthyAdmin(connect func() (sarama.ClusterAdmin, error)) (sarama.ClusterAdmin, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.admin != nil && time.Since(c.lastHealthCheck) > clusterAdminHealthCheckInterval {
+		if _, _, err := c.admin.DescribeCluster(); err != nil {
+			logrus.WithError(err).Warn("Pooled Kafka server connection failed health check, reconnecting")
+			if err := c.admin.Close(); err != nil {
+				logrus.WithError(err).Debug("Failed closing unhealthy Kafka server connection")
+			}
+			c.admin = nil
+		} else {
+			c.lastHealthCheck = time.Now()
+		}
+	}
+
+	if c.admin == nil {
+		admin, err := connect()
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		c.admin = admin
+		c.lastHealthCheck = time.Now()
+	}
+
+	return c.admin, nil
+}
+
+// EvictIdle closes the connections that were not used for longer than the idle timeout.
+func (p *ClusterAdminPool) EvictIdle() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key, connection := range p.connections {
+		if connection.inUse > 0 || time.Since(connection.lastUsed) < p.idleTimeout {
+			continue
+		}
+		connection.close()
+		delete(p.connections, key)
+	}
+}
+
+func (p *ClusterAdminPool) closeAll() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for key, connection := range p.connections {
+		connection.close()
+		delete(p.connections, key)
+	}
+}
+
+func (c *pooledClusterAdmin) close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.admin == nil {
+		return
+	}
+	if err := c.admin.Close(); err != nil {
+		logrus.WithError(err).Error("Error closing kafka admin client")
+	}
+	c.admin = nil
+}
+
+// Start implements manager.Runnable, evicting idle connections until the manager stops, and then closing them all.
+func (p *ClusterAdminPool) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.idleTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			p.closeAll()
+			return nil
+		case <-ticker.C:
+			p.EvictIdle()
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, as the pool only closes the connections of the replica
+// it runs on.
+func (p *ClusterAdminPool) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,120 @@
+package kafkaacls
+
+import (
+	"github.com/Shopify/sarama"
+	kafkaaclsmocks "github.com/otterize/intents-operator/src/operator/controllers/kafkaacls/mocks"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+const poolKey = "pool-key"
+
+type ClusterAdminPoolSuite struct {
+	suite.Suite
+	controller *gomock.Controller
+	pool       *ClusterAdminPool
+	connects   int
+}
+
+func (s *ClusterAdminPoolSuite) SetupTest() {
+	s.controller = gomock.NewController(s.T())
+	s.pool = NewClusterAdminPool(time.Minute, 2)
+	s.connects = 0
+}
+
+func (s *ClusterAdminPoolSuite) connectTo(admin sarama.ClusterAdmin) func() (sarama.ClusterAdmin, error) {
+	return func() (sarama.ClusterAdmin, error) {
+		s.connects++
+		return admin, nil
+	}
+}
+
+func (s *ClusterAdminPoolSuite) TestAcquireReusesConnection() {
+	mockClusterAdmin := kafkaaclsmocks.NewMockClusterAdmin(s.controller)
+
+	first, err := s.pool.Acquire(poolKey, s.connectTo(mockClusterAdmin))
+	s.Require().NoError(err)
+	s.Require().NoError(first.Close())
+	second, err := s.pool.Acquire(poolKey, s.connectTo(mockClusterAdmin))
+	s.Require().NoError(err)
+	s.Require().NoError(second.Close())
+
+	// closing a leased connection only releases it, so the mock expects no call to Close
+	s.Equal(1, s.connects)
+	s.Equal(0, s.pool.connections[poolKey].inUse)
+}
+
+func (s *ClusterAdminPoolSuite) TestAcquireReconnectsWhenHealthCheckFails() {
+	unhealthyClusterAdmin := kafkaaclsmocks.NewMockClusterAdmin(s.controller)
+	healthyClusterAdmin := kafkaaclsmocks.NewMockClusterAdmin(s.controller)
+
+	admin, err := s.pool.Acquire(poolKey, s.connectTo(unhealthyClusterAdmin))
+	s.Require().NoError(err)
+	s.Require().NoError(admin.Close())
+
+	s.pool.connections[poolKey].lastHealthCheck = time.Now().Add(-2 * clusterAdminHealthCheckInterval)
+	unhealthyClusterAdmin.EXPECT().DescribeCluster().Return(nil, int32(0), errors.New("connection reset"))
+	unhealthyClusterAdmin.EXPECT().Close().Return(nil)
+
+	admin, err = s.pool.Acquire(poolKey, s.connectTo(healthyClusterAdmin))
+	s.Require().NoError(err)
+	s.Require().NoError(admin.Close())
+	s.Equal(2, s.connects)
+	s.Equal(healthyClusterAdmin, s.pool.connections[poolKey].admin)
+}
+
+func (s *ClusterAdminPoolSuite) TestEvictIdleClosesUnusedConnections() {
+	idleClusterAdmin := kafkaaclsmocks.NewMockClusterAdmin(s.controller)
+	usedClusterAdmin := kafkaaclsmocks.NewMockClusterAdmin(s.controller)
+
+	idle, err := s.pool.Acquire("idle", s.connectTo(idleClusterAdmin))
+	s.Require().NoError(err)
+	s.Require().NoError(idle.Close())
+	s.pool.connections["idle"].lastUsed = time.Now().Add(-2 * time.Minute)
+	used, err := s.pool.Acquire("used", s.connectTo(usedClusterAdmin))
+	s.Require().NoError(err)
+
+	idleClusterAdmin.EXPECT().Close().Return(nil)
+	s.pool.EvictIdle()
+
+	s.NotContains(s.pool.connections, "idle")
+	s.Contains(s.pool.connections, "used")
+	s.Require().NoError(used.Close())
+}
+
+func (s *ClusterAdminPoolSuite) TestAcquireBlocksAtMaxConcurrentRequests() {
+	mockClusterAdmin := kafkaaclsmocks.NewMockClusterAdmin(s.controller)
+
+	first, err := s.pool.Acquire(poolKey, s.connectTo(mockClusterAdmin))
+	s.Require().NoError(err)
+	second, err := s.pool.Acquire(poolKey, s.connectTo(mockClusterAdmin))
+	s.Require().NoError(err)
+
+	acquired := make(chan sarama.ClusterAdmin)
+	go func() {
+		third, err := s.pool.Acquire(poolKey, s.connectTo(mockClusterAdmin))
+		s.NoError(err)
+		acquired <- third
+	}()
+
+	select {
+	case <-acquired:
+		s.Fail("acquired a connection beyond the maximum number of concurrent requests")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	s.Require().NoError(first.Close())
+	// closing a lease twice must not release it twice
+	s.Require().NoError(first.Close())
+	third := <-acquired
+	s.Require().NoError(second.Close())
+	s.Require().NoError(third.Close())
+	s.Equal(1, s.connects)
+}
+
+func TestClusterAdminPoolSuite(t *testing.T) {
+	suite.Run(t, new(ClusterAdminPoolSuite))
+}
@@ -0,0 +1,190 @@
+package kafkaacls
+
+import (
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	producerByteRateQuotaKey  = "producer_byte_rate"
+	consumerByteRateQuotaKey  = "consumer_byte_rate"
+	requestPercentageQuotaKey = "request_percentage"
+)
+
+// quotaKeys are the client quota configuration keys managed by the operator, for principals of clients with intents.
+var quotaKeys = []string{producerByteRateQuotaKey, consumerByteRateQuotaKey, requestPercentageQuotaKey}
+
+// GetClientQuota returns the quota requested by the intents of a client towards a single Kafka server, or nil if none
+// of them request one. If several intents set the same limit, the lowest one applies.
+func GetClientQuota(intents []otterizev1alpha3.Intent) *otterizev1alpha3.KafkaQuota {
+	var quota *otterizev1alpha3.KafkaQuota
+	for _, intent := range intents {
+		if intent.Quota == nil {
+			continue
+		}
+		if quota == nil {
+			quota = &otterizev1alpha3.KafkaQuota{}
+		}
+		quota.ProducerByteRate = minQuotaValue(quota.ProducerByteRate, intent.Quota.ProducerByteRate)
+		quota.ConsumerByteRate = minQuotaValue(quota.ConsumerByteRate, intent.Quota.ConsumerByteRate)
+		quota.RequestPercentage = minQuotaValue(quota.RequestPercentage, intent.Quota.RequestPercentage)
+	}
+
+	return quota
+}
+
+func minQuotaValue(current *int64, requested *int64) *int64 {
+	if current == nil {
+		return requested
+	}
+	if requested == nil {
+		return current
+	}
+	return lo.ToPtr(min(*current, *requested))
+}
+
+func quotaToValues(quota *otterizev1alpha3.KafkaQuota) map[string]int64 {
+	values := map[string]int64{}
+	if quota == nil {
+		return values
+	}
+	for key, value := range map[string]*int64{
+		producerByteRateQuotaKey:  quota.ProducerByteRate,
+		consumerByteRateQuotaKey:  quota.ConsumerByteRate,
+		requestPercentageQuotaKey: quota.RequestPercentage,
+	} {
+		if value != nil {
+			values[key] = *value
+		}
+	}
+	return values
+}
+
+func valuesToQuota(values map[string]int64) *otterizev1alpha3.KafkaQuota {
+	if len(values) == 0 {
+		return nil
+	}
+	quota := &otterizev1alpha3.KafkaQuota{}
+	if value, ok := values[producerByteRateQuotaKey]; ok {
+		quota.ProducerByteRate = lo.ToPtr(value)
+	}
+	if value, ok := values[consumerByteRateQuotaKey]; ok {
+		quota.ConsumerByteRate = lo.ToPtr(value)
+	}
+	if value, ok := values[requestPercentageQuotaKey]; ok {
+		quota.RequestPercentage = lo.ToPtr(value)
+	}
+	return quota
+}
+
+// getDesiredQuotaValues returns the quota values to set, given those applied and those requested by intents. Like ACLs,
+// new quotas are only set while enforcement and Kafka ACL creation are enabled, but are always removed once no longer
+// requested.
+func getDesiredQuotaValues(appliedValues map[string]int64, requestedValues map[string]int64, createEnabled bool) map[string]int64 {
+	if createEnabled {
+		return requestedValues
+	}
+	return lo.PickByKeys(appliedValues, lo.Keys(requestedValues))
+}
+
+// countQuotaDrift returns the number of quota values that are desired but not applied or applied with another value, and
+// the number of quota values that are applied but not desired.
+func countQuotaDrift(appliedValues map[string]int64, desiredValues map[string]int64) (missing int, unexpected int) {
+	for _, key := range quotaKeys {
+		appliedValue, applied := appliedValues[key]
+		desiredValue, desired := desiredValues[key]
+		switch {
+		case desired && (!applied || appliedValue != desiredValue):
+			missing++
+		case !desired && applied:
+			unexpected++
+		}
+	}
+	return missing, unexpected
+}
+
+func (a *KafkaIntentsAdminImpl) queryAppliedClientQuota(userName string) (map[string]int64, error) {
+	entries, err := a.kafkaAdminClient.DescribeClientQuotas([]sarama.QuotaFilterComponent{{
+		EntityType: sarama.QuotaEntityUser,
+		MatchType:  sarama.QuotaMatchExact,
+		Match:      userName,
+	}}, true)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	values := map[string]int64{}
+	for _, entry := range entries {
+		for _, key := range quotaKeys {
+			if value, ok := entry.Values[key]; ok {
+				values[key] = int64(value)
+			}
+		}
+	}
+	return values, nil
+}
+
+// ApplyClientQuota sets the quota requested by the intents of the client on the Kafka server, or removes it if quota is
+// nil, and returns the quota now applied.
+func (a *KafkaIntentsAdminImpl) ApplyClientQuota(clientName string, clientNamespace string, quota *otterizev1alpha3.KafkaQuota) (*otterizev1alpha3.KafkaQuota, error) {
+	userName := formatUserName(a.userNameMapping, clientName, clientNamespace)
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"user":            userName,
+			"serverName":      a.kafkaServer.Spec.Service,
+			"serverNamespace": a.kafkaServer.Namespace,
+		})
+	userEntity := []sarama.QuotaEntityComponent{{EntityType: sarama.QuotaEntityUser, MatchType: sarama.QuotaMatchExact, Name: userName}}
+
+	appliedValues, err := a.queryAppliedClientQuota(userName)
+	if err != nil {
+		return nil, errors.Errorf("failed describing client quotas: %w", err)
+	}
+	desiredValues := getDesiredQuotaValues(appliedValues, quotaToValues(quota), a.enforcementEnabledForServer && a.enableKafkaACLCreation)
+
+	for _, key := range quotaKeys {
+		appliedValue, applied := appliedValues[key]
+		desiredValue, desired := desiredValues[key]
+		switch {
+		case desired && (!applied || appliedValue != desiredValue):
+			logger.Infof("Setting client quota %s to %d", key, desiredValue)
+			err = a.kafkaAdminClient.AlterClientQuotas(userEntity, sarama.ClientQuotasOp{Key: key, Value: float64(desiredValue)}, false)
+		case !desired && applied:
+			logger.Infof("Removing client quota %s", key)
+			err = a.kafkaAdminClient.AlterClientQuotas(userEntity, sarama.ClientQuotasOp{Key: key, Remove: true}, false)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errors.Errorf("failed altering client quota %s: %w", key, err)
+		}
+	}
+
+	return valuesToQuota(desiredValues), nil
+}
+
+// resyncClientQuotas compares the quotas of the clients on the Kafka server with their intents, and repairs the drift if
+// enabled.
+func (a *KafkaIntentsAdminImpl) resyncClientQuotas(intentsByClient map[types.NamespacedName][]otterizev1alpha3.Intent, repair bool) (missing int, unexpected int, err error) {
+	for clientName, intents := range intentsByClient {
+		appliedValues, err := a.queryAppliedClientQuota(formatUserName(a.userNameMapping, clientName.Name, clientName.Namespace))
+		if err != nil {
+			return missing, unexpected, errors.Errorf("failed describing client quotas: %w", err)
+		}
+		quota := GetClientQuota(intents)
+		desiredValues := getDesiredQuotaValues(appliedValues, quotaToValues(quota), a.enforcementEnabledForServer && a.enableKafkaACLCreation)
+		clientMissing, clientUnexpected := countQuotaDrift(appliedValues, desiredValues)
+		missing += clientMissing
+		unexpected += clientUnexpected
+		if repair && (clientMissing > 0 || clientUnexpected > 0) {
+			if _, err := a.ApplyClientQuota(clientName.Name, clientName.Namespace, quota); err != nil {
+				return missing, unexpected, errors.Wrap(err)
+			}
+		}
+	}
+	return missing, unexpected, nil
+}
@@ -0,0 +1,123 @@
+package kafkaacls
+
+import (
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	kafkaaclsmocks "github.com/otterize/intents-operator/src/operator/controllers/kafkaacls/mocks"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+type QuotasSuite struct {
+	suite.Suite
+	mockClusterAdmin *kafkaaclsmocks.MockClusterAdmin
+}
+
+func (s *QuotasSuite) SetupTest() {
+	controller := gomock.NewController(s.T())
+	s.mockClusterAdmin = kafkaaclsmocks.NewMockClusterAdmin(controller)
+}
+
+func (s *QuotasSuite) newIntentsAdmin(enableKafkaACLCreation bool) KafkaIntentsAdmin {
+	kafkaServerConfig := otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: kafkaServerConfigResourceName, Namespace: testNamespace},
+		Spec:       otterizev1alpha3.KafkaServerConfigSpec{Service: otterizev1alpha3.Service{Name: serverName}, Addr: serverAddress},
+	}
+	return NewKafkaIntentsAdminImpl(kafkaServerConfig, s.mockClusterAdmin, "$ServiceName.$Namespace", enableKafkaACLCreation, true)
+}
+
+func (s *QuotasSuite) expectDescribeClientQuotas(values map[string]float64) {
+	var entries []sarama.DescribeClientQuotasEntry
+	if values != nil {
+		entries = []sarama.DescribeClientQuotasEntry{{Entity: userEntity(), Values: values}}
+	}
+	s.mockClusterAdmin.EXPECT().DescribeClientQuotas([]sarama.QuotaFilterComponent{{
+		EntityType: sarama.QuotaEntityUser,
+		MatchType:  sarama.QuotaMatchExact,
+		Match:      "client.client-namespace",
+	}}, true).Return(entries, nil)
+}
+
+func userEntity() []sarama.QuotaEntityComponent {
+	return []sarama.QuotaEntityComponent{{EntityType: sarama.QuotaEntityUser, MatchType: sarama.QuotaMatchExact, Name: "client.client-namespace"}}
+}
+
+func (s *QuotasSuite) TestGetClientQuotaMergesLowestLimits() {
+	quota := GetClientQuota([]otterizev1alpha3.Intent{
+		{Quota: &otterizev1alpha3.KafkaQuota{ProducerByteRate: lo.ToPtr(int64(2048)), ConsumerByteRate: lo.ToPtr(int64(4096))}},
+		{},
+		{Quota: &otterizev1alpha3.KafkaQuota{ProducerByteRate: lo.ToPtr(int64(1024)), RequestPercentage: lo.ToPtr(int64(25))}},
+	})
+	s.Equal(&otterizev1alpha3.KafkaQuota{
+		ProducerByteRate:  lo.ToPtr(int64(1024)),
+		ConsumerByteRate:  lo.ToPtr(int64(4096)),
+		RequestPercentage: lo.ToPtr(int64(25)),
+	}, quota)
+
+	s.Nil(GetClientQuota([]otterizev1alpha3.Intent{{}}))
+}
+
+func (s *QuotasSuite) TestApplyClientQuotaSetsChangedAndRemovesUnrequestedValues() {
+	intentsAdmin := s.newIntentsAdmin(true)
+	s.expectDescribeClientQuotas(map[string]float64{producerByteRateQuotaKey: 1024, consumerByteRateQuotaKey: 1024, requestPercentageQuotaKey: 50})
+	s.mockClusterAdmin.EXPECT().AlterClientQuotas(userEntity(), sarama.ClientQuotasOp{Key: consumerByteRateQuotaKey, Value: 2048}, false).Return(nil)
+	s.mockClusterAdmin.EXPECT().AlterClientQuotas(userEntity(), sarama.ClientQuotasOp{Key: requestPercentageQuotaKey, Remove: true}, false).Return(nil)
+
+	quota := &otterizev1alpha3.KafkaQuota{ProducerByteRate: lo.ToPtr(int64(1024)), ConsumerByteRate: lo.ToPtr(int64(2048))}
+	appliedQuota, err := intentsAdmin.ApplyClientQuota("client", "client-namespace", quota)
+	s.Require().NoError(err)
+	s.Equal(quota, appliedQuota)
+}
+
+func (s *QuotasSuite) TestApplyClientQuotaRemovesQuota() {
+	intentsAdmin := s.newIntentsAdmin(true)
+	s.expectDescribeClientQuotas(map[string]float64{producerByteRateQuotaKey: 1024})
+	s.mockClusterAdmin.EXPECT().AlterClientQuotas(userEntity(), sarama.ClientQuotasOp{Key: producerByteRateQuotaKey, Remove: true}, false).Return(nil)
+
+	appliedQuota, err := intentsAdmin.ApplyClientQuota("client", "client-namespace", nil)
+	s.Require().NoError(err)
+	s.Nil(appliedQuota)
+}
+
+func (s *QuotasSuite) TestApplyClientQuotaSkipsSettingWhenCreationDisabled() {
+	intentsAdmin := s.newIntentsAdmin(false)
+	s.expectDescribeClientQuotas(nil)
+
+	appliedQuota, err := intentsAdmin.ApplyClientQuota("client", "client-namespace", &otterizev1alpha3.KafkaQuota{ProducerByteRate: lo.ToPtr(int64(1024))})
+	s.Require().NoError(err)
+	s.Nil(appliedQuota)
+}
+
+func (s *QuotasSuite) TestResyncClientQuotasReportsDrift() {
+	intentsAdmin := s.newIntentsAdmin(true)
+	intentsByClient := map[types.NamespacedName][]otterizev1alpha3.Intent{
+		{Name: "client", Namespace: "client-namespace"}: {{Quota: &otterizev1alpha3.KafkaQuota{ConsumerByteRate: lo.ToPtr(int64(2048))}}},
+	}
+	s.expectDescribeClientQuotas(map[string]float64{consumerByteRateQuotaKey: 1024, requestPercentageQuotaKey: 50})
+
+	missing, unexpected, err := intentsAdmin.(*KafkaIntentsAdminImpl).resyncClientQuotas(intentsByClient, false)
+	s.Require().NoError(err)
+	s.Equal(1, missing)
+	s.Equal(1, unexpected)
+}
+
+func (s *QuotasSuite) TestResyncClientQuotasRemovesQuotaNotRequested() {
+	intentsAdmin := s.newIntentsAdmin(true)
+	intentsByClient := map[types.NamespacedName][]otterizev1alpha3.Intent{{Name: "client", Namespace: "client-namespace"}: {{}}}
+	s.expectDescribeClientQuotas(map[string]float64{producerByteRateQuotaKey: 1024})
+	s.expectDescribeClientQuotas(map[string]float64{producerByteRateQuotaKey: 1024})
+	s.mockClusterAdmin.EXPECT().AlterClientQuotas(userEntity(), sarama.ClientQuotasOp{Key: producerByteRateQuotaKey, Remove: true}, false).Return(nil)
+
+	missing, unexpected, err := intentsAdmin.(*KafkaIntentsAdminImpl).resyncClientQuotas(intentsByClient, true)
+	s.Require().NoError(err)
+	s.Equal(0, missing)
+	s.Equal(1, unexpected)
+}
+
+func TestQuotasSuite(t *testing.T) {
+	suite.Run(t, new(QuotasSuite))
+}
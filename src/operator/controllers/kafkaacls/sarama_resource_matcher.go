@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"github.com/Shopify/sarama"
 	"go.uber.org/mock/gomock"
-	"golang.org/x/exp/slices"
 	"reflect"
+	"sort"
 )
 
 // Implement gomock matcher interface for sarama.ResourceAcls
@@ -46,8 +46,8 @@ func compareResourceAcls(a, b *sarama.ResourceAcls) bool {
 		return false
 	}
 
-	slices.SortFunc(a.Acls, sortAcl)
-	slices.SortFunc(b.Acls, sortAcl)
+	sort.Slice(a.Acls, func(x, y int) bool { return sortAcl(a.Acls[x], a.Acls[y]) })
+	sort.Slice(b.Acls, func(x, y int) bool { return sortAcl(b.Acls[x], b.Acls[y]) })
 	for i := range a.Acls {
 		if !reflect.DeepEqual(a.Acls[i], b.Acls[i]) {
 			return false
@@ -0,0 +1,118 @@
+package kafkaacls
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/xdg-go/scram"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SASLUsernameKey            = "username"
+	SASLPasswordKey            = "password"
+	OAuthClientIDKey           = "clientId"
+	OAuthClientSecretKey       = "clientSecret"
+	defaultSASLUserNameMapping = "$ServiceName.$Namespace"
+)
+
+// getSASLCredentials reads the Secret referenced by the authentication of the Kafka server.
+func getSASLCredentials(ctx context.Context, k8sClient client.Client, kafkaServer otterizev1alpha3.KafkaServerConfig) (map[string][]byte, error) {
+	return getSecretData(ctx, k8sClient, kafkaServer.Spec.Authentication.SecretRef, kafkaServer.Namespace)
+}
+
+func configureSASL(ctx context.Context, config *sarama.Config, authentication otterizev1alpha3.KafkaAuthentication, credentials map[string][]byte) error {
+	config.Net.SASL.Enable = true
+	config.Net.SASL.Handshake = true
+	config.Net.SASL.Mechanism = sarama.SASLMechanism(authentication.Mechanism)
+
+	switch authentication.Mechanism {
+	case otterizev1alpha3.KafkaSASLMechanismOAuthBearer:
+		if authentication.TokenURL == "" {
+			return errors.Errorf("tokenURL is required for SASL mechanism %s", authentication.Mechanism)
+		}
+		clientID, clientSecret, err := requiredCredentials(credentials, OAuthClientIDKey, OAuthClientSecretKey)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		oauthConfig := clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     authentication.TokenURL,
+			Scopes:       authentication.Scopes,
+		}
+		// Tokens are fetched with the context of the reconcile that connected. A pooled connection whose token can no longer be
+		// refreshed fails its health check, and is reconnected with the context of a later reconcile.
+		config.Net.SASL.TokenProvider = &oauthTokenProvider{tokenSource: oauthConfig.TokenSource(ctx)}
+		return nil
+	case otterizev1alpha3.KafkaSASLMechanismSCRAMSHA256:
+		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha256.New} }
+	case otterizev1alpha3.KafkaSASLMechanismSCRAMSHA512:
+		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: sha512.New} }
+	case otterizev1alpha3.KafkaSASLMechanismPlain:
+	default:
+		return errors.Errorf("unsupported SASL mechanism %s", authentication.Mechanism)
+	}
+
+	username, password, err := requiredCredentials(credentials, SASLUsernameKey, SASLPasswordKey)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	config.Net.SASL.User = username
+	config.Net.SASL.Password = password
+	return nil
+}
+
+func requiredCredentials(credentials map[string][]byte, firstKey string, secondKey string) (string, string, error) {
+	first, second := string(credentials[firstKey]), string(credentials[secondKey])
+	if first == "" || second == "" {
+		return "", "", errors.Errorf("SASL credentials secret must contain the keys %s and %s", firstKey, secondKey)
+	}
+	return first, second, nil
+}
+
+// scramClient implements sarama.SCRAMClient using the xdg-go/scram library.
+type scramClient struct {
+	hashGenerator scram.HashGeneratorFcn
+	conversation  *scram.ClientConversation
+}
+
+func (c *scramClient) Begin(userName string, password string, authzID string) error {
+	newClient, err := c.hashGenerator.NewClient(userName, password, authzID)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	c.conversation = newClient.NewConversation()
+	return nil
+}
+
+func (c *scramClient) Step(challenge string) (string, error) {
+	response, err := c.conversation.Step(challenge)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+	return response, nil
+}
+
+func (c *scramClient) Done() bool {
+	return c.conversation.Done()
+}
+
+// oauthTokenProvider implements sarama.AccessTokenProvider with an OAuth token source, which caches tokens until they
+// expire.
+type oauthTokenProvider struct {
+	tokenSource oauth2.TokenSource
+}
+
+func (p *oauthTokenProvider) Token() (*sarama.AccessToken, error) {
+	token, err := p.tokenSource.Token()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return &sarama.AccessToken{Token: token.AccessToken}, nil
+}
@@ -0,0 +1,86 @@
+package kafkaacls
+
+import (
+	"context"
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+type SASLSuite struct {
+	testbase.MocksSuiteBase
+}
+
+func saslKafkaServerConfig(authentication otterizev1alpha3.KafkaAuthentication) otterizev1alpha3.KafkaServerConfig {
+	return otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: kafkaServerConfigResourceName, Namespace: testNamespace},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			Service:        otterizev1alpha3.Service{Name: serverName},
+			Addr:           serverAddress,
+			Authentication: &authentication,
+		},
+	}
+}
+
+func (s *SASLSuite) TestCredentialsSecretDefaultsToServerNamespace() {
+	kafkaServer := saslKafkaServerConfig(otterizev1alpha3.KafkaAuthentication{
+		Mechanism: otterizev1alpha3.KafkaSASLMechanismSCRAMSHA512,
+		SecretRef: otterizev1alpha3.SecretReference{Name: "kafka-credentials"},
+	})
+
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: "kafka-credentials", Namespace: testNamespace}, gomock.AssignableToTypeOf(&corev1.Secret{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, secret *corev1.Secret, _ ...client.GetOption) error {
+			secret.Data = map[string][]byte{SASLUsernameKey: []byte("operator"), SASLPasswordKey: []byte("secret")}
+			return nil
+		})
+
+	credentials, err := getSASLCredentials(context.Background(), s.Client, kafkaServer)
+	s.Require().NoError(err)
+
+	config := sarama.NewConfig()
+	err = configureSASL(context.Background(), config, *kafkaServer.Spec.Authentication, credentials)
+	s.Require().NoError(err)
+	s.True(config.Net.SASL.Enable)
+	s.Equal(sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512), config.Net.SASL.Mechanism)
+	s.Equal("operator", config.Net.SASL.User)
+	s.Equal("secret", config.Net.SASL.Password)
+	s.Require().NotNil(config.Net.SASL.SCRAMClientGeneratorFunc)
+	s.NoError(config.Net.SASL.SCRAMClientGeneratorFunc().Begin("operator", "secret", ""))
+}
+
+func (s *SASLSuite) TestMissingCredentialKeys() {
+	config := sarama.NewConfig()
+	err := configureSASL(context.Background(), config, otterizev1alpha3.KafkaAuthentication{Mechanism: otterizev1alpha3.KafkaSASLMechanismPlain}, map[string][]byte{SASLUsernameKey: []byte("operator")})
+	s.ErrorContains(err, "must contain the keys username and password")
+}
+
+func (s *SASLSuite) TestOAuthBearer() {
+	config := sarama.NewConfig()
+	authentication := otterizev1alpha3.KafkaAuthentication{Mechanism: otterizev1alpha3.KafkaSASLMechanismOAuthBearer}
+	credentials := map[string][]byte{OAuthClientIDKey: []byte("operator"), OAuthClientSecretKey: []byte("secret")}
+
+	err := configureSASL(context.Background(), config, authentication, credentials)
+	s.ErrorContains(err, "tokenURL is required")
+
+	authentication.TokenURL = "https://auth.example.com/token"
+	err = configureSASL(context.Background(), config, authentication, credentials)
+	s.Require().NoError(err)
+	s.Equal(sarama.SASLMechanism(sarama.SASLTypeOAuth), config.Net.SASL.Mechanism)
+	s.NotNil(config.Net.SASL.TokenProvider)
+}
+
+func (s *SASLSuite) TestUserNameMappingForSASL() {
+	intentsAdmin := NewKafkaIntentsAdminImpl(otterizev1alpha3.KafkaServerConfig{}, nil, defaultSASLUserNameMapping, true, true).(*KafkaIntentsAdminImpl)
+	s.Equal("User:client.client-namespace", intentsAdmin.formatPrincipal("client", "client-namespace"))
+}
+
+func TestSASLSuite(t *testing.T) {
+	suite.Run(t, new(SASLSuite))
+}
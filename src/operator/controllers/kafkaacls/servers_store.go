@@ -1,10 +1,12 @@
 package kafkaacls
 
 import (
+	"context"
 	gerrors "errors"
 	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
 	"github.com/otterize/intents-operator/src/shared/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sync"
 )
 
 var (
@@ -15,11 +17,13 @@ type ServersStore interface {
 	Add(config *otterizev1alpha3.KafkaServerConfig)
 	Remove(serverName string, namespace string)
 	Exists(serverName string, namespace string) bool
-	Get(serverName string, namespace string) (KafkaIntentsAdmin, error)
+	Get(ctx context.Context, serverName string, namespace string) (KafkaIntentsAdmin, error)
 	MapErr(f func(types.NamespacedName, *otterizev1alpha3.KafkaServerConfig, otterizev1alpha3.TLSSource) error) error
 }
 
 type ServersStoreImpl struct {
+	// lock guards serversByName, which is read by the periodic Kafka ACL resync concurrently with reconciles.
+	lock                        sync.RWMutex
 	serversByName               map[types.NamespacedName]*otterizev1alpha3.KafkaServerConfig
 	enableKafkaACLCreation      bool
 	tlsSourceFiles              otterizev1alpha3.TLSSource
@@ -38,33 +42,49 @@ func NewServersStore(tlsSourceFiles otterizev1alpha3.TLSSource, enableKafkaACLCr
 }
 
 func (s *ServersStoreImpl) Add(config *otterizev1alpha3.KafkaServerConfig) {
-	name := types.NamespacedName{Name: config.Spec.Service.Name, Namespace: config.Namespace}
+	name := config.GetServerName()
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.serversByName[name] = config
 }
 
 func (s *ServersStoreImpl) Remove(serverName string, namespace string) {
 	name := types.NamespacedName{Name: serverName, Namespace: namespace}
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	delete(s.serversByName, name)
 }
 
 func (s *ServersStoreImpl) Exists(serverName string, namespace string) bool {
 	name := types.NamespacedName{Name: serverName, Namespace: namespace}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	_, ok := s.serversByName[name]
 	return ok
 }
 
-func (s *ServersStoreImpl) Get(serverName string, namespace string) (KafkaIntentsAdmin, error) {
+func (s *ServersStoreImpl) Get(ctx context.Context, serverName string, namespace string) (KafkaIntentsAdmin, error) {
 	name := types.NamespacedName{Name: serverName, Namespace: namespace}
+	s.lock.RLock()
 	config, ok := s.serversByName[name]
+	s.lock.RUnlock()
 	if !ok {
 		return nil, ServerSpecNotFound
 	}
 
-	return s.IntentsAdminFactoryFunction(*config, s.tlsSourceFiles, s.enableKafkaACLCreation, s.enforcementDefaultState)
+	return s.IntentsAdminFactoryFunction(ctx, *config, s.tlsSourceFiles, s.enableKafkaACLCreation, s.enforcementDefaultState)
 }
 
 func (s *ServersStoreImpl) MapErr(f func(types.NamespacedName, *otterizev1alpha3.KafkaServerConfig, otterizev1alpha3.TLSSource) error) error {
+	// f connects to the Kafka servers, so it is called on a copy rather than while holding the lock.
+	s.lock.RLock()
+	serversByName := make(map[types.NamespacedName]*otterizev1alpha3.KafkaServerConfig, len(s.serversByName))
 	for serverName, config := range s.serversByName {
+		serversByName[serverName] = config
+	}
+	s.lock.RUnlock()
+
+	for serverName, config := range serversByName {
 		if err := f(serverName, config, s.tlsSourceFiles); err != nil {
 			return errors.Wrap(err)
 		}
@@ -0,0 +1,466 @@
+package kafkaacls
+
+import (
+	"context"
+	"github.com/Shopify/sarama"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+)
+
+//+kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkausers,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	StrimziClusterLabelKey = "strimzi.io/cluster"
+)
+
+var (
+	KafkaUserGroupVersionKind = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaUser"}
+
+	saramaResourceTypeToStrimziResourceType = map[sarama.AclResourceType]string{
+		sarama.AclResourceTopic:           "topic",
+		sarama.AclResourceGroup:           "group",
+		sarama.AclResourceTransactionalID: "transactionalId",
+		sarama.AclResourceCluster:         "cluster",
+	}
+	saramaPatternTypeToStrimziPatternType = map[sarama.AclResourcePatternType]string{
+		sarama.AclPatternLiteral:  "literal",
+		sarama.AclPatternPrefixed: "prefix",
+	}
+	quotaKeyToStrimziQuotaField = map[string]string{
+		producerByteRateQuotaKey:  "producerByteRate",
+		consumerByteRateQuotaKey:  "consumerByteRate",
+		requestPercentageQuotaKey: "requestPercentage",
+	}
+)
+
+type strimziACLResource struct {
+	Type        string `json:"type"`
+	Name        string `json:"name,omitempty"`
+	PatternType string `json:"patternType,omitempty"`
+}
+
+type strimziACLRule struct {
+	Resource   strimziACLResource `json:"resource"`
+	Operations []string           `json:"operations"`
+	Host       string             `json:"host,omitempty"`
+	Type       string             `json:"type,omitempty"`
+}
+
+// strimziACL is a single operation allowed on a resource, which KafkaUser resources group into ACL rules by resource.
+type strimziACL struct {
+	resource  strimziACLResource
+	operation string
+}
+
+// StrimziKafkaIntentsAdmin enforces intents by writing the ACLs of each client to the authorization of its KafkaUser
+// resource, which the Strimzi User Operator applies to the brokers. KafkaUsers that already exist, for example to
+// generate the credentials of the client, are only updated, and KafkaUsers created by the operator are labeled with the
+// KafkaServerConfig, so they can be deleted when the client has no intents left.
+type StrimziKafkaIntentsAdmin struct {
+	k8sClient                   client.Client
+	kafkaServer                 otterizev1alpha3.KafkaServerConfig
+	userNameMapping             string
+	enableKafkaACLCreation      bool
+	enforcementEnabledForServer bool
+	// aclCollector computes the ACLs required by intents, without connecting to the Kafka server.
+	aclCollector *KafkaIntentsAdminImpl
+}
+
+func NewStrimziKafkaIntentsAdmin(k8sClient client.Client, kafkaServer otterizev1alpha3.KafkaServerConfig, enableKafkaACLCreation bool, enforcementEnabledForServer bool) KafkaIntentsAdmin {
+	userNameMapping := lo.Ternary(kafkaServer.Spec.UserNameMapping != "", kafkaServer.Spec.UserNameMapping, defaultSASLUserNameMapping)
+	return &StrimziKafkaIntentsAdmin{
+		k8sClient:                   k8sClient,
+		kafkaServer:                 kafkaServer,
+		userNameMapping:             userNameMapping,
+		enableKafkaACLCreation:      enableKafkaACLCreation,
+		enforcementEnabledForServer: enforcementEnabledForServer,
+		aclCollector:                &KafkaIntentsAdminImpl{kafkaServer: kafkaServer, userNameMapping: userNameMapping},
+	}
+}
+
+func (a *StrimziKafkaIntentsAdmin) namespace() string {
+	return lo.Ternary(a.kafkaServer.Spec.Strimzi.Namespace != "", a.kafkaServer.Spec.Strimzi.Namespace, a.kafkaServer.Namespace)
+}
+
+func (a *StrimziKafkaIntentsAdmin) logger(userName string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		"kafkaUser":       userName,
+		"serverName":      a.kafkaServer.Spec.Service,
+		"serverNamespace": a.kafkaServer.Namespace,
+	})
+}
+
+func (a *StrimziKafkaIntentsAdmin) ApplyServerTopicsConf(topicsConf []otterizev1alpha3.TopicConfig) error {
+	if len(topicsConf) > 0 {
+		logrus.WithField("server", a.kafkaServer.Spec.Service).Warn("Topic configurations are not applied to Strimzi Kafka clusters, configure them using the authorization of the Kafka resource")
+	}
+	return nil
+}
+
+func (a *StrimziKafkaIntentsAdmin) RemoveServerIntents(_ []otterizev1alpha3.TopicConfig) error {
+	return nil
+}
+
+func (a *StrimziKafkaIntentsAdmin) ApplyClientIntents(clientName string, clientNamespace string, intents []otterizev1alpha3.Intent) error {
+	ctx := context.Background()
+	userName := formatUserName(a.userNameMapping, clientName, clientNamespace)
+	expectedACLs, err := a.getExpectedACLs(userName, intents)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	kafkaUser, err := a.getKafkaUser(ctx, userName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	appliedACLs, err := getKafkaUserACLs(kafkaUser)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	desiredACLs := expectedACLs
+	if !a.enforcementEnabledForServer || !a.enableKafkaACLCreation {
+		// like KafkaIntentsAdminImpl, only remove the ACLs that are no longer required
+		a.logger(userName).Infof("Skipped creation of %d new ACLs because enforcement or Kafka ACL creation is disabled", len(lo.Without(lo.Keys(expectedACLs), lo.Keys(appliedACLs)...)))
+		desiredACLs = lo.PickByKeys(appliedACLs, lo.Keys(expectedACLs))
+	}
+
+	return a.applyKafkaUserACLs(ctx, userName, kafkaUser, appliedACLs, desiredACLs)
+}
+
+func (a *StrimziKafkaIntentsAdmin) RemoveClientIntents(clientName string, clientNamespace string) error {
+	ctx := context.Background()
+	userName := formatUserName(a.userNameMapping, clientName, clientNamespace)
+	kafkaUser, err := a.getKafkaUser(ctx, userName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	appliedACLs, err := getKafkaUserACLs(kafkaUser)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return a.applyKafkaUserACLs(ctx, userName, kafkaUser, appliedACLs, nil)
+}
+
+// ResyncACLs compares the authorization and quotas of the KafkaUsers of the clients with their intents. KafkaUsers
+// created by the operator for clients that no longer have intents are unexpected as well. The ACLs on the brokers are left to the
+// Strimzi User Operator, which reconciles them with the KafkaUsers.
+func (a *StrimziKafkaIntentsAdmin) ResyncACLs(_ []otterizev1alpha3.TopicConfig, intentsByClient map[types.NamespacedName][]otterizev1alpha3.Intent, repair bool) (missing int, unexpected int, err error) {
+	ctx := context.Background()
+	userNames := make(map[string]bool, len(intentsByClient))
+	for clientName, intents := range intentsByClient {
+		userName := formatUserName(a.userNameMapping, clientName.Name, clientName.Namespace)
+		userNames[userName] = true
+		expectedACLs, err := a.getExpectedACLs(userName, intents)
+		if err != nil {
+			return 0, 0, errors.Wrap(err)
+		}
+		kafkaUser, err := a.getKafkaUser(ctx, userName)
+		if err != nil {
+			return 0, 0, errors.Wrap(err)
+		}
+		appliedACLs, err := getKafkaUserACLs(kafkaUser)
+		if err != nil {
+			return 0, 0, errors.Wrap(err)
+		}
+
+		clientMissing := lo.Without(lo.Keys(expectedACLs), lo.Keys(appliedACLs)...)
+		clientUnexpected := lo.Without(lo.Keys(appliedACLs), lo.Keys(expectedACLs)...)
+		if a.enforcementEnabledForServer && a.enableKafkaACLCreation {
+			missing += len(clientMissing)
+		}
+		unexpected += len(clientUnexpected)
+		if repair && (len(clientMissing) > 0 || len(clientUnexpected) > 0) {
+			if err := a.ApplyClientIntents(clientName.Name, clientName.Namespace, intents); err != nil {
+				return missing, unexpected, errors.Wrap(err)
+			}
+		}
+
+		if kafkaUser == nil {
+			continue
+		}
+		appliedQuotaValues, err := getKafkaUserQuotaValues(kafkaUser)
+		if err != nil {
+			return missing, unexpected, errors.Wrap(err)
+		}
+		quota := GetClientQuota(intents)
+		desiredQuotaValues := getDesiredQuotaValues(appliedQuotaValues, quotaToValues(quota), a.enforcementEnabledForServer && a.enableKafkaACLCreation)
+		quotasMissing, quotasUnexpected := countQuotaDrift(appliedQuotaValues, desiredQuotaValues)
+		missing += quotasMissing
+		unexpected += quotasUnexpected
+		if repair && (quotasMissing > 0 || quotasUnexpected > 0) {
+			if _, err := a.ApplyClientQuota(clientName.Name, clientName.Namespace, quota); err != nil {
+				return missing, unexpected, errors.Wrap(err)
+			}
+		}
+	}
+
+	kafkaUsers := &unstructured.UnstructuredList{}
+	kafkaUsers.SetGroupVersionKind(KafkaUserGroupVersionKind.GroupVersion().WithKind(KafkaUserGroupVersionKind.Kind + "List"))
+	err = a.k8sClient.List(ctx, kafkaUsers, client.InNamespace(a.namespace()), client.MatchingLabels{otterizev1alpha3.OtterizeKafkaServerConfigLabelKey: a.kafkaServer.Name})
+	if err != nil {
+		return missing, unexpected, errors.Wrap(err)
+	}
+	for i := range kafkaUsers.Items {
+		kafkaUser := &kafkaUsers.Items[i]
+		if userNames[kafkaUser.GetName()] {
+			continue
+		}
+		appliedACLs, err := getKafkaUserACLs(kafkaUser)
+		if err != nil {
+			return missing, unexpected, errors.Wrap(err)
+		}
+		unexpected += len(appliedACLs)
+		if repair {
+			if err := a.applyKafkaUserACLs(ctx, kafkaUser.GetName(), kafkaUser, appliedACLs, nil); err != nil {
+				return missing, unexpected, errors.Wrap(err)
+			}
+		}
+	}
+
+	return missing, unexpected, nil
+}
+
+// ApplyClientQuota writes the quota to the quotas of the KafkaUser of the client, leaving other quotas of the KafkaUser
+// as they are. KafkaUsers are created along with the ACLs of the client, so quotas of clients without one are skipped.
+func (a *StrimziKafkaIntentsAdmin) ApplyClientQuota(clientName string, clientNamespace string, quota *otterizev1alpha3.KafkaQuota) (*otterizev1alpha3.KafkaQuota, error) {
+	ctx := context.Background()
+	userName := formatUserName(a.userNameMapping, clientName, clientNamespace)
+	logger := a.logger(userName)
+	kafkaUser, err := a.getKafkaUser(ctx, userName)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	if kafkaUser == nil {
+		if quota != nil {
+			logger.Warn("Skipped applying Kafka quota, as the client has no KafkaUser")
+		}
+		return nil, nil
+	}
+
+	appliedValues, err := getKafkaUserQuotaValues(kafkaUser)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	desiredValues := getDesiredQuotaValues(appliedValues, quotaToValues(quota), a.enforcementEnabledForServer && a.enableKafkaACLCreation)
+	if reflect.DeepEqual(appliedValues, desiredValues) {
+		logger.Debug("KafkaUser quotas are up to date")
+		return valuesToQuota(desiredValues), nil
+	}
+
+	for key, field := range quotaKeyToStrimziQuotaField {
+		if value, ok := desiredValues[key]; ok {
+			err = unstructured.SetNestedField(kafkaUser.Object, value, "spec", "quotas", field)
+		} else {
+			unstructured.RemoveNestedField(kafkaUser.Object, "spec", "quotas", field)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+	}
+	if quotas, found, _ := unstructured.NestedMap(kafkaUser.Object, "spec", "quotas"); found && len(quotas) == 0 {
+		unstructured.RemoveNestedField(kafkaUser.Object, "spec", "quotas")
+	}
+
+	logger.Infof("Updating KafkaUser with %d quotas", len(desiredValues))
+	if err := a.k8sClient.Update(ctx, kafkaUser); err != nil {
+		return nil, errors.Wrap(err)
+	}
+	return valuesToQuota(desiredValues), nil
+}
+
+func (a *StrimziKafkaIntentsAdmin) Close() {}
+
+func (a *StrimziKafkaIntentsAdmin) getExpectedACLs(userName string, intents []otterizev1alpha3.Intent) (map[strimziACL]bool, error) {
+	resourceToACLs, err := a.aclCollector.getExpectedClientACLs("User:"+userName, intents)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	acls := map[strimziACL]bool{}
+	for resource, resourceACLs := range resourceToACLs {
+		strimziResource := strimziACLResource{Type: saramaResourceTypeToStrimziResourceType[resource.ResourceType]}
+		if resource.ResourceType != sarama.AclResourceCluster {
+			strimziResource.Name = resource.ResourceName
+			strimziResource.PatternType = saramaPatternTypeToStrimziPatternType[resource.ResourcePatternType]
+		}
+		for _, acl := range resourceACLs {
+			acls[strimziACL{resource: strimziResource, operation: acl.Operation.String()}] = true
+		}
+	}
+
+	return acls, nil
+}
+
+func (a *StrimziKafkaIntentsAdmin) getKafkaUser(ctx context.Context, userName string) (*unstructured.Unstructured, error) {
+	kafkaUser := &unstructured.Unstructured{}
+	kafkaUser.SetGroupVersionKind(KafkaUserGroupVersionKind)
+	err := a.k8sClient.Get(ctx, types.NamespacedName{Name: userName, Namespace: a.namespace()}, kafkaUser)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	return kafkaUser, nil
+}
+
+// getKafkaUserACLs returns the allow ACLs in the authorization of the KafkaUser, which may be nil.
+func getKafkaUserACLs(kafkaUser *unstructured.Unstructured) (map[strimziACL]bool, error) {
+	acls := map[strimziACL]bool{}
+	if kafkaUser == nil {
+		return acls, nil
+	}
+
+	rules, _, err := unstructured.NestedSlice(kafkaUser.Object, "spec", "authorization", "acls")
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	for _, rule := range rules {
+		ruleObject, ok := rule.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid ACL rule in KafkaUser %s: %v", kafkaUser.GetName(), rule)
+		}
+		var aclRule strimziACLRule
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(ruleObject, &aclRule); err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if aclRule.Type != "" && aclRule.Type != "allow" {
+			continue
+		}
+		for _, operation := range aclRule.Operations {
+			acls[strimziACL{resource: aclRule.Resource, operation: operation}] = true
+		}
+	}
+
+	return acls, nil
+}
+
+// getKafkaUserQuotaValues returns the quotas of the KafkaUser that are managed by the operator.
+func getKafkaUserQuotaValues(kafkaUser *unstructured.Unstructured) (map[string]int64, error) {
+	values := map[string]int64{}
+	for key, field := range quotaKeyToStrimziQuotaField {
+		value, found, err := unstructured.NestedFieldNoCopy(kafkaUser.Object, "spec", "quotas", field)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if !found {
+			continue
+		}
+		switch typedValue := value.(type) {
+		case int64:
+			values[key] = typedValue
+		case float64:
+			values[key] = int64(typedValue)
+		default:
+			return nil, errors.Errorf("invalid quota %s in KafkaUser %s: %v", field, kafkaUser.GetName(), value)
+		}
+	}
+	return values, nil
+}
+
+// applyKafkaUserACLs writes the desired ACLs to the authorization of the KafkaUser, creating it if needed. If no ACLs
+// are desired, KafkaUsers created by the operator are deleted, and the authorization is removed from others.
+func (a *StrimziKafkaIntentsAdmin) applyKafkaUserACLs(ctx context.Context, userName string, kafkaUser *unstructured.Unstructured, appliedACLs map[strimziACL]bool, desiredACLs map[strimziACL]bool) error {
+	logger := a.logger(userName)
+	if kafkaUser != nil && len(desiredACLs) == len(appliedACLs) && lo.Every(lo.Keys(appliedACLs), lo.Keys(desiredACLs)) {
+		logger.Debug("KafkaUser ACLs are up to date")
+		return nil
+	}
+
+	if len(desiredACLs) == 0 {
+		if kafkaUser == nil {
+			return nil
+		}
+		if _, ok := kafkaUser.GetLabels()[otterizev1alpha3.OtterizeKafkaServerConfigLabelKey]; ok {
+			logger.Info("Deleting KafkaUser, as the client has no Kafka intents left")
+			return errors.Wrap(client.IgnoreNotFound(a.k8sClient.Delete(ctx, kafkaUser)))
+		}
+		logger.Info("Removing the authorization of KafkaUser, as the client has no Kafka intents left")
+		unstructured.RemoveNestedField(kafkaUser.Object, "spec", "authorization")
+		return errors.Wrap(a.k8sClient.Update(ctx, kafkaUser))
+	}
+
+	rules, err := aclsToKafkaUserRules(desiredACLs)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if kafkaUser == nil {
+		kafkaUser = &unstructured.Unstructured{}
+		kafkaUser.SetGroupVersionKind(KafkaUserGroupVersionKind)
+		kafkaUser.SetName(userName)
+		kafkaUser.SetNamespace(a.namespace())
+		kafkaUser.SetLabels(map[string]string{
+			StrimziClusterLabelKey:                             a.kafkaServer.Spec.Strimzi.ClusterName,
+			otterizev1alpha3.OtterizeKafkaServerConfigLabelKey: a.kafkaServer.Name,
+		})
+		if err := setKafkaUserAuthorization(kafkaUser, rules); err != nil {
+			return errors.Wrap(err)
+		}
+		logger.Infof("Creating KafkaUser with %d ACLs", len(desiredACLs))
+		return errors.Wrap(a.k8sClient.Create(ctx, kafkaUser))
+	}
+
+	if err := setKafkaUserAuthorization(kafkaUser, rules); err != nil {
+		return errors.Wrap(err)
+	}
+	logger.Infof("Updating KafkaUser with %d ACLs", len(desiredACLs))
+	return errors.Wrap(a.k8sClient.Update(ctx, kafkaUser))
+}
+
+func setKafkaUserAuthorization(kafkaUser *unstructured.Unstructured, rules []interface{}) error {
+	if err := unstructured.SetNestedField(kafkaUser.Object, "simple", "spec", "authorization", "type"); err != nil {
+		return errors.Wrap(err)
+	}
+	return errors.Wrap(unstructured.SetNestedSlice(kafkaUser.Object, rules, "spec", "authorization", "acls"))
+}
+
+// aclsToKafkaUserRules groups the ACLs into a rule per resource, sorted so that unchanged ACLs are written identically.
+func aclsToKafkaUserRules(acls map[strimziACL]bool) ([]interface{}, error) {
+	operationsByResource := map[strimziACLResource][]string{}
+	for acl := range acls {
+		operationsByResource[acl.resource] = append(operationsByResource[acl.resource], acl.operation)
+	}
+
+	resources := lo.Keys(operationsByResource)
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		if resources[i].Name != resources[j].Name {
+			return resources[i].Name < resources[j].Name
+		}
+		return resources[i].PatternType < resources[j].PatternType
+	})
+
+	rules := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		operations := operationsByResource[resource]
+		sort.Strings(operations)
+		rule, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&strimziACLRule{
+			Resource:   resource,
+			Operations: operations,
+			Host:       "*",
+			Type:       "allow",
+		})
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
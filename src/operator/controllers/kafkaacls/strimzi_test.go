@@ -0,0 +1,219 @@
+package kafkaacls
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+const (
+	strimziNamespace   = "kafka"
+	strimziClusterName = "my-cluster"
+	kafkaUserName      = "client.client-namespace"
+)
+
+type StrimziSuite struct {
+	testbase.MocksSuiteBase
+}
+
+func strimziKafkaServerConfig() otterizev1alpha3.KafkaServerConfig {
+	return otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: kafkaServerConfigResourceName, Namespace: testNamespace},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			Service: otterizev1alpha3.Service{Name: serverName},
+			Strimzi: &otterizev1alpha3.StrimziConfig{ClusterName: strimziClusterName, Namespace: strimziNamespace},
+		},
+	}
+}
+
+func consumeIntent(topics ...string) []otterizev1alpha3.Intent {
+	intent := otterizev1alpha3.Intent{Name: serverName, Type: otterizev1alpha3.IntentTypeKafka}
+	for _, topic := range topics {
+		intent.Topics = append(intent.Topics, otterizev1alpha3.KafkaTopic{Name: topic, Operations: []otterizev1alpha3.KafkaOperation{otterizev1alpha3.KafkaOperationConsume}})
+	}
+	return []otterizev1alpha3.Intent{intent}
+}
+
+func topicACLRule(topic string, operations ...interface{}) interface{} {
+	return map[string]interface{}{
+		"resource":   map[string]interface{}{"type": "topic", "name": topic, "patternType": "literal"},
+		"operations": operations,
+		"host":       "*",
+		"type":       "allow",
+	}
+}
+
+func kafkaUserWithACLs(labels map[string]string, rules ...interface{}) *unstructured.Unstructured {
+	kafkaUser := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"authentication": map[string]interface{}{"type": "tls"},
+			"authorization":  map[string]interface{}{"type": "simple", "acls": rules},
+		},
+	}}
+	kafkaUser.SetGroupVersionKind(KafkaUserGroupVersionKind)
+	kafkaUser.SetName(kafkaUserName)
+	kafkaUser.SetNamespace(strimziNamespace)
+	kafkaUser.SetLabels(labels)
+	return kafkaUser
+}
+
+func (s *StrimziSuite) expectGetKafkaUser(kafkaUser *unstructured.Unstructured) {
+	s.Client.EXPECT().Get(gomock.Any(), types.NamespacedName{Name: kafkaUserName, Namespace: strimziNamespace}, gomock.AssignableToTypeOf(&unstructured.Unstructured{})).DoAndReturn(
+		func(_ context.Context, _ types.NamespacedName, obj *unstructured.Unstructured, _ ...client.GetOption) error {
+			if kafkaUser == nil {
+				return k8serrors.NewNotFound(schema.GroupResource{Group: KafkaUserGroupVersionKind.Group, Resource: "kafkausers"}, kafkaUserName)
+			}
+			kafkaUser.DeepCopyInto(obj)
+			return nil
+		})
+}
+
+func (s *StrimziSuite) TestApplyClientIntentsCreatesKafkaUser() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	s.expectGetKafkaUser(nil)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, kafkaUser *unstructured.Unstructured, _ ...client.CreateOption) error {
+			s.Equal(map[string]string{
+				StrimziClusterLabelKey:                             strimziClusterName,
+				otterizev1alpha3.OtterizeKafkaServerConfigLabelKey: kafkaServerConfigResourceName,
+			}, kafkaUser.GetLabels())
+			acls, _, err := unstructured.NestedSlice(kafkaUser.Object, "spec", "authorization", "acls")
+			s.Require().NoError(err)
+			s.Equal([]interface{}{topicACLRule("orders", "Read"), topicACLRule("payments", "Read")}, acls)
+			return nil
+		})
+
+	err := intentsAdmin.ApplyClientIntents("client", "client-namespace", consumeIntent("payments", "orders"))
+	s.Require().NoError(err)
+}
+
+func (s *StrimziSuite) TestApplyClientIntentsUpdatesExistingKafkaUser() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	s.expectGetKafkaUser(kafkaUserWithACLs(nil, topicACLRule("old-topic", "Read")))
+	s.Client.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, kafkaUser *unstructured.Unstructured, _ ...client.UpdateOption) error {
+			authenticationType, _, _ := unstructured.NestedString(kafkaUser.Object, "spec", "authentication", "type")
+			s.Equal("tls", authenticationType)
+			acls, _, err := unstructured.NestedSlice(kafkaUser.Object, "spec", "authorization", "acls")
+			s.Require().NoError(err)
+			s.Equal([]interface{}{topicACLRule("orders", "Read")}, acls)
+			return nil
+		})
+
+	err := intentsAdmin.ApplyClientIntents("client", "client-namespace", consumeIntent("orders"))
+	s.Require().NoError(err)
+}
+
+func (s *StrimziSuite) TestApplyClientIntentsUpToDate() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	s.expectGetKafkaUser(kafkaUserWithACLs(nil, topicACLRule("orders", "Read")))
+
+	err := intentsAdmin.ApplyClientIntents("client", "client-namespace", consumeIntent("orders"))
+	s.Require().NoError(err)
+}
+
+func (s *StrimziSuite) TestApplyClientIntentsOnlyRemovesACLsWhenCreationDisabled() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), false, true)
+	s.expectGetKafkaUser(kafkaUserWithACLs(nil, topicACLRule("orders", "Read"), topicACLRule("old-topic", "Read")))
+	s.Client.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, kafkaUser *unstructured.Unstructured, _ ...client.UpdateOption) error {
+			acls, _, err := unstructured.NestedSlice(kafkaUser.Object, "spec", "authorization", "acls")
+			s.Require().NoError(err)
+			s.Equal([]interface{}{topicACLRule("orders", "Read")}, acls)
+			return nil
+		})
+
+	err := intentsAdmin.ApplyClientIntents("client", "client-namespace", consumeIntent("orders", "payments"))
+	s.Require().NoError(err)
+}
+
+func (s *StrimziSuite) TestRemoveClientIntentsDeletesCreatedKafkaUser() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	kafkaUser := kafkaUserWithACLs(map[string]string{otterizev1alpha3.OtterizeKafkaServerConfigLabelKey: kafkaServerConfigResourceName}, topicACLRule("orders", "Read"))
+	s.expectGetKafkaUser(kafkaUser)
+	s.Client.EXPECT().Delete(gomock.Any(), kafkaUser).Return(nil)
+
+	err := intentsAdmin.RemoveClientIntents("client", "client-namespace")
+	s.Require().NoError(err)
+}
+
+func (s *StrimziSuite) TestRemoveClientIntentsKeepsExistingKafkaUser() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	s.expectGetKafkaUser(kafkaUserWithACLs(nil, topicACLRule("orders", "Read")))
+	s.Client.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, kafkaUser *unstructured.Unstructured, _ ...client.UpdateOption) error {
+			_, found, _ := unstructured.NestedMap(kafkaUser.Object, "spec", "authorization")
+			s.False(found)
+			_, found, _ = unstructured.NestedMap(kafkaUser.Object, "spec", "authentication")
+			s.True(found)
+			return nil
+		})
+
+	err := intentsAdmin.RemoveClientIntents("client", "client-namespace")
+	s.Require().NoError(err)
+}
+
+func (s *StrimziSuite) TestApplyClientQuotaUpdatesKafkaUserQuotas() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	kafkaUser := kafkaUserWithACLs(nil, topicACLRule("orders", "Read"))
+	s.Require().NoError(unstructured.SetNestedField(kafkaUser.Object, int64(4), "spec", "quotas", "controllerMutationRate"))
+	s.Require().NoError(unstructured.SetNestedField(kafkaUser.Object, int64(50), "spec", "quotas", "requestPercentage"))
+	s.expectGetKafkaUser(kafkaUser)
+	s.Client.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, kafkaUser *unstructured.Unstructured, _ ...client.UpdateOption) error {
+			quotas, _, err := unstructured.NestedMap(kafkaUser.Object, "spec", "quotas")
+			s.Require().NoError(err)
+			s.Equal(map[string]interface{}{"controllerMutationRate": int64(4), "consumerByteRate": int64(2048)}, quotas)
+			return nil
+		})
+
+	quota := &otterizev1alpha3.KafkaQuota{ConsumerByteRate: lo.ToPtr(int64(2048))}
+	appliedQuota, err := intentsAdmin.ApplyClientQuota("client", "client-namespace", quota)
+	s.Require().NoError(err)
+	s.Equal(quota, appliedQuota)
+}
+
+func (s *StrimziSuite) TestApplyClientQuotaSkipsMissingKafkaUser() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	s.expectGetKafkaUser(nil)
+
+	appliedQuota, err := intentsAdmin.ApplyClientQuota("client", "client-namespace", &otterizev1alpha3.KafkaQuota{ConsumerByteRate: lo.ToPtr(int64(2048))})
+	s.Require().NoError(err)
+	s.Nil(appliedQuota)
+}
+
+func (s *StrimziSuite) TestResyncACLsRemovesKafkaUserQuotaNotRequested() {
+	intentsAdmin := NewStrimziKafkaIntentsAdmin(s.Client, strimziKafkaServerConfig(), true, true)
+	kafkaUser := kafkaUserWithACLs(nil, topicACLRule("orders", "Read"))
+	s.Require().NoError(unstructured.SetNestedField(kafkaUser.Object, int64(50), "spec", "quotas", "requestPercentage"))
+	s.expectGetKafkaUser(kafkaUser)
+	s.expectGetKafkaUser(kafkaUser)
+	s.Client.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, kafkaUser *unstructured.Unstructured, _ ...client.UpdateOption) error {
+			_, found, err := unstructured.NestedMap(kafkaUser.Object, "spec", "quotas")
+			s.Require().NoError(err)
+			s.False(found)
+			return nil
+		})
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&unstructured.UnstructuredList{}), gomock.Any(), gomock.Any()).Return(nil)
+
+	intentsByClient := map[types.NamespacedName][]otterizev1alpha3.Intent{{Name: "client", Namespace: "client-namespace"}: consumeIntent("orders")}
+	missing, unexpected, err := intentsAdmin.ResyncACLs(nil, intentsByClient, true)
+	s.Require().NoError(err)
+	s.Equal(0, missing)
+	s.Equal(1, unexpected)
+}
+
+func TestStrimziSuite(t *testing.T) {
+	suite.Run(t, new(StrimziSuite))
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/otterize/intents-operator/src/shared/telemetries/telemetriesconfig"
 	"github.com/samber/lo"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -101,6 +102,7 @@ func (r *KafkaServerConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&otterizev1alpha3.KafkaServerConfig{}).
 		WithOptions(controller.Options{RecoverPanic: lo.ToPtr(true)}).
 		Watches(&otterizev1alpha3.ProtectedService{}, handler.EnqueueRequestsFromMapFunc(r.mapProtectedServiceToKafkaServerConfig)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapSecretToKafkaServerConfig)).
 		Complete(r)
 	if err != nil {
 		return errors.Wrap(err)
@@ -112,7 +114,7 @@ func (r *KafkaServerConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func (r *KafkaServerConfigReconciler) InitKafkaServerConfigIndices(mgr ctrl.Manager) error {
-	return mgr.GetCache().IndexField(
+	err := mgr.GetCache().IndexField(
 		context.Background(),
 		&otterizev1alpha3.KafkaServerConfig{},
 		otterizev1alpha3.OtterizeKafkaServerConfigServiceNameField,
@@ -120,6 +122,46 @@ func (r *KafkaServerConfigReconciler) InitKafkaServerConfigIndices(mgr ctrl.Mana
 			ksc := object.(*otterizev1alpha3.KafkaServerConfig)
 			return []string{ksc.Spec.Service.Name}
 		})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	err = mgr.GetCache().IndexField(
+		context.Background(),
+		&otterizev1alpha3.KafkaServerConfig{},
+		otterizev1alpha3.KafkaServerConfigSecretNamesIndexField,
+		func(object client.Object) []string {
+			ksc := object.(*otterizev1alpha3.KafkaServerConfig)
+			return lo.Map(kafkaacls.ReferencedSecrets(*ksc), func(secretName types.NamespacedName, _ int) string {
+				return secretName.String()
+			})
+		})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *KafkaServerConfigReconciler) mapSecretToKafkaServerConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	secretName := client.ObjectKeyFromObject(obj)
+	var kafkaServerConfigs otterizev1alpha3.KafkaServerConfigList
+	err := r.Client.List(ctx,
+		&kafkaServerConfigs,
+		&client.MatchingFields{otterizev1alpha3.KafkaServerConfigSecretNamesIndexField: secretName.String()},
+	)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to list KSCs for secret %s", secretName.String())
+		return nil
+	}
+
+	if len(kafkaServerConfigs.Items) > 0 {
+		logrus.Debugf("Enqueueing KafkaServerConfigs for credentials secret %s", secretName.String())
+	}
+
+	return lo.Map(kafkaServerConfigs.Items, func(ksc otterizev1alpha3.KafkaServerConfig, _ int) reconcile.Request {
+		return reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&ksc)}
+	})
 }
 
 func (r *KafkaServerConfigReconciler) mapProtectedServiceToKafkaServerConfig(ctx context.Context, obj client.Object) []reconcile.Request {
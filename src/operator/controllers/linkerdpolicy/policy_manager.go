@@ -0,0 +1,603 @@
+package linkerdpolicy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/amit7itz/goset"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/protected_services"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/otterize/intents-operator/src/shared/serviceidresolver"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"maps"
+	"reflect"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+const (
+	ReasonGettingLinkerdPolicyFailed       = "GettingLinkerdPolicyFailed"
+	ReasonCreatingLinkerdPolicyFailed      = "CreatingLinkerdPolicyFailed"
+	ReasonUpdatingLinkerdPolicyFailed      = "UpdatingLinkerdPolicyFailed"
+	ReasonDeleteLinkerdPolicyFailed        = "DeleteLinkerdPolicyFailed"
+	ReasonCreatedLinkerdPolicy             = "CreatedLinkerdPolicy"
+	ReasonNamespaceNotAllowed              = "NamespaceNotAllowed"
+	ReasonMissingLinkerdProxy              = "MissingLinkerdProxy"
+	ReasonLinkerdServerPortsNotFound       = "LinkerdServerPortsNotFound"
+	OtterizeLinkerdServerNameTemplate      = "otterize-%s-%d"
+	OtterizeLinkerdAuthnNameTemplate       = "meshtls-authn-to-%s-from-%s"
+	OtterizeLinkerdRouteNameTemplate       = "%s-route-%s"
+	OtterizeLinkerdPolicyNameTemplate      = "authorization-policy-to-%s-from-%s"
+	OtterizeLinkerdPortPolicyNameTemplate  = "authorization-policy-to-%s-port-%d-from-%s"
+	LinkerdMeshTLSIdentityTemplate         = "%s.%s.serviceaccount.identity.linkerd.cluster.local"
+	linkerdServerManagedByOtterizeLabelVal = "true"
+)
+
+//+kubebuilder:rbac:groups="policy.linkerd.io",resources=servers;httproutes;authorizationpolicies;meshtlsauthentications,verbs=get;update;patch;list;watch;delete;deletecollection;create
+//+kubebuilder:rbac:groups=k8s.otterize.com,resources=clientintents,verbs=get;list;watch;create;update;patch;delete
+
+type PolicyManagerImpl struct {
+	client                      client.Client
+	recorder                    *injectablerecorder.InjectableRecorder
+	serviceIdResolver           serviceidresolver.ServiceResolver
+	restrictToNamespaces        []string
+	activeNamespaces            *goset.Set[string]
+	enforcementDefaultState     bool
+	enableLinkerdPolicyCreation bool
+}
+
+type PolicyManager interface {
+	DeleteAll(ctx context.Context, clientIntents *v1alpha3.ClientIntents) error
+	Create(ctx context.Context, clientIntents *v1alpha3.ClientIntents, clientServiceAccount string) error
+	UpdateIntentsStatus(ctx context.Context, clientIntents *v1alpha3.ClientIntents, clientServiceAccount string) error
+}
+
+func NewPolicyManager(client client.Client, recorder *injectablerecorder.InjectableRecorder, serviceIdResolver serviceidresolver.ServiceResolver, restrictedNamespaces []string, enforcementDefaultState bool, linkerdEnforcementEnabled bool, activeNamespaces *goset.Set[string]) *PolicyManagerImpl {
+	return &PolicyManagerImpl{
+		client:                      client,
+		recorder:                    recorder,
+		serviceIdResolver:           serviceIdResolver,
+		restrictToNamespaces:        restrictedNamespaces,
+		enforcementDefaultState:     enforcementDefaultState,
+		enableLinkerdPolicyCreation: linkerdEnforcementEnabled,
+		activeNamespaces:            activeNamespaces,
+	}
+}
+
+// policyKinds are the resources created per client->server pair. Servers and HTTPRoutes are shared between all clients
+// of a server, and are garbage collected once no authorization policy refers to them anymore.
+var policyKinds = []schema.GroupVersionKind{AuthorizationPolicyGVK, MeshTLSAuthenticationGVK}
+
+type objectKey struct {
+	kind string
+	name types.NamespacedName
+}
+
+func keyOf(obj *unstructured.Unstructured) objectKey {
+	return objectKey{kind: obj.GetKind(), name: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+}
+
+func (c *PolicyManagerImpl) DeleteAll(
+	ctx context.Context,
+	clientIntents *v1alpha3.ClientIntents,
+) error {
+	existingObjects, err := c.listClientObjects(ctx, clientIntents)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	err = c.deleteOutdatedObjects(ctx, existingObjects, goset.NewSet[objectKey]())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return c.deleteUnusedServers(ctx, existingObjects)
+}
+
+func (c *PolicyManagerImpl) Create(
+	ctx context.Context,
+	clientIntents *v1alpha3.ClientIntents,
+	clientServiceAccount string,
+) error {
+	existingObjects, err := c.listClientObjects(ctx, clientIntents)
+	if err != nil {
+		c.recorder.RecordWarningEventf(clientIntents, ReasonGettingLinkerdPolicyFailed, "Could not get Linkerd policies: %s", err.Error())
+		return errors.Wrap(err)
+	}
+
+	updatedObjects, err := c.createOrUpdatePolicies(ctx, clientIntents, clientServiceAccount)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	err = c.deleteOutdatedObjects(ctx, existingObjects, updatedObjects)
+	if err != nil {
+		c.recorder.RecordWarningEventf(clientIntents, ReasonDeleteLinkerdPolicyFailed, "Failed to delete Linkerd policy: %s", err.Error())
+		return errors.Wrap(err)
+	}
+
+	return c.deleteUnusedServers(ctx, existingObjects)
+}
+
+// UpdateIntentsStatus records the client's service account on the ClientIntents, the same way the Istio policy manager does.
+func (c *PolicyManagerImpl) UpdateIntentsStatus(
+	ctx context.Context,
+	clientIntents *v1alpha3.ClientIntents,
+	clientServiceAccount string,
+) error {
+	serviceAccountLabelValue, ok := clientIntents.Annotations[v1alpha3.OtterizeClientServiceAccountAnnotation]
+	if ok && serviceAccountLabelValue == clientServiceAccount {
+		return nil
+	}
+
+	updatedIntents := clientIntents.DeepCopy()
+	if updatedIntents.Annotations == nil {
+		updatedIntents.Annotations = make(map[string]string)
+	}
+
+	updatedIntents.Annotations[v1alpha3.OtterizeClientServiceAccountAnnotation] = clientServiceAccount
+	err := c.client.Patch(ctx, updatedIntents, client.MergeFrom(clientIntents))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (c *PolicyManagerImpl) listClientObjects(ctx context.Context, clientIntents *v1alpha3.ClientIntents) ([]*unstructured.Unstructured, error) {
+	clientFormattedIdentity := v1alpha3.GetFormattedOtterizeIdentity(clientIntents.Spec.Service.Name, clientIntents.Namespace)
+
+	objects := make([]*unstructured.Unstructured, 0)
+	for _, gvk := range policyKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := c.client.List(ctx, list, client.MatchingLabels{v1alpha3.OtterizeLinkerdClientLabelKey: clientFormattedIdentity})
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+
+	return objects, nil
+}
+
+func (c *PolicyManagerImpl) createOrUpdatePolicies(
+	ctx context.Context,
+	clientIntents *v1alpha3.ClientIntents,
+	clientServiceAccount string,
+) (*goset.Set[objectKey], error) {
+	updatedObjects := goset.NewSet[objectKey]()
+	reconciledServers := 0
+	for _, intent := range clientIntents.GetCallsList() {
+		if (intent.Type != "" && intent.Type != v1alpha3.IntentTypeHTTP) || intent.IsTargetServerKubernetesService() {
+			continue
+		}
+		serverNamespace := intent.GetTargetServerNamespace(clientIntents.Namespace)
+		shouldCreatePolicy, err := protected_services.IsServerEnforcementEnabledDueToProtectionOrDefaultState(
+			ctx, c.client, intent.GetTargetServerName(), serverNamespace, c.enforcementDefaultState, c.activeNamespaces)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+
+		if !shouldCreatePolicy {
+			logrus.Debugf("Enforcement is disabled globally and server is not explicitly protected, skipping Linkerd policy creation for server %s in namespace %s", intent.GetTargetServerName(), serverNamespace)
+			c.recorder.RecordNormalEventf(clientIntents, consts.ReasonEnforcementDefaultOff, "Enforcement is disabled globally and called service '%s' is not explicitly protected using a ProtectedService resource, Linkerd policy creation skipped", intent.Name)
+			continue
+		}
+
+		if !c.enableLinkerdPolicyCreation {
+			c.recorder.RecordNormalEvent(clientIntents, consts.ReasonLinkerdPolicyCreationDisabled, "Linkerd policy creation is disabled, creation skipped")
+			return updatedObjects, nil
+		}
+
+		if len(c.restrictToNamespaces) != 0 && !lo.Contains(c.restrictToNamespaces, serverNamespace) {
+			c.recorder.RecordWarningEventf(
+				clientIntents,
+				ReasonNamespaceNotAllowed,
+				"Namespace %s was specified in intent, but is not allowed by configuration, Linkerd policy ignored",
+				serverNamespace,
+			)
+			continue
+		}
+
+		ports, err := c.getServerPorts(ctx, intent, serverNamespace)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+		if len(ports) == 0 {
+			c.recorder.RecordWarningEventf(clientIntents, ReasonLinkerdServerPortsNotFound, "Could not determine the ports of server %s in namespace %s, Linkerd policy will be created once its pods declare container ports", intent.GetTargetServerName(), serverNamespace)
+			continue
+		}
+
+		serverObjects := c.generateServers(intent, serverNamespace, ports)
+		for _, obj := range serverObjects {
+			err = c.createOrUpdate(ctx, clientIntents, obj)
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+		}
+
+		for _, obj := range c.generatePolicies(clientIntents, intent, clientServiceAccount, ports) {
+			err = c.createOrUpdate(ctx, clientIntents, obj)
+			if err != nil {
+				return nil, errors.Wrap(err)
+			}
+			updatedObjects.Add(keyOf(obj))
+		}
+		reconciledServers++
+	}
+
+	if reconciledServers != 0 {
+		c.recorder.RecordNormalEventf(clientIntents, ReasonCreatedLinkerdPolicy, "Linkerd policy reconcile complete, reconciled %d servers", reconciledServers)
+	}
+
+	return updatedObjects, nil
+}
+
+// getServerPorts returns the TCP container ports of the server's pods. Linkerd Servers must specify a port, and unlike
+// Istio there is no workload-wide policy to fall back to.
+func (c *PolicyManagerImpl) getServerPorts(ctx context.Context, intent v1alpha3.Intent, serverNamespace string) ([]int32, error) {
+	pod, err := c.serviceIdResolver.ResolveIntentServerToPod(ctx, intent, serverNamespace)
+	if err != nil {
+		if errors.Is(err, serviceidresolver.ErrPodNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err)
+	}
+
+	ports := sets.New[int32]()
+	for _, container := range pod.Spec.Containers {
+		if container.Name == LinkerdProxyContainerName {
+			continue
+		}
+		for _, port := range container.Ports {
+			if port.Protocol == "" || port.Protocol == corev1.ProtocolTCP {
+				ports.Insert(port.ContainerPort)
+			}
+		}
+	}
+
+	return sets.List(ports), nil
+}
+
+func (c *PolicyManagerImpl) createOrUpdate(ctx context.Context, clientIntents *v1alpha3.ClientIntents, newObj *unstructured.Unstructured) error {
+	existingObj := &unstructured.Unstructured{}
+	existingObj.SetGroupVersionKind(newObj.GroupVersionKind())
+	err := c.client.Get(ctx, types.NamespacedName{Namespace: newObj.GetNamespace(), Name: newObj.GetName()}, existingObj)
+	if k8serrors.IsNotFound(err) {
+		err = c.client.Create(ctx, newObj)
+		if err != nil {
+			c.recorder.RecordWarningEventf(clientIntents, ReasonCreatingLinkerdPolicyFailed, "Failed to create Linkerd %s: %s", newObj.GetKind(), err.Error())
+			return errors.Wrap(err)
+		}
+		return nil
+	}
+	if err != nil {
+		c.recorder.RecordWarningEventf(clientIntents, ReasonGettingLinkerdPolicyFailed, "Could not get Linkerd %s: %s", newObj.GetKind(), err.Error())
+		return errors.Wrap(err)
+	}
+
+	if reflect.DeepEqual(existingObj.Object["spec"], newObj.Object["spec"]) && reflect.DeepEqual(existingObj.GetLabels(), newObj.GetLabels()) {
+		return nil
+	}
+
+	objCopy := existingObj.DeepCopy()
+	objCopy.Object["spec"] = newObj.Object["spec"]
+	objCopy.SetLabels(newObj.GetLabels())
+	err = c.client.Patch(ctx, objCopy, client.MergeFrom(existingObj))
+	if err != nil {
+		c.recorder.RecordWarningEventf(clientIntents, ReasonUpdatingLinkerdPolicyFailed, "Failed to update Linkerd %s: %s", newObj.GetKind(), err.Error())
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (c *PolicyManagerImpl) deleteOutdatedObjects(ctx context.Context, existingObjects []*unstructured.Unstructured, validObjects *goset.Set[objectKey]) error {
+	for _, existingObj := range existingObjects {
+		if validObjects.Contains(keyOf(existingObj)) {
+			continue
+		}
+		err := c.client.Delete(ctx, existingObj)
+		if client.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// deleteUnusedServers removes the Otterize-managed Servers (and their catch-all routes) of the servers the client
+// previously had policies for, once no authorization policy of any client refers to them.
+func (c *PolicyManagerImpl) deleteUnusedServers(ctx context.Context, previousObjects []*unstructured.Unstructured) error {
+	checkedServers := goset.NewSet[types.NamespacedName]()
+	for _, obj := range previousObjects {
+		formattedServer := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetLabels()[v1alpha3.OtterizeServiceLabelKey]}
+		if formattedServer.Name == "" || checkedServers.Contains(formattedServer) {
+			continue
+		}
+		checkedServers.Add(formattedServer)
+
+		policies := &unstructured.UnstructuredList{}
+		policies.SetGroupVersionKind(AuthorizationPolicyGVK.GroupVersion().WithKind(AuthorizationPolicyGVK.Kind + "List"))
+		err := c.client.List(ctx, policies,
+			client.InNamespace(formattedServer.Namespace),
+			client.MatchingLabels{v1alpha3.OtterizeServiceLabelKey: formattedServer.Name})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		if len(policies.Items) != 0 {
+			err = c.deleteUnusedRoutes(ctx, formattedServer, policies.Items)
+			if err != nil {
+				return errors.Wrap(err)
+			}
+			continue
+		}
+
+		for _, gvk := range []schema.GroupVersionKind{HTTPRouteGVK, ServerGVK} {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			err = c.client.DeleteAllOf(ctx, obj,
+				client.InNamespace(formattedServer.Namespace),
+				client.MatchingLabels{
+					v1alpha3.OtterizeServiceLabelKey:       formattedServer.Name,
+					v1alpha3.OtterizeLinkerdServerLabelKey: linkerdServerManagedByOtterizeLabelVal,
+				})
+			if client.IgnoreNotFound(err) != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteUnusedRoutes removes the HTTPRoutes of the server's HTTP intents that no authorization policy targets anymore.
+// Catch-all routes are kept as long as the Server exists.
+func (c *PolicyManagerImpl) deleteUnusedRoutes(ctx context.Context, formattedServer types.NamespacedName, policies []unstructured.Unstructured) error {
+	targetedRoutes := goset.NewSet[string]()
+	for _, policy := range policies {
+		targetKind, _, _ := unstructured.NestedString(policy.Object, "spec", "targetRef", "kind")
+		targetName, _, _ := unstructured.NestedString(policy.Object, "spec", "targetRef", "name")
+		if targetKind == HTTPRouteGVK.Kind {
+			targetedRoutes.Add(targetName)
+		}
+	}
+
+	routes := &unstructured.UnstructuredList{}
+	routes.SetGroupVersionKind(HTTPRouteGVK.GroupVersion().WithKind(HTTPRouteGVK.Kind + "List"))
+	err := c.client.List(ctx, routes,
+		client.InNamespace(formattedServer.Namespace),
+		client.MatchingLabels{
+			v1alpha3.OtterizeServiceLabelKey:       formattedServer.Name,
+			v1alpha3.OtterizeLinkerdServerLabelKey: linkerdServerManagedByOtterizeLabelVal,
+		})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if targetedRoutes.Contains(route.GetName()) || isCatchAllRoute(route) {
+			continue
+		}
+		err = c.client.Delete(ctx, route)
+		if client.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// isCatchAllRoute returns whether the route is the catch-all route of a Server, which is named after it.
+func isCatchAllRoute(route *unstructured.Unstructured) bool {
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	return len(parentRefs) == 1 && parentRefs[0].(map[string]any)["name"] == route.GetName()
+}
+
+func (c *PolicyManagerImpl) getServerName(formattedTargetServer string, port int32) string {
+	return fmt.Sprintf(OtterizeLinkerdServerNameTemplate, formattedTargetServer, port)
+}
+
+// generateServers returns a Server per port of the target server, along with a catch-all HTTPRoute for each.
+// Linkerd rejects requests that match none of a Server's routes, so without the catch-all route, creating an HTTPRoute
+// for one client's HTTP intents would break clients that were granted access to the whole server.
+func (c *PolicyManagerImpl) generateServers(intent v1alpha3.Intent, serverNamespace string, ports []int32) []*unstructured.Unstructured {
+	formattedTargetServer := v1alpha3.GetFormattedOtterizeIdentity(intent.GetTargetServerName(), serverNamespace)
+	serverLabels := getServerLabels(formattedTargetServer)
+
+	objects := make([]*unstructured.Unstructured, 0, len(ports)*2)
+	for _, port := range ports {
+		serverName := c.getServerName(formattedTargetServer, port)
+		server := newLinkerdObject(ServerGVK, serverName, serverNamespace, serverLabels, map[string]any{
+			"podSelector": map[string]any{
+				"matchLabels": map[string]any{
+					v1alpha3.OtterizeServiceLabelKey: formattedTargetServer,
+				},
+			},
+			"port": int64(port),
+		})
+
+		catchAllRoute := newLinkerdObject(HTTPRouteGVK, serverName, serverNamespace, serverLabels, map[string]any{
+			"parentRefs": []any{serverParentRef(serverName)},
+			"rules": []any{
+				map[string]any{"matches": []any{catchAllMatch()}},
+			},
+		})
+		objects = append(objects, server, catchAllRoute)
+	}
+
+	return objects
+}
+
+func getServerLabels(formattedTargetServer string) map[string]string {
+	return map[string]string{
+		v1alpha3.OtterizeServiceLabelKey:       formattedTargetServer,
+		v1alpha3.OtterizeLinkerdServerLabelKey: linkerdServerManagedByOtterizeLabelVal,
+	}
+}
+
+func catchAllMatch() map[string]any {
+	return map[string]any{"path": map[string]any{"type": "PathPrefix", "value": "/"}}
+}
+
+// generatePolicies returns the MeshTLSAuthentication identifying the client, and the AuthorizationPolicies granting it
+// access - to the whole Server for plain intents, or to the HTTPRoutes matching its HTTP intents.
+// Linkerd picks a single route for each request, so an HTTPRoute is shared by all clients with the same match, each
+// granted access to it by its own AuthorizationPolicy. Otherwise, clients whose routes were not picked would be denied.
+func (c *PolicyManagerImpl) generatePolicies(
+	clientIntents *v1alpha3.ClientIntents,
+	intent v1alpha3.Intent,
+	clientServiceAccountName string,
+	ports []int32,
+) []*unstructured.Unstructured {
+	serverNamespace := intent.GetTargetServerNamespace(clientIntents.Namespace)
+	formattedTargetServer := v1alpha3.GetFormattedOtterizeIdentity(intent.GetTargetServerName(), serverNamespace)
+	clientFormattedIdentity := v1alpha3.GetFormattedOtterizeIdentity(clientIntents.GetServiceName(), clientIntents.Namespace)
+	clientName := fmt.Sprintf("%s.%s", clientIntents.GetServiceName(), clientIntents.Namespace)
+	policyLabels := map[string]string{
+		v1alpha3.OtterizeServiceLabelKey:       formattedTargetServer,
+		v1alpha3.OtterizeLinkerdClientLabelKey: clientFormattedIdentity,
+	}
+
+	authnName := fmt.Sprintf(OtterizeLinkerdAuthnNameTemplate, intent.GetTargetServerName(), clientName)
+	authn := newLinkerdObject(MeshTLSAuthenticationGVK, authnName, serverNamespace, policyLabels, map[string]any{
+		"identities": []any{fmt.Sprintf(LinkerdMeshTLSIdentityTemplate, clientServiceAccountName, clientIntents.Namespace)},
+	})
+	requiredAuthenticationRefs := []any{
+		map[string]any{"group": LinkerdPolicyGroup, "kind": MeshTLSAuthenticationGVK.Kind, "name": authnName},
+	}
+
+	objects := []*unstructured.Unstructured{authn}
+	if intent.Type == v1alpha3.IntentTypeHTTP {
+		generatedRoutes := goset.NewSet[string]()
+		for _, port := range ports {
+			serverName := c.getServerName(formattedTargetServer, port)
+			for _, match := range c.intentsHTTPResourceToLinkerdMatches(intent.HTTPResources) {
+				// the catch-all route of the Server already matches everything
+				routeName := serverName
+				if !reflect.DeepEqual(match, catchAllMatch()) {
+					routeName = fmt.Sprintf(OtterizeLinkerdRouteNameTemplate, serverName, hashLinkerdMatch(match))
+				}
+				if generatedRoutes.Contains(routeName) {
+					continue
+				}
+				generatedRoutes.Add(routeName)
+
+				if routeName != serverName {
+					objects = append(objects, newLinkerdObject(HTTPRouteGVK, routeName, serverNamespace, getServerLabels(formattedTargetServer), map[string]any{
+						"parentRefs": []any{serverParentRef(serverName)},
+						"rules": []any{
+							map[string]any{"matches": []any{match}},
+						},
+					}))
+				}
+
+				policyName := fmt.Sprintf(OtterizeLinkerdPolicyNameTemplate, routeName, clientName)
+				objects = append(objects, newLinkerdObject(AuthorizationPolicyGVK, policyName, serverNamespace, policyLabels, map[string]any{
+					"targetRef": map[string]any{
+						"group": LinkerdPolicyGroup,
+						"kind":  HTTPRouteGVK.Kind,
+						"name":  routeName,
+					},
+					"requiredAuthenticationRefs": requiredAuthenticationRefs,
+				}))
+			}
+		}
+		return objects
+	}
+
+	for _, port := range ports {
+		policyName := fmt.Sprintf(OtterizeLinkerdPortPolicyNameTemplate, intent.GetTargetServerName(), port, clientName)
+		policy := newLinkerdObject(AuthorizationPolicyGVK, policyName, serverNamespace, policyLabels, map[string]any{
+			"targetRef": map[string]any{
+				"group": LinkerdPolicyGroup,
+				"kind":  ServerGVK.Kind,
+				"name":  c.getServerName(formattedTargetServer, port),
+			},
+			"requiredAuthenticationRefs": requiredAuthenticationRefs,
+		})
+		objects = append(objects, policy)
+	}
+
+	return objects
+}
+
+func (c *PolicyManagerImpl) intentsHTTPResourceToLinkerdMatches(resources []v1alpha3.HTTPResource) []any {
+	matches := make([]any, 0, len(resources))
+	for _, resource := range resources {
+		match := map[string]any{"path": intentsPathToLinkerdPathMatch(resource.Path)}
+		if len(resource.Headers) != 0 {
+			match["headers"] = lo.Map(resource.Headers, func(header v1alpha3.HTTPHeader, _ int) any {
+				return intentsHeaderToLinkerdHeaderMatch(header)
+			})
+		}
+		if len(resource.Methods) == 0 {
+			matches = append(matches, match)
+			continue
+		}
+		for _, method := range resource.Methods {
+			methodMatch := maps.Clone(match)
+			methodMatch["method"] = string(method)
+			matches = append(matches, methodMatch)
+		}
+	}
+
+	return matches
+}
+
+// hashLinkerdMatch returns a short hash identifying the match, used to name the HTTPRoute shared by all clients with it.
+func hashLinkerdMatch(match any) string {
+	// maps are printed with sorted keys, so equal matches hash the same
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v", match)))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+func intentsPathToLinkerdPathMatch(path string) map[string]any {
+	switch {
+	case path == "*":
+		return map[string]any{"type": "PathPrefix", "value": "/"}
+	case strings.HasSuffix(path, "*"):
+		return map[string]any{"type": "PathPrefix", "value": strings.TrimSuffix(path, "*")}
+	case strings.HasPrefix(path, "*"):
+		return map[string]any{"type": "RegularExpression", "value": ".*" + regexp.QuoteMeta(strings.TrimPrefix(path, "*"))}
+	default:
+		return map[string]any{"type": "Exact", "value": path}
+	}
+}
+
+func intentsHeaderToLinkerdHeaderMatch(header v1alpha3.HTTPHeader) map[string]any {
+	if len(header.Values) == 1 {
+		return map[string]any{"type": "Exact", "name": header.Name, "value": header.Values[0]}
+	}
+
+	values := lo.Map(header.Values, func(value string, _ int) string { return regexp.QuoteMeta(value) })
+	return map[string]any{"type": "RegularExpression", "name": header.Name, "value": fmt.Sprintf("^(%s)$", strings.Join(values, "|"))}
+}
+
+func serverParentRef(serverName string) map[string]any {
+	return map[string]any{"group": LinkerdPolicyGroup, "kind": ServerGVK.Kind, "name": serverName}
+}
+
+func newLinkerdObject(gvk schema.GroupVersionKind, name string, namespace string, labels map[string]string, spec map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetLabels(labels)
+	return obj
+}
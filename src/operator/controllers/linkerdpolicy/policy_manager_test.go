@@ -0,0 +1,278 @@
+package linkerdpolicy
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/consts"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	serviceidresolvermocks "github.com/otterize/intents-operator/src/shared/serviceidresolver/mocks"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+type PolicyManagerTestSuite struct {
+	testbase.MocksSuiteBase
+	serviceResolver *serviceidresolvermocks.MockServiceResolver
+	admin           *PolicyManagerImpl
+}
+
+func (s *PolicyManagerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.serviceResolver = serviceidresolvermocks.NewMockServiceResolver(s.Controller)
+	s.admin = NewPolicyManager(s.Client, &injectablerecorder.InjectableRecorder{Recorder: s.Recorder}, s.serviceResolver, []string{}, true, true, nil)
+}
+
+func (s *PolicyManagerTestSuite) TearDownTest() {
+	s.admin = nil
+	s.serviceResolver = nil
+	s.MocksSuiteBase.TearDownTest()
+}
+
+func testIntents(intents ...v1alpha3.Intent) *v1alpha3.ClientIntents {
+	return &v1alpha3.ClientIntents{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "client-intents",
+			Namespace: "test-namespace",
+		},
+		Spec: &v1alpha3.IntentsSpec{
+			Service: v1alpha3.Service{Name: "test-client"},
+			Calls:   intents,
+		},
+	}
+}
+
+func (s *PolicyManagerTestSuite) expectListClientObjects() {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&unstructured.UnstructuredList{}), gomock.Any()).Return(nil).Times(len(policyKinds))
+}
+
+func (s *PolicyManagerTestSuite) TestGeneratePoliciesForServerWideIntent() {
+	intents := testIntents(v1alpha3.Intent{Name: "test-server"})
+
+	objects := s.admin.generatePolicies(intents, intents.Spec.Calls[0], "test-client-sa", []int32{8080, 9090})
+
+	s.Require().Len(objects, 3)
+	authn := objects[0]
+	s.Equal(MeshTLSAuthenticationGVK, authn.GroupVersionKind())
+	s.Equal("meshtls-authn-to-test-server-from-test-client.test-namespace", authn.GetName())
+	identities, _, err := unstructured.NestedStringSlice(authn.Object, "spec", "identities")
+	s.Require().NoError(err)
+	s.Equal([]string{"test-client-sa.test-namespace.serviceaccount.identity.linkerd.cluster.local"}, identities)
+
+	formattedServer := v1alpha3.GetFormattedOtterizeIdentity("test-server", "test-namespace")
+	for i, port := range []int32{8080, 9090} {
+		policy := objects[i+1]
+		s.Equal(AuthorizationPolicyGVK, policy.GroupVersionKind())
+		targetKind, _, _ := unstructured.NestedString(policy.Object, "spec", "targetRef", "kind")
+		targetName, _, _ := unstructured.NestedString(policy.Object, "spec", "targetRef", "name")
+		s.Equal(ServerGVK.Kind, targetKind)
+		s.Equal(s.admin.getServerName(formattedServer, port), targetName)
+		s.Equal(formattedServer, policy.GetLabels()[v1alpha3.OtterizeServiceLabelKey])
+		s.Equal(v1alpha3.GetFormattedOtterizeIdentity("test-client", "test-namespace"), policy.GetLabels()[v1alpha3.OtterizeLinkerdClientLabelKey])
+	}
+}
+
+func routeMatches(route *unstructured.Unstructured) []any {
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	return rules[0].(map[string]any)["matches"].([]any)
+}
+
+func policyTarget(policy *unstructured.Unstructured) (string, string) {
+	targetKind, _, _ := unstructured.NestedString(policy.Object, "spec", "targetRef", "kind")
+	targetName, _, _ := unstructured.NestedString(policy.Object, "spec", "targetRef", "name")
+	return targetKind, targetName
+}
+
+func (s *PolicyManagerTestSuite) TestGeneratePoliciesForHTTPIntent() {
+	intents := testIntents(v1alpha3.Intent{
+		Name: "test-server.other-namespace",
+		Type: v1alpha3.IntentTypeHTTP,
+		HTTPResources: []v1alpha3.HTTPResource{
+			{Path: "/login", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet, v1alpha3.HTTPMethodPost}},
+			{Path: "/health"},
+		},
+	})
+
+	objects := s.admin.generatePolicies(intents, intents.Spec.Calls[0], "test-client-sa", []int32{8080})
+
+	// the MeshTLSAuthentication, and a route and policy per match
+	s.Require().Len(objects, 7)
+	for _, obj := range objects {
+		s.Equal("other-namespace", obj.GetNamespace())
+	}
+
+	formattedServer := v1alpha3.GetFormattedOtterizeIdentity("test-server", "other-namespace")
+	expectedMatches := []any{
+		map[string]any{"path": map[string]any{"type": "Exact", "value": "/login"}, "method": "GET"},
+		map[string]any{"path": map[string]any{"type": "Exact", "value": "/login"}, "method": "POST"},
+		map[string]any{"path": map[string]any{"type": "Exact", "value": "/health"}},
+	}
+	for i, expectedMatch := range expectedMatches {
+		route, policy := objects[1+i*2], objects[2+i*2]
+		s.Equal(HTTPRouteGVK, route.GroupVersionKind())
+		s.Equal([]any{expectedMatch}, routeMatches(route))
+		// routes are shared between clients, so they are labeled as part of the server
+		s.Equal(formattedServer, route.GetLabels()[v1alpha3.OtterizeServiceLabelKey])
+		s.NotContains(route.GetLabels(), v1alpha3.OtterizeLinkerdClientLabelKey)
+
+		s.Equal(AuthorizationPolicyGVK, policy.GroupVersionKind())
+		targetKind, targetName := policyTarget(policy)
+		s.Equal(HTTPRouteGVK.Kind, targetKind)
+		s.Equal(route.GetName(), targetName)
+	}
+}
+
+func (s *PolicyManagerTestSuite) TestGeneratePoliciesForClientsCallingSamePathShareRoute() {
+	call := v1alpha3.Intent{
+		Name:          "test-server",
+		Type:          v1alpha3.IntentTypeHTTP,
+		HTTPResources: []v1alpha3.HTTPResource{{Path: "/login", Methods: []v1alpha3.HTTPMethod{v1alpha3.HTTPMethodGet}}},
+	}
+	intents := testIntents(call)
+	otherIntents := testIntents(call)
+	otherIntents.Spec.Service.Name = "other-client"
+
+	objects := s.admin.generatePolicies(intents, call, "test-client-sa", []int32{8080})
+	otherObjects := s.admin.generatePolicies(otherIntents, call, "other-client-sa", []int32{8080})
+
+	s.Require().Len(objects, 3)
+	s.Require().Len(otherObjects, 3)
+	route, otherRoute := objects[1], otherObjects[1]
+	s.Equal(route.GetName(), otherRoute.GetName())
+	s.Equal(route.Object["spec"], otherRoute.Object["spec"])
+	s.Equal(route.GetLabels(), otherRoute.GetLabels())
+
+	policy, otherPolicy := objects[2], otherObjects[2]
+	s.NotEqual(policy.GetName(), otherPolicy.GetName())
+	_, targetName := policyTarget(policy)
+	_, otherTargetName := policyTarget(otherPolicy)
+	s.Equal(route.GetName(), targetName)
+	s.Equal(route.GetName(), otherTargetName)
+}
+
+func (s *PolicyManagerTestSuite) TestGeneratePoliciesForHTTPIntentToAnyPathUsesCatchAllRoute() {
+	intents := testIntents(v1alpha3.Intent{
+		Name:          "test-server",
+		Type:          v1alpha3.IntentTypeHTTP,
+		HTTPResources: []v1alpha3.HTTPResource{{Path: "*"}},
+	})
+
+	objects := s.admin.generatePolicies(intents, intents.Spec.Calls[0], "test-client-sa", []int32{8080})
+
+	s.Require().Len(objects, 2)
+	_, targetName := policyTarget(objects[1])
+	s.Equal(s.admin.getServerName(v1alpha3.GetFormattedOtterizeIdentity("test-server", "test-namespace"), 8080), targetName)
+}
+
+func (s *PolicyManagerTestSuite) TestGeneratePoliciesForHTTPIntentWithPatternsAndHeaders() {
+	intents := testIntents(v1alpha3.Intent{
+		Name: "test-server.other-namespace",
+		Type: v1alpha3.IntentTypeHTTP,
+		HTTPResources: []v1alpha3.HTTPResource{
+			{Path: "/api/*", Headers: []v1alpha3.HTTPHeader{{Name: "x-tenant-id", Values: []string{"a", "b.c"}}}},
+			{Path: "*.json", Headers: []v1alpha3.HTTPHeader{{Name: "x-tenant-id", Values: []string{"a"}}}},
+		},
+	})
+
+	objects := s.admin.generatePolicies(intents, intents.Spec.Calls[0], "test-client-sa", []int32{8080})
+
+	s.Require().Len(objects, 5)
+	s.Equal([]any{
+		map[string]any{
+			"path":    map[string]any{"type": "PathPrefix", "value": "/api/"},
+			"headers": []any{map[string]any{"type": "RegularExpression", "name": "x-tenant-id", "value": `^(a|b\.c)$`}},
+		},
+	}, routeMatches(objects[1]))
+	s.Equal([]any{
+		map[string]any{
+			"path":    map[string]any{"type": "RegularExpression", "value": `.*\.json`},
+			"headers": []any{map[string]any{"type": "Exact", "name": "x-tenant-id", "value": "a"}},
+		},
+	}, routeMatches(objects[3]))
+}
+
+func (s *PolicyManagerTestSuite) TestDeleteUnusedRoutesKeepsTargetedAndCatchAllRoutes() {
+	formattedServer := v1alpha3.GetFormattedOtterizeIdentity("test-server", "test-namespace")
+	serverName := s.admin.getServerName(formattedServer, 8080)
+	newRoute := func(name string) unstructured.Unstructured {
+		return *newLinkerdObject(HTTPRouteGVK, name, "test-namespace", getServerLabels(formattedServer), map[string]any{
+			"parentRefs": []any{serverParentRef(serverName)},
+		})
+	}
+	targetedRoute, unusedRoute := newRoute(serverName+"-route-targeted"), newRoute(serverName+"-route-unused")
+	policy := newLinkerdObject(AuthorizationPolicyGVK, "policy", "test-namespace", nil, map[string]any{
+		"targetRef": map[string]any{"group": LinkerdPolicyGroup, "kind": HTTPRouteGVK.Kind, "name": targetedRoute.GetName()},
+	})
+
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&unstructured.UnstructuredList{}), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, list *unstructured.UnstructuredList, _ ...any) error {
+			list.Items = []unstructured.Unstructured{newRoute(serverName), targetedRoute, unusedRoute}
+			return nil
+		})
+	s.Client.EXPECT().Delete(gomock.Any(), &unusedRoute).Return(nil)
+
+	err := s.admin.deleteUnusedRoutes(context.Background(), types.NamespacedName{Namespace: "test-namespace", Name: formattedServer}, []unstructured.Unstructured{*policy})
+	s.NoError(err)
+}
+
+func (s *PolicyManagerTestSuite) TestCreateLinkerdEnforcementDisabled() {
+	s.admin.enableLinkerdPolicyCreation = false
+	intents := testIntents(v1alpha3.Intent{Name: "test-server"})
+
+	s.expectListClientObjects()
+
+	err := s.admin.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+	s.ExpectEvent(consts.ReasonLinkerdPolicyCreationDisabled)
+}
+
+func (s *PolicyManagerTestSuite) TestCreateServerWithoutPorts() {
+	intents := testIntents(v1alpha3.Intent{Name: "test-server"})
+
+	s.expectListClientObjects()
+	s.serviceResolver.EXPECT().ResolveIntentServerToPod(gomock.Any(), intents.Spec.Calls[0], "test-namespace").Return(corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "server"}}},
+	}, nil)
+
+	err := s.admin.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+	s.ExpectEvent(ReasonLinkerdServerPortsNotFound)
+}
+
+func (s *PolicyManagerTestSuite) TestCreate() {
+	intents := testIntents(v1alpha3.Intent{Name: "test-server"})
+
+	s.expectListClientObjects()
+	s.serviceResolver.EXPECT().ResolveIntentServerToPod(gomock.Any(), intents.Spec.Calls[0], "test-namespace").Return(corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "server", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}},
+			{Name: LinkerdProxyContainerName, Ports: []corev1.ContainerPort{{ContainerPort: 4143}}},
+		}},
+	}, nil)
+
+	// Server, catch-all route, MeshTLSAuthentication and AuthorizationPolicy
+	createdKinds := make([]string, 0)
+	s.Client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&unstructured.Unstructured{})).Return(
+		k8serrors.NewNotFound(schema.GroupResource{}, "")).Times(4)
+	s.Client.EXPECT().Create(gomock.Any(), gomock.AssignableToTypeOf(&unstructured.Unstructured{})).DoAndReturn(
+		func(_ context.Context, obj *unstructured.Unstructured, _ ...any) error {
+			createdKinds = append(createdKinds, obj.GetKind())
+			return nil
+		}).Times(4)
+
+	err := s.admin.Create(context.Background(), intents, "test-client-sa")
+	s.NoError(err)
+	s.Equal([]string{ServerGVK.Kind, HTTPRouteGVK.Kind, MeshTLSAuthenticationGVK.Kind, AuthorizationPolicyGVK.Kind}, createdKinds)
+	s.ExpectEvent(ReasonCreatedLinkerdPolicy)
+}
+
+func TestPolicyManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyManagerTestSuite))
+}
@@ -0,0 +1,55 @@
+package linkerdpolicy
+
+import (
+	"context"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	LinkerdAuthorizationPolicyCRDName = "authorizationpolicies.policy.linkerd.io"
+	LinkerdProxyContainerName         = "linkerd-proxy"
+	LinkerdPolicyGroup                = "policy.linkerd.io"
+)
+
+// Linkerd does not publish a standalone API module, so its resources are handled as unstructured objects.
+var (
+	ServerGVK                = schema.GroupVersionKind{Group: LinkerdPolicyGroup, Version: "v1beta1", Kind: "Server"}
+	HTTPRouteGVK             = schema.GroupVersionKind{Group: LinkerdPolicyGroup, Version: "v1beta2", Kind: "HTTPRoute"}
+	AuthorizationPolicyGVK   = schema.GroupVersionKind{Group: LinkerdPolicyGroup, Version: "v1alpha1", Kind: "AuthorizationPolicy"}
+	MeshTLSAuthenticationGVK = schema.GroupVersionKind{Group: LinkerdPolicyGroup, Version: "v1alpha1", Kind: "MeshTLSAuthentication"}
+)
+
+// IsPodPartOfLinkerdMesh checks for the linkerd proxy, either as a regular container or as a native sidecar.
+func IsPodPartOfLinkerdMesh(pod corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == LinkerdProxyContainerName {
+			return true
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == LinkerdProxyContainerName {
+			return true
+		}
+	}
+	return false
+}
+
+func IsLinkerdPoliciesInstalled(ctx context.Context, client client.Client) (bool, error) {
+	crd := apiextensionsv1.CustomResourceDefinition{}
+	err := client.Get(ctx, types.NamespacedName{Name: LinkerdAuthorizationPolicyCRDName}, &crd)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return false, errors.Wrap(err)
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -3,7 +3,6 @@ package pod_reconcilers
 import (
 	"context"
 	"fmt"
-	"github.com/amit7itz/goset"
 	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
 	"github.com/otterize/intents-operator/src/operator/controllers/istiopolicy"
 	"github.com/otterize/intents-operator/src/prometheus"
@@ -39,9 +38,9 @@ type PodWatcher struct {
 	injectablerecorder.InjectableRecorder
 }
 
-func NewPodWatcher(c client.Client, eventRecorder record.EventRecorder, watchedNamespaces []string, enforcementDefaultState bool, istioEnforcementEnabled bool, activeNamespaces *goset.Set[string]) *PodWatcher {
+func NewPodWatcher(c client.Client, eventRecorder record.EventRecorder) *PodWatcher {
 	recorder := injectablerecorder.InjectableRecorder{Recorder: eventRecorder}
-	creator := istiopolicy.NewPolicyManager(c, &recorder, watchedNamespaces, enforcementDefaultState, istioEnforcementEnabled, activeNamespaces)
+	creator := istiopolicy.NewPolicyManager(c, &recorder)
 	return &PodWatcher{
 		Client:             c,
 		serviceIdResolver:  serviceidresolver.NewResolver(c),
@@ -133,7 +132,7 @@ func (p *PodWatcher) handleIstioPolicy(ctx context.Context, pod v1.Pod, serviceI
 	}
 
 	for _, clientIntents := range intents.Items {
-		err = p.createIstioPolicies(ctx, clientIntents, pod)
+		err = p.updateIstioIntentsStatus(ctx, clientIntents, pod)
 		if err != nil {
 			return errors.Wrap(err)
 		}
@@ -143,11 +142,15 @@ func (p *PodWatcher) handleIstioPolicy(ctx context.Context, pod v1.Pod, serviceI
 }
 
 func (p *PodWatcher) updateServerSideCar(ctx context.Context, pod v1.Pod, serviceID serviceidentity.ServiceIdentity) error {
-	missingSideCar := !istiopolicy.IsPodPartOfIstioMesh(pod)
+	isPodInMesh, err := istiopolicy.IsPodEnrolledInIstioMesh(ctx, p.Client, pod, p.istioAmbientEnabled())
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	missingSideCar := !isPodInMesh
 
 	serviceFullName := fmt.Sprintf("%s.%s", serviceID.Name, pod.Namespace)
 	var intentsList otterizev1alpha3.ClientIntentsList
-	err := p.List(
+	err = p.List(
 		ctx, &intentsList,
 		&client.MatchingFields{otterizev1alpha3.OtterizeTargetServerIndexField: serviceFullName})
 	if err != nil {
@@ -245,26 +248,25 @@ func (p *PodWatcher) istioEnforcementEnabled() bool {
 	return viper.GetBool(operatorconfig.EnableIstioPolicyKey)
 }
 
-func (p *PodWatcher) createIstioPolicies(ctx context.Context, intents otterizev1alpha3.ClientIntents, pod v1.Pod) error {
+func (p *PodWatcher) istioAmbientEnabled() bool {
+	return viper.GetBool(operatorconfig.EnableIstioAmbientKey)
+}
+
+// updateIstioIntentsStatus saves the client's service account and sidecar status on its ClientIntents. Changes to them
+// trigger a reconcile of the intents, which regenerates the authorization policies of the servers the client calls.
+func (p *PodWatcher) updateIstioIntentsStatus(ctx context.Context, intents otterizev1alpha3.ClientIntents, pod v1.Pod) error {
 	if intents.DeletionTimestamp != nil {
 		return nil
 	}
 
-	missingSideCar := !istiopolicy.IsPodPartOfIstioMesh(pod)
-
-	err := p.istioPolicyAdmin.UpdateIntentsStatus(ctx, &intents, pod.Spec.ServiceAccountName, missingSideCar)
+	isPodInMesh, err := istiopolicy.IsPodEnrolledInIstioMesh(ctx, p.Client, pod, p.istioAmbientEnabled())
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	missingSideCar := !isPodInMesh
 
-	if missingSideCar {
-		logrus.Debugf("Pod %s/%s does not have a sidecar, skipping Istio policy creation", pod.Namespace, pod.Name)
-		return nil
-	}
-
-	err = p.istioPolicyAdmin.Create(ctx, &intents, pod.Spec.ServiceAccountName)
+	err = p.istioPolicyAdmin.UpdateIntentsStatus(ctx, &intents, pod.Spec.ServiceAccountName, missingSideCar)
 	if err != nil {
-		logrus.WithError(err).Errorln("Failed creating Istio authorization policy")
 		return errors.Wrap(err)
 	}
 
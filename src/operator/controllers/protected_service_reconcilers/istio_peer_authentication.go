@@ -0,0 +1,133 @@
+package protected_service_reconcilers
+
+import (
+	"context"
+	"fmt"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/operator/controllers/istiopolicy"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/otterize/intents-operator/src/shared/injectablerecorder"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"slices"
+	"strings"
+)
+
+const (
+	ReasonProtectedServiceClientsMissingSidecar = "ProtectedServiceClientsMissingSidecar"
+)
+
+// IstioPeerAuthenticationReconciler enforces STRICT Istio mTLS on protected services, and reports the clients that
+// would be rejected because they are not part of the mesh.
+type IstioPeerAuthenticationReconciler struct {
+	client.Client
+	injectablerecorder.InjectableRecorder
+	peerAuthenticationManager *istiopolicy.PeerAuthenticationManager
+}
+
+func NewIstioPeerAuthenticationReconciler(client client.Client) *IstioPeerAuthenticationReconciler {
+	return &IstioPeerAuthenticationReconciler{
+		Client:                    client,
+		peerAuthenticationManager: istiopolicy.NewPeerAuthenticationManager(client),
+	}
+}
+
+func (r *IstioPeerAuthenticationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	isInstalled, err := istiopolicy.IsIstioPeerAuthenticationInstalled(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	if !isInstalled {
+		logrus.Debug("Peer authentication CRD is not installed, Istio strict mTLS skipped")
+		return ctrl.Result{}, nil
+	}
+
+	var protectedServices otterizev1alpha3.ProtectedServiceList
+	err = r.List(ctx, &protectedServices, client.InNamespace(req.Namespace))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	activeProtectedServices := make([]otterizev1alpha3.ProtectedService, 0)
+	for _, protectedService := range protectedServices.Items {
+		if protectedService.DeletionTimestamp == nil {
+			activeProtectedServices = append(activeProtectedServices, protectedService)
+		}
+	}
+
+	serviceNames := sets.New[string]()
+	for _, protectedService := range activeProtectedServices {
+		serviceNames.Insert(protectedService.Spec.Name)
+	}
+
+	err = r.peerAuthenticationManager.ReconcileNamespace(ctx, req.Namespace, sets.List(serviceNames))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err)
+	}
+
+	for _, protectedService := range activeProtectedServices {
+		err = r.updateStatus(ctx, protectedService)
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, errors.Wrap(err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *IstioPeerAuthenticationReconciler) updateStatus(ctx context.Context, protectedService otterizev1alpha3.ProtectedService) error {
+	clientsMissingSidecar, err := r.getClientsMissingSidecar(ctx, protectedService)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	// the list is compared with slices.Equal, as an empty list is stored as nil
+	if protectedService.Status.IstioStrictMTLS && slices.Equal(protectedService.Status.ClientsMissingSidecar, clientsMissingSidecar) {
+		return nil
+	}
+
+	// every ProtectedService in the namespace is updated on each reconcile, so the warning is only recorded when the
+	// clients missing a sidecar change
+	if len(clientsMissingSidecar) != 0 && !slices.Equal(protectedService.Status.ClientsMissingSidecar, clientsMissingSidecar) {
+		r.RecordWarningEventf(&protectedService, ReasonProtectedServiceClientsMissingSidecar,
+			"Strict mTLS is enforced for %s, but these clients are missing an Istio sidecar and will be blocked: %s",
+			protectedService.Spec.Name, strings.Join(clientsMissingSidecar, ", "))
+	}
+
+	updatedProtectedService := protectedService.DeepCopy()
+	updatedProtectedService.Status = otterizev1alpha3.ProtectedServiceStatus{
+		IstioStrictMTLS:       true,
+		ClientsMissingSidecar: clientsMissingSidecar,
+	}
+	err = r.Status().Patch(ctx, updatedProtectedService, client.MergeFrom(&protectedService))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+// getClientsMissingSidecar returns the clients calling the protected service that the Istio policy reconciler has
+// marked as missing a sidecar.
+func (r *IstioPeerAuthenticationReconciler) getClientsMissingSidecar(ctx context.Context, protectedService otterizev1alpha3.ProtectedService) ([]string, error) {
+	formattedServerName := otterizev1alpha3.GetFormattedOtterizeIdentity(protectedService.Spec.Name, protectedService.Namespace)
+
+	var clientIntents otterizev1alpha3.ClientIntentsList
+	err := r.List(ctx, &clientIntents, &client.MatchingFields{otterizev1alpha3.OtterizeFormattedTargetServerIndexField: formattedServerName})
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
+	clients := sets.New[string]()
+	for _, intents := range clientIntents.Items {
+		if intents.Annotations[otterizev1alpha3.OtterizeMissingSidecarAnnotation] != "true" {
+			continue
+		}
+		clients.Insert(fmt.Sprintf("%s.%s", intents.GetServiceName(), intents.Namespace))
+	}
+
+	return sets.List(clients), nil
+}
@@ -0,0 +1,89 @@
+package protected_service_reconcilers
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+// protectedServiceStatusRecorder records the status patches of ProtectedServices.
+type protectedServiceStatusRecorder struct {
+	client.SubResourceWriter
+	patched []*otterizev1alpha3.ProtectedService
+}
+
+func (r *protectedServiceStatusRecorder) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	r.patched = append(r.patched, obj.(*otterizev1alpha3.ProtectedService))
+	return nil
+}
+
+type IstioPeerAuthenticationReconcilerTestSuite struct {
+	testbase.MocksSuiteBase
+	reconciler   *IstioPeerAuthenticationReconciler
+	statusWriter *protectedServiceStatusRecorder
+}
+
+func (s *IstioPeerAuthenticationReconcilerTestSuite) SetupTest() {
+	s.MocksSuiteBase.SetupTest()
+	s.statusWriter = &protectedServiceStatusRecorder{}
+	s.reconciler = NewIstioPeerAuthenticationReconciler(s.Client)
+	s.reconciler.InjectRecorder(s.Recorder)
+}
+
+func (s *IstioPeerAuthenticationReconcilerTestSuite) protectedService(status otterizev1alpha3.ProtectedServiceStatus) otterizev1alpha3.ProtectedService {
+	return otterizev1alpha3.ProtectedService{
+		ObjectMeta: metav1.ObjectMeta{Name: protectedServicesResourceName, Namespace: testNamespace},
+		Spec:       otterizev1alpha3.ProtectedServiceSpec{Name: protectedServiceName},
+		Status:     status,
+	}
+}
+
+func (s *IstioPeerAuthenticationReconcilerTestSuite) expectClientIntents(clientsMissingSidecar ...string) {
+	s.Client.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&otterizev1alpha3.ClientIntentsList{}), gomock.Any()).DoAndReturn(
+		func(_ context.Context, list *otterizev1alpha3.ClientIntentsList, _ ...client.ListOption) error {
+			for _, clientName := range clientsMissingSidecar {
+				list.Items = append(list.Items, otterizev1alpha3.ClientIntents{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        clientName,
+						Namespace:   testNamespace,
+						Annotations: map[string]string{otterizev1alpha3.OtterizeMissingSidecarAnnotation: "true"},
+					},
+					Spec: &otterizev1alpha3.IntentsSpec{Service: otterizev1alpha3.Service{Name: clientName}},
+				})
+			}
+			return nil
+		})
+}
+
+func (s *IstioPeerAuthenticationReconcilerTestSuite) TestStatusWithoutClientsMissingSidecarNotPatchedAgain() {
+	s.expectClientIntents()
+
+	err := s.reconciler.updateStatus(context.Background(), s.protectedService(otterizev1alpha3.ProtectedServiceStatus{IstioStrictMTLS: true}))
+	s.Require().NoError(err)
+	s.Empty(s.statusWriter.patched)
+}
+
+func (s *IstioPeerAuthenticationReconcilerTestSuite) TestClientsMissingSidecarRecordedOnce() {
+	s.expectClientIntents("client")
+	s.Client.EXPECT().Status().Return(s.statusWriter)
+
+	err := s.reconciler.updateStatus(context.Background(), s.protectedService(otterizev1alpha3.ProtectedServiceStatus{IstioStrictMTLS: true}))
+	s.Require().NoError(err)
+	s.ExpectEvent(ReasonProtectedServiceClientsMissingSidecar)
+	s.Require().Len(s.statusWriter.patched, 1)
+	s.Equal([]string{"client." + testNamespace}, s.statusWriter.patched[0].Status.ClientsMissingSidecar)
+
+	s.expectClientIntents("client")
+	err = s.reconciler.updateStatus(context.Background(), *s.statusWriter.patched[0])
+	s.Require().NoError(err)
+	s.Len(s.statusWriter.patched, 1)
+}
+
+func TestIstioPeerAuthenticationReconcilerTestSuite(t *testing.T) {
+	suite.Run(t, new(IstioPeerAuthenticationReconcilerTestSuite))
+}
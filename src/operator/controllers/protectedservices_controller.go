@@ -58,6 +58,7 @@ func NewProtectedServiceReconciler(
 	extNetpolHandler protected_service_reconcilers.ExternalNepolHandler,
 	enforcementDefaultState bool,
 	netpolEnforcementEnabled bool,
+	istioStrictMTLSEnabled bool,
 	effectivePolicySyncer protected_service_reconcilers.EffectivePolicyReconcilerGroup,
 ) *ProtectedServiceReconciler {
 	group := reconcilergroup.NewGroup(
@@ -74,6 +75,11 @@ func NewProtectedServiceReconciler(
 		group.AddToGroup(defaultDenyReconciler)
 	}
 
+	if istioStrictMTLSEnabled {
+		peerAuthenticationReconciler := protected_service_reconcilers.NewIstioPeerAuthenticationReconciler(client)
+		group.AddToGroup(peerAuthenticationReconciler)
+	}
+
 	if !enforcementDefaultState || !netpolEnforcementEnabled {
 		policyCleaner := protected_service_reconcilers.NewPolicyCleanerReconciler(client, effectivePolicySyncer)
 		group.AddToGroup(policyCleaner)
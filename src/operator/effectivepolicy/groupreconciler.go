@@ -150,7 +150,7 @@ func (g *GroupReconciler) filterAndTransformClientIntentsIntoClientCalls(clientI
 			continue
 		}
 		objEventRecorder := injectablerecorder.NewObjectEventRecorder(&g.InjectableRecorder, lo.ToPtr(clientIntent))
-		clientCalls = append(clientCalls, ClientCall{Service: clientService, IntendedCall: intendedCall, ObjectEventRecorder: objEventRecorder})
+		clientCalls = append(clientCalls, ClientCall{Service: clientService, IntendedCall: intendedCall, ClientIntents: lo.ToPtr(clientIntent), ObjectEventRecorder: objEventRecorder})
 	}
 	return clientCalls
 }
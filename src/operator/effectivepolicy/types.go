@@ -10,6 +10,7 @@ import (
 type ClientCall struct {
 	Service             serviceidentity.ServiceIdentity
 	IntendedCall        v1alpha3.Intent
+	ClientIntents       *v1alpha3.ClientIntents
 	ObjectEventRecorder *injectablerecorder.ObjectEventRecorder
 }
 
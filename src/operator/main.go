@@ -35,6 +35,7 @@ import (
 	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/networkpolicy"
 	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/networkpolicy/builders"
 	"github.com/otterize/intents-operator/src/operator/controllers/intents_reconcilers/port_network_policy"
+	"github.com/otterize/intents-operator/src/operator/controllers/istiopolicy"
 	"github.com/otterize/intents-operator/src/operator/controllers/kafkaacls"
 	"github.com/otterize/intents-operator/src/operator/controllers/pod_reconcilers"
 	"github.com/otterize/intents-operator/src/operator/effectivepolicy"
@@ -61,6 +62,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/metadata"
 	"path"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -68,6 +70,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -75,7 +78,11 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	istionetworkingv1alpha3scheme "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istionetworkingscheme "istio.io/client-go/pkg/apis/networking/v1beta1"
 	istiosecurityscheme "istio.io/client-go/pkg/apis/security/v1beta1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	gcpiamv1 "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/clients/generated/apis/iam/v1beta1"
 	gcpk8sv1 "github.com/GoogleCloudPlatform/k8s-config-connector/pkg/clients/generated/apis/k8s/v1alpha1"
@@ -95,6 +102,10 @@ func init() {
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(istiosecurityscheme.AddToScheme(scheme))
+	utilruntime.Must(istionetworkingscheme.AddToScheme(scheme))
+	utilruntime.Must(istionetworkingv1alpha3scheme.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
 	utilruntime.Must(otterizev1alpha2.AddToScheme(scheme))
 	utilruntime.Must(otterizev1alpha3.AddToScheme(scheme))
 
@@ -114,6 +125,24 @@ func MustGetEnvVar(name string) string {
 	return value
 }
 
+// importKafkaACLs runs the operator binary as a one-shot command, which writes the ACLs of a Kafka server as ClientIntents.
+func importKafkaACLs(kafkaServerConfigName string, defaultTls otterizev1alpha3.TLSSource) {
+	namespace, name, ok := strings.Cut(kafkaServerConfigName, "/")
+	if !ok {
+		logrus.Fatalf("%s must be formatted as namespace/name", operatorconfig.ImportKafkaACLsFromKey)
+	}
+
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		logrus.WithError(err).Fatal("unable to create kubernetes API client")
+	}
+
+	err = kafkaacls.RunACLImport(context.Background(), k8sClient, types.NamespacedName{Namespace: namespace, Name: name}, defaultTls, viper.GetString(operatorconfig.ImportKafkaACLsOutputDirKey))
+	if err != nil {
+		logrus.WithError(err).Fatal("failed importing Kafka ACLs")
+	}
+}
+
 func main() {
 	operatorconfig.InitCLIFlags()
 	logrus.SetFormatter(&logrus.JSONFormatter{
@@ -136,6 +165,12 @@ func main() {
 		EnableNetworkPolicy:                  viper.GetBool(operatorconfig.EnableNetworkPolicyKey),
 		EnableKafkaACL:                       viper.GetBool(operatorconfig.EnableKafkaACLKey),
 		EnableIstioPolicy:                    viper.GetBool(operatorconfig.EnableIstioPolicyKey),
+		EnableIstioStrictMTLS:                viper.GetBool(operatorconfig.EnableIstioStrictMTLSKey),
+		EnableIstioSidecarEgress:             viper.GetBool(operatorconfig.EnableIstioSidecarEgressKey),
+		EnableIstioAmbient:                   viper.GetBool(operatorconfig.EnableIstioAmbientKey),
+		IstioEgressGateway:                   viper.GetString(operatorconfig.IstioEgressGatewayKey),
+		EnableIstioEgressGatewayPolicy:       viper.GetBool(operatorconfig.EnableIstioEgressGatewayPolicyKey),
+		EnableLinkerdPolicy:                  viper.GetBool(operatorconfig.EnableLinkerdPolicyKey),
 		EnableDatabasePolicy:                 viper.GetBool(operatorconfig.EnableDatabasePolicy),
 		EnableEgressNetworkPolicyReconcilers: viper.GetBool(operatorconfig.EnableEgressNetworkPolicyReconcilersKey),
 		EnableAWSPolicy:                      viper.GetBool(operatorconfig.EnableAWSPolicyKey),
@@ -150,6 +185,11 @@ func main() {
 		RootCAFile: viper.GetString(operatorconfig.KafkaServerTLSCAKey),
 	}
 
+	if kafkaServerConfigName := viper.GetString(operatorconfig.ImportKafkaACLsFromKey); kafkaServerConfigName != "" {
+		importKafkaACLs(kafkaServerConfigName, tlsSource)
+		return
+	}
+
 	podName := MustGetEnvVar(operatorconfig.IntentsOperatorPodNameKey)
 	podNamespace := MustGetEnvVar(operatorconfig.IntentsOperatorPodNamespaceKey)
 	ctrl.SetLogger(logrusr.New(logrus.StandardLogger()))
@@ -216,9 +256,19 @@ func main() {
 		logrus.WithError(err).Panic("unable to create kubernetes API client")
 	}
 
-	kafkaServersStore := kafkaacls.NewServersStore(tlsSource, enforcementConfig.EnableKafkaACL, kafkaacls.NewKafkaIntentsAdmin, enforcementConfig.EnforcementDefaultState)
+	kafkaAdminIdleTimeout := viper.GetDuration(operatorconfig.KafkaAdminIdleTimeoutKey)
+	if kafkaAdminIdleTimeout <= 0 {
+		logrus.Panicf("%s must be a positive duration, got %s", operatorconfig.KafkaAdminIdleTimeoutKey, kafkaAdminIdleTimeout)
+	}
+	kafkaClusterAdminPool := kafkaacls.NewClusterAdminPool(kafkaAdminIdleTimeout, viper.GetInt(operatorconfig.KafkaAdminMaxConcurrentRequestsKey))
+	if err := mgr.Add(kafkaClusterAdminPool); err != nil {
+		logrus.WithError(err).Panic("unable to add Kafka admin client pool to manager")
+	}
+	kafkaIntentsAdminFactory := kafkaacls.NewKafkaIntentsAdminFactory(mgr.GetClient(), kafkaClusterAdminPool)
+	kafkaServersStore := kafkaacls.NewServersStore(tlsSource, enforcementConfig.EnableKafkaACL, kafkaIntentsAdminFactory, enforcementConfig.EnforcementDefaultState)
 
-	extNetpolHandler := external_traffic.NewNetworkPolicyHandler(mgr.GetClient(), mgr.GetScheme(), allowExternalTraffic)
+	gatewayRoutes := external_traffic.NewGatewayRoutes(mgr.GetClient())
+	extNetpolHandler := external_traffic.NewNetworkPolicyHandler(mgr.GetClient(), mgr.GetScheme(), allowExternalTraffic, gatewayRoutes)
 	endpointReconciler := external_traffic.NewEndpointsReconciler(mgr.GetClient(), extNetpolHandler)
 	ingressRulesBuilder := builders.NewIngressNetpolBuilder()
 
@@ -227,7 +277,8 @@ func main() {
 	dnsServerNetpolBuilder := builders.NewIngressDNSServerAutoAllowNetpolBuilder()
 	epNetpolReconciler := networkpolicy.NewReconciler(mgr.GetClient(), scheme, extNetpolHandler, watchedNamespaces, enforcementConfig.EnforcedNamespaces, enforcementConfig.EnableNetworkPolicy, enforcementConfig.EnforcementDefaultState,
 		[]networkpolicy.IngressRuleBuilder{ingressRulesBuilder, svcNetworkPolicyBuilder, dnsServerNetpolBuilder}, make([]networkpolicy.EgressRuleBuilder, 0))
-	epGroupReconciler := effectivepolicy.NewGroupReconciler(mgr.GetClient(), scheme, epNetpolReconciler)
+	epIstioReconciler := istiopolicy.NewEffectivePolicyReconciler(mgr.GetClient(), watchedNamespaces, enforcementConfig.EnforcedNamespaces, enforcementConfig.EnableIstioPolicy, enforcementConfig.EnforcementDefaultState, enforcementConfig.EnableIstioAmbient)
+	epGroupReconciler := effectivepolicy.NewGroupReconciler(mgr.GetClient(), scheme, epNetpolReconciler, epIstioReconciler)
 	if enforcementConfig.EnableEgressNetworkPolicyReconcilers {
 		egressNetworkPolicyHandler := builders.NewEgressNetworkPolicyBuilder()
 		epNetpolReconciler.AddEgressRuleBuilder(egressNetworkPolicyHandler)
@@ -235,6 +286,8 @@ func main() {
 		epNetpolReconciler.AddEgressRuleBuilder(internetNetpolReconciler)
 		svcEgressNetworkPolicyHandler := builders.NewPortEgressRulesBuilder(mgr.GetClient())
 		epNetpolReconciler.AddEgressRuleBuilder(svcEgressNetworkPolicyHandler)
+		externalKafkaNetpolReconciler := builders.NewExternalKafkaEgressRulesBuilder(mgr.GetClient())
+		epNetpolReconciler.AddEgressRuleBuilder(externalKafkaNetpolReconciler)
 
 	}
 	epIntentsReconciler := intents_reconcilers.NewServiceEffectiveIntentsReconciler(mgr.GetClient(), scheme, epGroupReconciler)
@@ -245,7 +298,11 @@ func main() {
 	serviceIdResolver := serviceidresolver.NewResolver(mgr.GetClient())
 
 	if enforcementConfig.EnableAWSPolicy {
-		awsOptions := make([]awsagent.Option, 0)
+		awsOptions := []awsagent.Option{
+			awsagent.WithPermissionBoundary(viper.GetString(operatorconfig.AWSRolePermissionBoundaryARNKey)),
+			awsagent.WithRolePath(viper.GetString(operatorconfig.AWSRolePathKey)),
+			awsagent.WithCustomTags(viper.GetStringMapString(operatorconfig.AWSResourceTagsKey)),
+		}
 		if viper.GetBool(operatorconfig.EnableAWSRolesAnywhereKey) {
 			keyPath := path.Join(viper.GetString(operatorconfig.AWSRolesAnywhereCertDirKey), viper.GetString(operatorconfig.AWSRolesAnywherePrivKeyFilenameKey))
 			certPath := path.Join(viper.GetString(operatorconfig.AWSRolesAnywhereCertDirKey), viper.GetString(operatorconfig.AWSRolesAnywhereCertFilenameKey))
@@ -266,13 +323,16 @@ func main() {
 			//
 			//	iamAgents = append(iamAgents, awsIntentsAgent)
 			//} else {
-			awsIntentsAgent, err := awspolicyagent.NewMultiaccountAWSPolicyAgent(signalHandlerCtx, accounts, clusterName, keyPath, certPath)
+			awsIntentsAgent, err := awspolicyagent.NewMultiaccountAWSPolicyAgent(signalHandlerCtx, accounts, awsOptions, clusterName, keyPath, certPath)
 			if err != nil {
 				logrus.WithError(err).Panic("Could not initialize AWS agent")
 			}
 			iamAgents = append(iamAgents, awsIntentsAgent)
 			//}
 		} else {
+			if viper.GetBool(operatorconfig.EnableAWSPodIdentityKey) {
+				awsOptions = append(awsOptions, awsagent.WithPodIdentity())
+			}
 			awsAgent, err := awsagent.NewAWSAgent(signalHandlerCtx, awsOptions...)
 			if err != nil {
 				logrus.WithError(err).Panic("Could not initialize AWS agent")
@@ -324,6 +384,10 @@ func main() {
 		logrus.WithError(err).Panic("unable to init index for ingress")
 	}
 
+	if err = gatewayRoutes.InitGatewayRouteReferencedServicesIndices(mgr); err != nil {
+		logrus.WithError(err).Panic("unable to init indices for Gateway API routes")
+	}
+
 	otterizeCloudClient, connectedToCloud, err := operator_cloud_client.NewClient(signalHandlerCtx)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to initialize Otterize Cloud client")
@@ -342,6 +406,7 @@ func main() {
 
 	externalPolicySvcReconciler := external_traffic.NewServiceReconciler(mgr.GetClient(), extNetpolHandler, otterizeCloudClient)
 	ingressReconciler := external_traffic.NewIngressReconciler(mgr.GetClient(), extNetpolHandler, otterizeCloudClient)
+	gatewayRouteReconciler := external_traffic.NewGatewayRouteReconciler(mgr.GetClient(), extNetpolHandler, otterizeCloudClient)
 
 	if !enforcementConfig.EnforcementDefaultState {
 		logrus.Infof("Running with enforcement disabled globally, won't perform any enforcement")
@@ -411,7 +476,8 @@ func main() {
 			logrus.WithError(err).Panic("unable to create webhook v1alpha2", "webhook", "KafkaServerConfig")
 		}
 
-		if err = (&otterizev1alpha3.KafkaServerConfig{}).SetupWebhookWithManager(mgr); err != nil {
+		kafkaServerConfigValidatorV1alpha3 := webhooks.NewKafkaServerConfigValidatorV1alpha3(mgr.GetClient())
+		if err = (&otterizev1alpha3.KafkaServerConfig{}).SetupWebhookWithManager(mgr, kafkaServerConfigValidatorV1alpha3); err != nil {
 			logrus.WithError(err).Panic("unable to create webhook v1alpha3", "webhook", "KafkaServerConfig")
 		}
 
@@ -421,6 +487,7 @@ func main() {
 		mgr.GetClient(),
 		mgr.GetScheme(),
 		kafkaServersStore,
+		kafkaIntentsAdminFactory,
 		watchedNamespaces,
 		enforcementConfig,
 		otterizeCloudClient,
@@ -456,6 +523,10 @@ func main() {
 		logrus.WithError(err).Panic("unable to create controller", "controller", "Ingress")
 	}
 
+	if err = gatewayRouteReconciler.SetupWithManager(mgr); err != nil {
+		logrus.WithError(err).Panic("unable to create controller", "controller", "GatewayRoute")
+	}
+
 	kafkaServerConfigReconciler := controllers.NewKafkaServerConfigReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
@@ -474,6 +545,25 @@ func main() {
 		logrus.WithError(err).Panic("unable to init indices for KafkaServerConfig")
 	}
 
+	if kafkaACLResyncInterval := viper.GetDuration(operatorconfig.KafkaACLResyncIntervalKey); enforcementConfig.EnableKafkaACL && kafkaACLResyncInterval > 0 {
+		kafkaACLReconciler := intents_reconcilers.NewKafkaACLReconciler(
+			mgr.GetClient(),
+			mgr.GetScheme(),
+			kafkaServersStore,
+			enforcementConfig.EnableKafkaACL,
+			kafkaIntentsAdminFactory,
+			enforcementConfig.EnforcementDefaultState,
+			podName,
+			podNamespace,
+			serviceidresolver.NewResolver(mgr.GetClient()),
+			enforcementConfig.EnforcedNamespaces,
+		)
+		kafkaACLResyncer := intents_reconcilers.NewKafkaACLResyncer(kafkaACLReconciler, kafkaACLResyncInterval, viper.GetBool(operatorconfig.KafkaACLDriftRepairKey))
+		if err = mgr.Add(kafkaACLResyncer); err != nil {
+			logrus.WithError(err).Panic("unable to add Kafka ACL resync to manager")
+		}
+	}
+
 	protectedServicesReconciler := controllers.NewProtectedServiceReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
@@ -481,6 +571,7 @@ func main() {
 		extNetpolHandler,
 		enforcementConfig.EnforcementDefaultState,
 		enforcementConfig.EnableNetworkPolicy,
+		enforcementConfig.EnableIstioPolicy && enforcementConfig.EnableIstioStrictMTLS,
 		epGroupReconciler,
 	)
 
@@ -489,7 +580,7 @@ func main() {
 		logrus.WithError(err).Panic("unable to create controller", "controller", "ProtectedServices")
 	}
 
-	podWatcher := pod_reconcilers.NewPodWatcher(mgr.GetClient(), mgr.GetEventRecorderFor("intents-operator"), watchedNamespaces, enforcementConfig.EnforcementDefaultState, enforcementConfig.EnableIstioPolicy, enforcementConfig.EnforcedNamespaces)
+	podWatcher := pod_reconcilers.NewPodWatcher(mgr.GetClient(), mgr.GetEventRecorderFor("intents-operator"))
 	nsWatcher := pod_reconcilers.NewNamespaceWatcher(mgr.GetClient())
 	svcWatcher := port_network_policy.NewServiceWatcher(mgr.GetClient(), mgr.GetEventRecorderFor("intents-operator"), epGroupReconciler)
 
@@ -25,8 +25,10 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"net/netip"
+	"net/url"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -150,6 +152,56 @@ func (v *IntentsValidatorV1alpha3) validateSpec(intents *otterizev1alpha3.Client
 					Detail: fmt.Sprintf("invalid intent format. type %s cannot contain kafka topics", otterizev1alpha3.IntentTypeHTTP),
 				}
 			}
+			if intent.ConsumerGroups != nil || intent.TransactionalIDs != nil || intent.ClusterOperations != nil {
+				return &field.Error{
+					Type:   field.ErrorTypeForbidden,
+					Field:  "kafkaConsumerGroups",
+					Detail: fmt.Sprintf("invalid intent format. type %s cannot contain kafka consumer groups, transactional IDs or cluster operations", otterizev1alpha3.IntentTypeHTTP),
+				}
+			}
+			for _, resource := range intent.HTTPResources {
+				if err := validateHTTPResource(resource); err != nil {
+					return err
+				}
+			}
+			if intent.RateLimit != nil && intent.RateLimit.Requests <= 0 {
+				return &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "rateLimit.requests",
+					Detail:   "rate limit must allow at least one request",
+					BadValue: intent.RateLimit.Requests,
+				}
+			}
+		} else if intent.RateLimit != nil {
+			return &field.Error{
+				Type:   field.ErrorTypeForbidden,
+				Field:  "rateLimit",
+				Detail: fmt.Sprintf("invalid intent format. only intents of type %s can be rate limited", otterizev1alpha3.IntentTypeHTTP),
+			}
+		}
+		if intent.Quota != nil && intent.Type != otterizev1alpha3.IntentTypeKafka {
+			return &field.Error{
+				Type:   field.ErrorTypeForbidden,
+				Field:  "kafkaQuota",
+				Detail: fmt.Sprintf("invalid intent format. only intents of type %s can have kafka quotas", otterizev1alpha3.IntentTypeKafka),
+			}
+		}
+		if len(intent.AWSManagedPolicies) != 0 && intent.Type != otterizev1alpha3.IntentTypeAWS {
+			return &field.Error{
+				Type:   field.ErrorTypeForbidden,
+				Field:  "awsManagedPolicies",
+				Detail: fmt.Sprintf("invalid intent format. only intents of type %s can attach managed policies", otterizev1alpha3.IntentTypeAWS),
+			}
+		}
+		for _, policyARN := range intent.AWSManagedPolicies {
+			if !strings.HasPrefix(policyARN, "arn:") || !strings.Contains(policyARN, ":policy/") {
+				return &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "awsManagedPolicies",
+					Detail:   "should be the ARN of an IAM policy",
+					BadValue: policyARN,
+				}
+			}
 		}
 		if intent.Type == otterizev1alpha3.IntentTypeInternet { // every ips should be valid ip
 			if intent.Internet == nil {
@@ -204,6 +256,23 @@ func (v *IntentsValidatorV1alpha3) validateSpec(intents *otterizev1alpha3.Client
 				}
 			}
 		}
+		if strings.HasPrefix(intent.Name, otterizev1alpha3.ExternalKafkaServerPrefix) {
+			if intent.Type != otterizev1alpha3.IntentTypeKafka {
+				return &field.Error{
+					Type:   field.ErrorTypeForbidden,
+					Field:  "name",
+					Detail: fmt.Sprintf("invalid intent format. only intents of type %s can target external servers", otterizev1alpha3.IntentTypeKafka),
+				}
+			}
+			if strings.Contains(intent.Name, ".") {
+				return &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "name",
+					Detail:   "external Kafka server aliases are cluster-wide and cannot specify a namespace",
+					BadValue: intent.Name,
+				}
+			}
+		}
 		if strings.Count(intent.Name, ".") > 1 {
 			return &field.Error{
 				Type:   field.ErrorTypeForbidden,
@@ -214,3 +283,103 @@ func (v *IntentsValidatorV1alpha3) validateSpec(intents *otterizev1alpha3.Client
 	}
 	return nil
 }
+
+func validateHTTPResource(resource otterizev1alpha3.HTTPResource) *field.Error {
+	if !isValidHTTPMatchPattern(resource.Path) {
+		return &field.Error{
+			Type:     field.ErrorTypeInvalid,
+			Field:    "path",
+			Detail:   "'*' is only allowed as the whole path or at its start or end",
+			BadValue: resource.Path,
+		}
+	}
+
+	for _, host := range resource.Hosts {
+		if host == "" || !isValidHTTPMatchPattern(host) {
+			return &field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "hosts",
+				Detail:   "should be a host name, '*' is only allowed at its start or end",
+				BadValue: host,
+			}
+		}
+	}
+
+	if resource.Port < 0 || resource.Port > 65535 {
+		return &field.Error{
+			Type:     field.ErrorTypeInvalid,
+			Field:    "port",
+			Detail:   "should be a valid port number",
+			BadValue: resource.Port,
+		}
+	}
+
+	for _, header := range resource.Headers {
+		if errs := validation.IsHTTPHeaderName(header.Name); len(errs) != 0 {
+			return &field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "headers",
+				Detail:   strings.Join(errs, ", "),
+				BadValue: header.Name,
+			}
+		}
+		if len(header.Values) == 0 {
+			return &field.Error{
+				Type:   field.ErrorTypeRequired,
+				Field:  "headers",
+				Detail: fmt.Sprintf("header %s must have at least one value", header.Name),
+			}
+		}
+	}
+
+	if resource.JWT != nil {
+		return validateJWTRequirement(resource.JWT)
+	}
+
+	return nil
+}
+
+func validateJWTRequirement(requirement *otterizev1alpha3.JWTRequirement) *field.Error {
+	if requirement.Issuer == "" {
+		return &field.Error{
+			Type:   field.ErrorTypeRequired,
+			Field:  "jwt.issuer",
+			Detail: "invalid JWT requirement, field issuer is required",
+		}
+	}
+
+	if requirement.JWKSURI != "" {
+		jwksURI, err := url.Parse(requirement.JWKSURI)
+		if err != nil || jwksURI.Scheme != "https" || jwksURI.Host == "" {
+			return &field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "jwt.jwksUri",
+				Detail:   "should be an https URL",
+				BadValue: requirement.JWKSURI,
+			}
+		}
+	}
+
+	for _, claim := range requirement.Claims {
+		if claim.Name == "" || len(claim.Values) == 0 {
+			return &field.Error{
+				Type:     field.ErrorTypeInvalid,
+				Field:    "jwt.claims",
+				Detail:   "claims must have a name and at least one value",
+				BadValue: claim.Name,
+			}
+		}
+	}
+
+	return nil
+}
+
+// isValidHTTPMatchPattern checks that the pattern is an exact, prefix ("/api/*"), suffix ("*.json") or presence ("*")
+// match, which are the forms supported by Istio.
+func isValidHTTPMatchPattern(pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	return !strings.Contains(strings.TrimSuffix(strings.TrimPrefix(pattern, "*"), "*"), "*") &&
+		!(strings.HasPrefix(pattern, "*") && strings.HasSuffix(pattern, "*"))
+}
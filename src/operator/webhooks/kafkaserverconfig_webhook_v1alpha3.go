@@ -0,0 +1,93 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type KafkaServerConfigValidatorV1alpha3 struct {
+	client.Client
+}
+
+func NewKafkaServerConfigValidatorV1alpha3(c client.Client) *KafkaServerConfigValidatorV1alpha3 {
+	return &KafkaServerConfigValidatorV1alpha3{
+		Client: c,
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-k8s-otterize-com-v1alpha3-kafkaserverconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=k8s.otterize.com,resources=kafkaserverconfigs,verbs=create;update,versions=v1alpha3,name=kafkaserverconfigv1alpha3.kb.io,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &KafkaServerConfigValidatorV1alpha3{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (v *KafkaServerConfigValidatorV1alpha3) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validateSpec(obj.(*otterizev1alpha3.KafkaServerConfig))
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (v *KafkaServerConfigValidatorV1alpha3) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validateSpec(newObj.(*otterizev1alpha3.KafkaServerConfig))
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (v *KafkaServerConfigValidatorV1alpha3) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *KafkaServerConfigValidatorV1alpha3) validateSpec(kafkaServerConfig *otterizev1alpha3.KafkaServerConfig) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if kafkaServerConfig.Spec.TLS.SecretRef != nil {
+		if err := validateSecretNamespace(*kafkaServerConfig.Spec.TLS.SecretRef, kafkaServerConfig.Namespace, specPath.Child("tls", "secretRef", "namespace")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	if kafkaServerConfig.Spec.Authentication != nil {
+		if err := validateSecretNamespace(kafkaServerConfig.Spec.Authentication.SecretRef, kafkaServerConfig.Namespace, specPath.Child("authentication", "secretRef", "namespace")); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	gvk := kafkaServerConfig.GroupVersionKind()
+	return k8serrors.NewInvalid(
+		schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind},
+		kafkaServerConfig.Name, allErrs)
+}
+
+// validateSecretNamespace rejects Secrets in other namespaces, as the operator only reads the credentials of a Kafka
+// server from the namespace of its KafkaServerConfig, so that creating a KafkaServerConfig does not grant access to the
+// Secrets of other namespaces.
+func validateSecretNamespace(secretRef otterizev1alpha3.SecretReference, namespace string, fieldPath *field.Path) *field.Error {
+	if secretRef.Namespace == "" || secretRef.Namespace == namespace {
+		return nil
+	}
+	return field.Forbidden(fieldPath, fmt.Sprintf("Secret %s must be in the namespace of the KafkaServerConfig, %s", secretRef.Name, namespace))
+}
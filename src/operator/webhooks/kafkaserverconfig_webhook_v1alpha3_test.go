@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"context"
+	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
+	"github.com/stretchr/testify/suite"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+type KafkaServerConfigValidatorTestSuite struct {
+	suite.Suite
+	validator *KafkaServerConfigValidatorV1alpha3
+}
+
+func (s *KafkaServerConfigValidatorTestSuite) SetupTest() {
+	s.validator = NewKafkaServerConfigValidatorV1alpha3(nil)
+}
+
+func kafkaServerConfigWithSecrets(tlsSecretNamespace string, saslSecretNamespace string) *otterizev1alpha3.KafkaServerConfig {
+	return &otterizev1alpha3.KafkaServerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka-namespace"},
+		Spec: otterizev1alpha3.KafkaServerConfigSpec{
+			TLS: otterizev1alpha3.TLSSource{SecretRef: &otterizev1alpha3.SecretReference{Name: "kafka-tls", Namespace: tlsSecretNamespace}},
+			Authentication: &otterizev1alpha3.KafkaAuthentication{
+				Mechanism: otterizev1alpha3.KafkaSASLMechanismSCRAMSHA512,
+				SecretRef: otterizev1alpha3.SecretReference{Name: "kafka-sasl", Namespace: saslSecretNamespace},
+			},
+		},
+	}
+}
+
+func (s *KafkaServerConfigValidatorTestSuite) TestSecretsInOwnNamespaceAllowed() {
+	_, err := s.validator.ValidateCreate(context.Background(), kafkaServerConfigWithSecrets("", "kafka-namespace"))
+	s.NoError(err)
+}
+
+func (s *KafkaServerConfigValidatorTestSuite) TestTLSSecretInOtherNamespaceRejected() {
+	_, err := s.validator.ValidateCreate(context.Background(), kafkaServerConfigWithSecrets("other-namespace", ""))
+	s.Require().Error(err)
+	s.True(k8serrors.IsInvalid(err))
+	s.Contains(err.Error(), "spec.tls.secretRef.namespace")
+}
+
+func (s *KafkaServerConfigValidatorTestSuite) TestSASLSecretInOtherNamespaceRejectedOnUpdate() {
+	_, err := s.validator.ValidateUpdate(context.Background(), kafkaServerConfigWithSecrets("", ""), kafkaServerConfigWithSecrets("", "other-namespace"))
+	s.Require().Error(err)
+	s.True(k8serrors.IsInvalid(err))
+	s.Contains(err.Error(), "spec.authentication.secretRef.namespace")
+}
+
+func TestKafkaServerConfigValidatorTestSuite(t *testing.T) {
+	suite.Run(t, new(KafkaServerConfigValidatorTestSuite))
+}
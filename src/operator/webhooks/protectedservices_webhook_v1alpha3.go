@@ -150,5 +150,9 @@ func (v *ProtectedServiceValidatorV1alpha3) validateSpec(protectedService *otter
 		}
 	}
 
+	if protectedService.Spec.JWT != nil {
+		return validateJWTRequirement(protectedService.Spec.JWT)
+	}
+
 	return nil
 }
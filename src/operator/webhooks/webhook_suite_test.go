@@ -22,6 +22,7 @@ import (
 	otterizev1alpha2 "github.com/otterize/intents-operator/src/operator/api/v1alpha2"
 	otterizev1alpha3 "github.com/otterize/intents-operator/src/operator/api/v1alpha3"
 	"github.com/otterize/intents-operator/src/shared/testbase"
+	"github.com/samber/lo"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 	istiosecurityscheme "istio.io/client-go/pkg/apis/security/v1beta1"
@@ -198,6 +199,133 @@ func (s *ValidationWebhookTestSuite) TestNameRequiredForEveryTypeExceptInternet(
 	s.Require().NoError(err)
 }
 
+func (s *ValidationWebhookTestSuite) TestHTTPResourceMatching() {
+	_, err := s.AddIntentsV1alpha3("valid-http-intents", "valid-http-client", []otterizev1alpha3.Intent{
+		{
+			Name: "server",
+			Type: otterizev1alpha3.IntentTypeHTTP,
+			HTTPResources: []otterizev1alpha3.HTTPResource{{
+				Path:    "/api/*",
+				Methods: []otterizev1alpha3.HTTPMethod{otterizev1alpha3.HTTPMethodGet},
+				Hosts:   []string{"*.example.com"},
+				Port:    8080,
+				Headers: []otterizev1alpha3.HTTPHeader{{Name: "x-tenant-id", Values: []string{"tenant-a"}}},
+			}},
+		},
+	})
+	s.Require().NoError(err)
+
+	_, err = s.AddIntentsV1alpha3("invalid-path-intents", "invalid-path-client", []otterizev1alpha3.Intent{
+		{
+			Name:          "server",
+			Type:          otterizev1alpha3.IntentTypeHTTP,
+			HTTPResources: []otterizev1alpha3.HTTPResource{{Path: "/api/*/users"}},
+		},
+	})
+	s.Require().ErrorContains(err, "'*' is only allowed as the whole path or at its start or end")
+
+	_, err = s.AddIntentsV1alpha3("invalid-header-intents", "invalid-header-client", []otterizev1alpha3.Intent{
+		{
+			Name:          "server",
+			Type:          otterizev1alpha3.IntentTypeHTTP,
+			HTTPResources: []otterizev1alpha3.HTTPResource{{Path: "/api", Headers: []otterizev1alpha3.HTTPHeader{{Name: "x-tenant-id"}}}},
+		},
+	})
+	s.Require().ErrorContains(err, "header x-tenant-id must have at least one value")
+}
+
+func (s *ValidationWebhookTestSuite) TestJWTRequirementValidation() {
+	_, err := s.AddIntentsV1alpha3("missing-issuer-intents", "missing-issuer-client", []otterizev1alpha3.Intent{
+		{
+			Name:          "server",
+			Type:          otterizev1alpha3.IntentTypeHTTP,
+			HTTPResources: []otterizev1alpha3.HTTPResource{{Path: "/api", JWT: &otterizev1alpha3.JWTRequirement{}}},
+		},
+	})
+	s.Require().ErrorContains(err, "invalid JWT requirement, field issuer is required")
+
+	_, err = s.AddIntentsV1alpha3("insecure-jwks-intents", "insecure-jwks-client", []otterizev1alpha3.Intent{
+		{
+			Name: "server",
+			Type: otterizev1alpha3.IntentTypeHTTP,
+			HTTPResources: []otterizev1alpha3.HTTPResource{{
+				Path: "/api",
+				JWT:  &otterizev1alpha3.JWTRequirement{Issuer: "issuer", JWKSURI: "http://issuer/jwks.json"},
+			}},
+		},
+	})
+	s.Require().ErrorContains(err, "should be an https URL")
+}
+
+func (s *ValidationWebhookTestSuite) TestRateLimitValidation() {
+	_, err := s.AddIntentsV1alpha3("kafka-rate-limit-intents", "kafka-rate-limit-client", []otterizev1alpha3.Intent{
+		{
+			Name:      "server",
+			Type:      otterizev1alpha3.IntentTypeKafka,
+			RateLimit: &otterizev1alpha3.RateLimit{Requests: 10},
+		},
+	})
+	s.Require().ErrorContains(err, "can be rate limited")
+
+	_, err = s.AddIntentsV1alpha3("zero-rate-limit-intents", "zero-rate-limit-client", []otterizev1alpha3.Intent{
+		{
+			Name:      "server",
+			Type:      otterizev1alpha3.IntentTypeHTTP,
+			RateLimit: &otterizev1alpha3.RateLimit{Requests: 0, Per: otterizev1alpha3.RateLimitUnitMinute},
+		},
+	})
+	s.Require().ErrorContains(err, "rate limit must allow at least one request")
+}
+
+func (s *ValidationWebhookTestSuite) TestKafkaQuotaValidation() {
+	_, err := s.AddIntentsV1alpha3("http-quota-intents", "http-quota-client", []otterizev1alpha3.Intent{
+		{
+			Name:  "server",
+			Type:  otterizev1alpha3.IntentTypeHTTP,
+			Quota: &otterizev1alpha3.KafkaQuota{ProducerByteRate: lo.ToPtr(int64(1024))},
+		},
+	})
+	s.Require().ErrorContains(err, "can have kafka quotas")
+}
+
+func (s *ValidationWebhookTestSuite) TestExternalKafkaServerValidation() {
+	_, err := s.AddIntentsV1alpha3("http-external-intents", "http-external-client", []otterizev1alpha3.Intent{
+		{
+			Name: otterizev1alpha3.ExternalKafkaServerPrefix + "msk",
+			Type: otterizev1alpha3.IntentTypeHTTP,
+		},
+	})
+	s.Require().ErrorContains(err, "can target external servers")
+
+	_, err = s.AddIntentsV1alpha3("namespaced-external-intents", "namespaced-external-client", []otterizev1alpha3.Intent{
+		{
+			Name: otterizev1alpha3.ExternalKafkaServerPrefix + "msk.namespace",
+			Type: otterizev1alpha3.IntentTypeKafka,
+		},
+	})
+	s.Require().ErrorContains(err, "cannot specify a namespace")
+}
+
+func (s *ValidationWebhookTestSuite) TestAWSManagedPoliciesValidation() {
+	_, err := s.AddIntentsV1alpha3("http-managed-policies-intents", "http-managed-policies-client", []otterizev1alpha3.Intent{
+		{
+			Name:               "server",
+			Type:               otterizev1alpha3.IntentTypeHTTP,
+			AWSManagedPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+		},
+	})
+	s.Require().ErrorContains(err, "can attach managed policies")
+
+	_, err = s.AddIntentsV1alpha3("invalid-managed-policies-intents", "invalid-managed-policies-client", []otterizev1alpha3.Intent{
+		{
+			Name:               "s3-read-only",
+			Type:               otterizev1alpha3.IntentTypeAWS,
+			AWSManagedPolicies: []string{"AmazonS3ReadOnlyAccess"},
+		},
+	})
+	s.Require().ErrorContains(err, "should be the ARN of an IAM policy")
+}
+
 func (s *ValidationWebhookTestSuite) TestValidateProtectedServices() {
 	fakeValidator := NewProtectedServiceValidatorV1alpha2(nil)
 
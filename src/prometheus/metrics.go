@@ -34,6 +34,18 @@ var (
 		Name: "protected_services_applied",
 		Help: "The total number of ProtectedService resources applied",
 	})
+	kafkaACLsMissing = promauto.With(metrics.Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_acls_missing",
+		Help: "The number of ACLs required by intents that were missing from a Kafka server in the last resync",
+	}, []string{"server", "namespace"})
+	kafkaACLsUnexpected = promauto.With(metrics.Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_acls_unexpected",
+		Help: "The number of ACLs not required by intents that were found on a Kafka server in the last resync",
+	}, []string{"server", "namespace"})
+	kafkaACLsDriftRepaired = promauto.With(metrics.Registry).NewCounter(prometheus.CounterOpts{
+		Name: "kafka_acls_drift_repaired",
+		Help: "The total number of missing or unexpected Kafka ACLs repaired by resyncs",
+	})
 )
 
 func IncrementIntentsApplied(count int) {
@@ -58,3 +70,12 @@ func IncrementPodsUnlabeledForNetworkPolicies(count int) {
 func SetProtectedServicesApplied(count int) {
 	protectedServiceApplied.Set(float64(count))
 }
+
+func SetKafkaACLDrift(serverName string, serverNamespace string, missing int, unexpected int) {
+	kafkaACLsMissing.WithLabelValues(serverName, serverNamespace).Set(float64(missing))
+	kafkaACLsUnexpected.WithLabelValues(serverName, serverNamespace).Set(float64(unexpected))
+}
+
+func IncrementKafkaACLDriftRepaired(count int) {
+	kafkaACLsDriftRepaired.Add(float64(count))
+}
@@ -44,6 +44,15 @@ type IAMClient interface {
 	UntagPolicy(ctx context.Context, params *iam.UntagPolicyInput, optFns ...func(*iam.Options)) (*iam.UntagPolicyOutput, error)
 	UntagRole(ctx context.Context, params *iam.UntagRoleInput, optFns ...func(*iam.Options)) (*iam.UntagRoleOutput, error)
 	AttachRolePolicy(ctx context.Context, i *iam.AttachRolePolicyInput, opts ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error)
+	PutRolePermissionsBoundary(ctx context.Context, i *iam.PutRolePermissionsBoundaryInput, opts ...func(*iam.Options)) (*iam.PutRolePermissionsBoundaryOutput, error)
+}
+
+// EKSClient manages the EKS Pod Identity associations that bind IAM roles to service accounts.
+type EKSClient interface {
+	CreatePodIdentityAssociation(ctx context.Context, params *eks.CreatePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.CreatePodIdentityAssociationOutput, error)
+	DeletePodIdentityAssociation(ctx context.Context, params *eks.DeletePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DeletePodIdentityAssociationOutput, error)
+	DescribePodIdentityAssociation(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error)
+	ListPodIdentityAssociations(ctx context.Context, params *eks.ListPodIdentityAssociationsInput, optFns ...func(*eks.Options)) (*eks.ListPodIdentityAssociationsOutput, error)
 }
 
 type RolesAnywhereClient interface {
@@ -88,8 +97,21 @@ type Agent struct {
 	RolesAnywhereEnabled             bool
 	TrustAnchorArn                   string
 	TrustDomain                      string
+	// PodIdentityEnabled makes roles trust EKS Pod Identity instead of the cluster's OIDC provider (IRSA). Pods get
+	// their credentials through the Pod Identity association of their service account, rather than through the role
+	// ARN annotation on it.
+	PodIdentityEnabled bool
+	// PermissionBoundaryARN is the permission boundary of created roles. Defaults to the
+	// <cluster name>-limit-iam-permission-boundary policy of the account.
+	PermissionBoundaryARN string
+	// RolePath is the IAM path of created roles. Defaults to "/".
+	RolePath string
+	// CustomTags are added to created roles and policies. Values may reference the namespace and service account of
+	// the role as $(NAMESPACE) and $(SERVICE_ACCOUNT).
+	CustomTags map[string]string
 
 	iamClient           IAMClient
+	eksClient           EKSClient
 	rolesAnywhereClient RolesAnywhereClient
 	profileNameToId     map[string]string
 	profileCacheOnce    sync.Once
@@ -124,6 +146,30 @@ func AppliesOnPod(pod *corev1.Pod) bool {
 	return foundLabel
 }
 
+func WithPodIdentity() Option {
+	return func(a *Agent) {
+		a.PodIdentityEnabled = true
+	}
+}
+
+func WithPermissionBoundary(permissionBoundaryARN string) Option {
+	return func(a *Agent) {
+		a.PermissionBoundaryARN = permissionBoundaryARN
+	}
+}
+
+func WithRolePath(rolePath string) Option {
+	return func(a *Agent) {
+		a.RolePath = rolePath
+	}
+}
+
+func WithCustomTags(tags map[string]string) Option {
+	return func(a *Agent) {
+		a.CustomTags = tags
+	}
+}
+
 func WithRolesAnywhere(account operatorconfig.AWSAccount, clusterName string, keyPath string, certPath string) Option {
 	configTimeout, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -159,6 +205,10 @@ func NewAWSAgent(
 		option.Apply(agent)
 	}
 
+	if err := agent.validateRoleSettings(); err != nil {
+		return nil, errors.Wrap(err)
+	}
+
 	if !agent.config.Set {
 		// config was not initialized by option, use default
 		awsConfig, err := config.LoadDefaultConfig(ctx)
@@ -175,8 +225,13 @@ func NewAWSAgent(
 	rolesAnywhereClient := rolesanywhere.NewFromConfig(agent.config.Item)
 	stsClient := sts.NewFromConfig(agent.config.Item)
 	agent.iamClient = iamClient
+	agent.eksClient = eks.NewFromConfig(agent.config.Item)
 	agent.rolesAnywhereClient = rolesAnywhereClient
 
+	if agent.RolesAnywhereEnabled && agent.PodIdentityEnabled {
+		return nil, errors.New("EKS Pod Identity and IAM Roles Anywhere cannot be enabled together")
+	}
+
 	if !agent.RolesAnywhereEnabled {
 		currentCluster, err := getCurrentEKSCluster(ctx, agent.config.Item)
 
@@ -185,8 +240,11 @@ func NewAWSAgent(
 		}
 		agent.ClusterName = *currentCluster.Name
 
-		OidcURL := *currentCluster.Identity.Oidc.Issuer
-		agent.OidcURL = strings.Split(OidcURL, "://")[1]
+		// Pod Identity does not require the cluster to have an OIDC provider.
+		if !agent.PodIdentityEnabled {
+			OidcURL := *currentCluster.Identity.Oidc.Issuer
+			agent.OidcURL = strings.Split(OidcURL, "://")[1]
+		}
 	}
 
 	callerIdent, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
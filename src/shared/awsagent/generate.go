@@ -0,0 +1,3 @@
+package awsagent
+
+//go:generate go run go.uber.org/mock/mockgen@v0.2.0 -destination=./mocks/mock_aws_clients.go -package=awsagentmocks -source=agent.go IAMClient,EKSClient,RolesAnywhereClient
@@ -0,0 +1,225 @@
+package awsagent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+)
+
+// managedPolicyTagKey returns the key of the role tag that marks the attachment of a managed policy as owned by
+// Otterize. Policy ARNs may contain characters that are not allowed in tag keys, so the key is derived from a hash of
+// the ARN. Several intents services may share the service account of a role, so the value of the tag lists the names
+// of all intents services that requested the attachment, separated by spaces.
+func managedPolicyTagKey(policyARN string) string {
+	sum := sha256.Sum256([]byte(policyARN))
+	return managedPolicyTagKeyPrefix + hex.EncodeToString(sum[:8])
+}
+
+func getManagedPolicyOwners(tags []types.Tag) map[string][]string {
+	ownershipTags := lo.Filter(tags, func(tag types.Tag, _ int) bool {
+		return strings.HasPrefix(aws.ToString(tag.Key), managedPolicyTagKeyPrefix)
+	})
+	return lo.SliceToMap(ownershipTags, func(tag types.Tag) (string, []string) {
+		return aws.ToString(tag.Key), strings.Fields(aws.ToString(tag.Value))
+	})
+}
+
+func managedPolicyOwnershipTag(tagKey string, owners []string) types.Tag {
+	owners = lo.Uniq(owners)
+	slices.Sort(owners)
+	return types.Tag{Key: aws.String(tagKey), Value: aws.String(strings.Join(owners, " "))}
+}
+
+// SetRoleManagedPolicies attaches the given existing managed policies to the role of the service account on behalf of
+// the intents service, and detaches managed policies that the intents service no longer requests, once no other
+// intents service requests them either. Policies that were already attached to the role by someone else are left
+// untouched.
+func (a *Agent) SetRoleManagedPolicies(ctx context.Context, namespace string, accountName string, intentsServiceName string, policyARNs []string) error {
+	exists, role, err := a.GetOtterizeRole(ctx, namespace, accountName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if !exists {
+		return errors.Errorf("role not found: %s", a.generateRoleName(namespace, accountName))
+	}
+
+	listOutput, err := a.iamClient.ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{RoleName: role.RoleName})
+	if err != nil {
+		return errors.Errorf("failed to list role attached policies: %w", err)
+	}
+
+	attachedPolicyARNs := lo.SliceToMap(listOutput.AttachedPolicies, func(policy types.AttachedPolicy) (string, string) {
+		return managedPolicyTagKey(aws.ToString(policy.PolicyArn)), aws.ToString(policy.PolicyArn)
+	})
+	owners := getManagedPolicyOwners(role.Tags)
+	desiredTagKeys := make(map[string]bool)
+	ownershipTags := make([]types.Tag, 0)
+
+	for _, policyARN := range lo.Uniq(policyARNs) {
+		tagKey := managedPolicyTagKey(policyARN)
+		policyOwners, owned := owners[tagKey]
+		_, attached := attachedPolicyARNs[tagKey]
+		desiredTagKeys[tagKey] = true
+
+		if attached && !owned {
+			logrus.Debugf("managed policy %s was attached to role %s outside of Otterize, not managing it", policyARN, *role.RoleName)
+			continue
+		}
+
+		if !attached {
+			logrus.Debugf("attaching managed policy %s to role %s", policyARN, *role.RoleName)
+			_, err = a.iamClient.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+				PolicyArn: aws.String(policyARN),
+				RoleName:  role.RoleName,
+			})
+			if err != nil {
+				return errors.Errorf("failed to attach managed policy %s: %w", policyARN, err)
+			}
+		}
+
+		if !lo.Contains(policyOwners, intentsServiceName) {
+			ownershipTags = append(ownershipTags, managedPolicyOwnershipTag(tagKey, append(policyOwners, intentsServiceName)))
+		}
+	}
+
+	staleTagKeys := make([]string, 0)
+	for tagKey, policyOwners := range owners {
+		if desiredTagKeys[tagKey] || !lo.Contains(policyOwners, intentsServiceName) {
+			continue
+		}
+
+		// the policy is still requested by other intents services sharing the service account
+		if remainingOwners := lo.Without(policyOwners, intentsServiceName); len(remainingOwners) > 0 {
+			ownershipTags = append(ownershipTags, managedPolicyOwnershipTag(tagKey, remainingOwners))
+			continue
+		}
+
+		// the policy may have been detached outside of Otterize, in which case only its ownership tag is left
+		if policyARN, attached := attachedPolicyARNs[tagKey]; attached {
+			logrus.Debugf("detaching managed policy %s from role %s", policyARN, *role.RoleName)
+			_, err = a.iamClient.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+				PolicyArn: aws.String(policyARN),
+				RoleName:  role.RoleName,
+			})
+			if err != nil && !isNoSuchEntityException(err) {
+				return errors.Errorf("failed to detach managed policy %s: %w", policyARN, err)
+			}
+		}
+		staleTagKeys = append(staleTagKeys, tagKey)
+	}
+
+	if len(ownershipTags) > 0 {
+		_, err = a.iamClient.TagRole(ctx, &iam.TagRoleInput{RoleName: role.RoleName, Tags: ownershipTags})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	if len(staleTagKeys) > 0 {
+		_, err = a.iamClient.UntagRole(ctx, &iam.UntagRoleInput{RoleName: role.RoleName, TagKeys: staleTagKeys})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// DetachRoleManagedPolicies detaches the given managed policies from the roles in the namespace that Otterize attached
+// them to on behalf of the intents service. The role of the service is not known once its intents are deleted, so it
+// is found through the entities the policies are attached to. Roles are not filtered by the configured path, as roles
+// created before it was changed remain under their previous path.
+func (a *Agent) DetachRoleManagedPolicies(ctx context.Context, namespace string, intentsServiceName string, policyARNs []string) error {
+	for _, policyARN := range lo.Uniq(policyARNs) {
+		listEntitiesOutput, err := a.iamClient.ListEntitiesForPolicy(ctx, &iam.ListEntitiesForPolicyInput{
+			PolicyArn:    aws.String(policyARN),
+			EntityFilter: types.EntityTypeRole,
+		})
+		if err != nil {
+			if isNoSuchEntityException(err) {
+				continue
+			}
+			return errors.Wrap(err)
+		}
+
+		for _, policyRole := range listEntitiesOutput.PolicyRoles {
+			roleOutput, err := a.iamClient.GetRole(ctx, &iam.GetRoleInput{RoleName: policyRole.RoleName})
+			if err != nil {
+				if isNoSuchEntityException(err) {
+					continue
+				}
+				return errors.Wrap(err)
+			}
+
+			if !a.isManagedPolicyOwner(roleOutput.Role, namespace, intentsServiceName, policyARN) {
+				continue
+			}
+
+			tagKey := managedPolicyTagKey(policyARN)
+			// the policy is still requested by other intents services sharing the service account
+			if remainingOwners := lo.Without(getManagedPolicyOwners(roleOutput.Role.Tags)[tagKey], intentsServiceName); len(remainingOwners) > 0 {
+				_, err = a.iamClient.TagRole(ctx, &iam.TagRoleInput{RoleName: policyRole.RoleName, Tags: []types.Tag{managedPolicyOwnershipTag(tagKey, remainingOwners)}})
+				if err != nil {
+					return errors.Wrap(err)
+				}
+				continue
+			}
+
+			logrus.Debugf("detaching managed policy %s from role %s", policyARN, *policyRole.RoleName)
+			_, err = a.iamClient.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+				PolicyArn: aws.String(policyARN),
+				RoleName:  policyRole.RoleName,
+			})
+			if err != nil && !isNoSuchEntityException(err) {
+				return errors.Errorf("failed to detach managed policy %s: %w", policyARN, err)
+			}
+
+			_, err = a.iamClient.UntagRole(ctx, &iam.UntagRoleInput{RoleName: policyRole.RoleName, TagKeys: []string{tagKey}})
+			if err != nil {
+				return errors.Wrap(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Agent) isManagedPolicyOwner(role *types.Role, namespace string, intentsServiceName string, policyARN string) bool {
+	tags := lo.SliceToMap(role.Tags, func(tag types.Tag) (string, string) {
+		return aws.ToString(tag.Key), aws.ToString(tag.Value)
+	})
+
+	return tags[clusterNameTagKey] == a.ClusterName &&
+		tags[serviceAccountNamespaceTagKey] == namespace &&
+		lo.Contains(strings.Fields(tags[managedPolicyTagKey(policyARN)]), intentsServiceName)
+}
+
+// detachOwnedManagedPolicies detaches all managed policies Otterize attached to the role, in preparation to delete it.
+func (a *Agent) detachOwnedManagedPolicies(ctx context.Context, role *types.Role, attachedPolicies []types.AttachedPolicy) error {
+	owners := getManagedPolicyOwners(role.Tags)
+
+	for _, policy := range attachedPolicies {
+		if _, owned := owners[managedPolicyTagKey(aws.ToString(policy.PolicyArn))]; !owned {
+			continue
+		}
+
+		_, err := a.iamClient.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+			PolicyArn: policy.PolicyArn,
+			RoleName:  role.RoleName,
+		})
+		if err != nil && !isNoSuchEntityException(err) {
+			return errors.Errorf("failed to detach managed policy %s: %w", aws.ToString(policy.PolicyArn), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package awsagent
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	awsagentmocks "github.com/otterize/intents-operator/src/shared/awsagent/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+const (
+	testIntentsServiceName  = "client"
+	otherIntentsServiceName = "other-client"
+	s3ReadOnlyPolicyARN     = "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"
+	sqsFullAccessPolicyARN  = "arn:aws:iam::aws:policy/AmazonSQSFullAccess"
+	userManagedPolicyARN    = "arn:aws:iam::123456789012:policy/user-managed"
+)
+
+type ManagedPoliciesSuite struct {
+	suite.Suite
+	iamClient *awsagentmocks.MockIAMClient
+	agent     *Agent
+	roleName  *string
+}
+
+func (s *ManagedPoliciesSuite) SetupTest() {
+	controller := gomock.NewController(s.T())
+	s.iamClient = awsagentmocks.NewMockIAMClient(controller)
+	s.agent = &Agent{
+		AccountID:   "123456789012",
+		ClusterName: testClusterName,
+		iamClient:   s.iamClient,
+	}
+	s.roleName = aws.String(s.agent.generateRoleName(testNamespace, testServiceAccountName))
+}
+
+func (s *ManagedPoliciesSuite) roleTags(ownedPolicyARNs ...string) []types.Tag {
+	tags := []types.Tag{
+		{Key: aws.String(clusterNameTagKey), Value: aws.String(testClusterName)},
+		{Key: aws.String(serviceAccountNamespaceTagKey), Value: aws.String(testNamespace)},
+	}
+	for _, policyARN := range ownedPolicyARNs {
+		tags = append(tags, types.Tag{Key: aws.String(managedPolicyTagKey(policyARN)), Value: aws.String(testIntentsServiceName)})
+	}
+	return tags
+}
+
+func (s *ManagedPoliciesSuite) expectRole(tags []types.Tag, attachedPolicyARNs ...string) {
+	s.iamClient.EXPECT().GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: s.roleName}).Return(&iam.GetRoleOutput{
+		Role: &types.Role{RoleName: s.roleName, Tags: tags},
+	}, nil)
+
+	attachedPolicies := make([]types.AttachedPolicy, 0)
+	for _, policyARN := range attachedPolicyARNs {
+		attachedPolicies = append(attachedPolicies, types.AttachedPolicy{PolicyArn: aws.String(policyARN)})
+	}
+	s.iamClient.EXPECT().ListAttachedRolePolicies(gomock.Any(), &iam.ListAttachedRolePoliciesInput{RoleName: s.roleName}).Return(&iam.ListAttachedRolePoliciesOutput{
+		AttachedPolicies: attachedPolicies,
+	}, nil)
+}
+
+func (s *ManagedPoliciesSuite) TestSetRoleManagedPoliciesAttachesAndTracksOwnership() {
+	s.expectRole(s.roleTags())
+	s.iamClient.EXPECT().AttachRolePolicy(gomock.Any(), &iam.AttachRolePolicyInput{PolicyArn: aws.String(s3ReadOnlyPolicyARN), RoleName: s.roleName}).Return(&iam.AttachRolePolicyOutput{}, nil)
+	s.iamClient.EXPECT().TagRole(gomock.Any(), &iam.TagRoleInput{
+		RoleName: s.roleName,
+		Tags:     []types.Tag{{Key: aws.String(managedPolicyTagKey(s3ReadOnlyPolicyARN)), Value: aws.String(testIntentsServiceName)}},
+	}).Return(&iam.TagRoleOutput{}, nil)
+
+	err := s.agent.SetRoleManagedPolicies(context.Background(), testNamespace, testServiceAccountName, testIntentsServiceName, []string{s3ReadOnlyPolicyARN, s3ReadOnlyPolicyARN})
+	s.Require().NoError(err)
+}
+
+func (s *ManagedPoliciesSuite) TestSetRoleManagedPoliciesDetachesOnlyOwnedPolicies() {
+	s.expectRole(s.roleTags(s3ReadOnlyPolicyARN, sqsFullAccessPolicyARN), s3ReadOnlyPolicyARN, sqsFullAccessPolicyARN, userManagedPolicyARN)
+	s.iamClient.EXPECT().DetachRolePolicy(gomock.Any(), &iam.DetachRolePolicyInput{PolicyArn: aws.String(sqsFullAccessPolicyARN), RoleName: s.roleName}).Return(&iam.DetachRolePolicyOutput{}, nil)
+	s.iamClient.EXPECT().UntagRole(gomock.Any(), &iam.UntagRoleInput{RoleName: s.roleName, TagKeys: []string{managedPolicyTagKey(sqsFullAccessPolicyARN)}}).Return(&iam.UntagRoleOutput{}, nil)
+
+	err := s.agent.SetRoleManagedPolicies(context.Background(), testNamespace, testServiceAccountName, testIntentsServiceName, []string{s3ReadOnlyPolicyARN})
+	s.Require().NoError(err)
+}
+
+func (s *ManagedPoliciesSuite) TestSetRoleManagedPoliciesDoesNotTakeOverExistingAttachments() {
+	s.expectRole(s.roleTags(), userManagedPolicyARN)
+
+	err := s.agent.SetRoleManagedPolicies(context.Background(), testNamespace, testServiceAccountName, testIntentsServiceName, []string{userManagedPolicyARN})
+	s.Require().NoError(err)
+}
+
+func (s *ManagedPoliciesSuite) TestSetRoleManagedPoliciesSharesPoliciesBetweenServicesOfRole() {
+	tags := append(s.roleTags(),
+		types.Tag{Key: aws.String(managedPolicyTagKey(s3ReadOnlyPolicyARN)), Value: aws.String(testIntentsServiceName + " " + otherIntentsServiceName)},
+		types.Tag{Key: aws.String(managedPolicyTagKey(sqsFullAccessPolicyARN)), Value: aws.String(otherIntentsServiceName)},
+	)
+	s.expectRole(tags, s3ReadOnlyPolicyARN, sqsFullAccessPolicyARN)
+	s.iamClient.EXPECT().TagRole(gomock.Any(), &iam.TagRoleInput{
+		RoleName: s.roleName,
+		Tags: []types.Tag{
+			{Key: aws.String(managedPolicyTagKey(sqsFullAccessPolicyARN)), Value: aws.String(testIntentsServiceName + " " + otherIntentsServiceName)},
+			{Key: aws.String(managedPolicyTagKey(s3ReadOnlyPolicyARN)), Value: aws.String(otherIntentsServiceName)},
+		},
+	}).Return(&iam.TagRoleOutput{}, nil)
+
+	err := s.agent.SetRoleManagedPolicies(context.Background(), testNamespace, testServiceAccountName, testIntentsServiceName, []string{sqsFullAccessPolicyARN})
+	s.Require().NoError(err)
+}
+
+func (s *ManagedPoliciesSuite) TestSetRoleManagedPoliciesDoesNotDetachPoliciesOfOtherServices() {
+	tags := append(s.roleTags(), types.Tag{Key: aws.String(managedPolicyTagKey(sqsFullAccessPolicyARN)), Value: aws.String(otherIntentsServiceName)})
+	s.expectRole(tags, sqsFullAccessPolicyARN)
+
+	err := s.agent.SetRoleManagedPolicies(context.Background(), testNamespace, testServiceAccountName, testIntentsServiceName, nil)
+	s.Require().NoError(err)
+}
+
+func (s *ManagedPoliciesSuite) TestDetachRoleManagedPoliciesDetachesFromOwningRoles() {
+	otherRoleName := aws.String("other-role")
+	s.iamClient.EXPECT().ListEntitiesForPolicy(gomock.Any(), &iam.ListEntitiesForPolicyInput{
+		PolicyArn:    aws.String(s3ReadOnlyPolicyARN),
+		EntityFilter: types.EntityTypeRole,
+	}).Return(&iam.ListEntitiesForPolicyOutput{PolicyRoles: []types.PolicyRole{{RoleName: s.roleName}, {RoleName: otherRoleName}}}, nil)
+	s.iamClient.EXPECT().GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: s.roleName}).Return(&iam.GetRoleOutput{
+		Role: &types.Role{RoleName: s.roleName, Tags: s.roleTags(s3ReadOnlyPolicyARN)},
+	}, nil)
+	s.iamClient.EXPECT().GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: otherRoleName}).Return(&iam.GetRoleOutput{
+		Role: &types.Role{RoleName: otherRoleName},
+	}, nil)
+	s.iamClient.EXPECT().DetachRolePolicy(gomock.Any(), &iam.DetachRolePolicyInput{PolicyArn: aws.String(s3ReadOnlyPolicyARN), RoleName: s.roleName}).Return(&iam.DetachRolePolicyOutput{}, nil)
+	s.iamClient.EXPECT().UntagRole(gomock.Any(), &iam.UntagRoleInput{RoleName: s.roleName, TagKeys: []string{managedPolicyTagKey(s3ReadOnlyPolicyARN)}}).Return(&iam.UntagRoleOutput{}, nil)
+
+	err := s.agent.DetachRoleManagedPolicies(context.Background(), testNamespace, testIntentsServiceName, []string{s3ReadOnlyPolicyARN})
+	s.Require().NoError(err)
+}
+
+func (s *ManagedPoliciesSuite) TestDetachRoleManagedPoliciesKeepsPoliciesOfOtherServices() {
+	s.iamClient.EXPECT().ListEntitiesForPolicy(gomock.Any(), gomock.Any()).Return(&iam.ListEntitiesForPolicyOutput{PolicyRoles: []types.PolicyRole{{RoleName: s.roleName}}}, nil)
+	tags := append(s.roleTags(), types.Tag{Key: aws.String(managedPolicyTagKey(s3ReadOnlyPolicyARN)), Value: aws.String(testIntentsServiceName + " " + otherIntentsServiceName)})
+	s.iamClient.EXPECT().GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: s.roleName}).Return(&iam.GetRoleOutput{
+		Role: &types.Role{RoleName: s.roleName, Tags: tags},
+	}, nil)
+	s.iamClient.EXPECT().TagRole(gomock.Any(), &iam.TagRoleInput{
+		RoleName: s.roleName,
+		Tags:     []types.Tag{{Key: aws.String(managedPolicyTagKey(s3ReadOnlyPolicyARN)), Value: aws.String(otherIntentsServiceName)}},
+	}).Return(&iam.TagRoleOutput{}, nil)
+
+	err := s.agent.DetachRoleManagedPolicies(context.Background(), testNamespace, testIntentsServiceName, []string{s3ReadOnlyPolicyARN})
+	s.Require().NoError(err)
+}
+
+func TestManagedPoliciesSuite(t *testing.T) {
+	suite.Run(t, new(ManagedPoliciesSuite))
+}
@@ -0,0 +1,101 @@
+package awsagent
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksTypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// GetPodIdentityAssociation gets the EKS Pod Identity association of a service account, which can have at most one
+// association per cluster.
+func (a *Agent) GetPodIdentityAssociation(ctx context.Context, namespace string, serviceAccountName string) (found bool, association *eksTypes.PodIdentityAssociation, err error) {
+	listOutput, err := a.eksClient.ListPodIdentityAssociations(ctx, &eks.ListPodIdentityAssociationsInput{
+		ClusterName:    aws.String(a.ClusterName),
+		Namespace:      aws.String(namespace),
+		ServiceAccount: aws.String(serviceAccountName),
+	})
+	if err != nil {
+		return false, nil, errors.Errorf("failed to list pod identity associations: %w", err)
+	}
+
+	if len(listOutput.Associations) == 0 {
+		return false, nil, nil
+	}
+
+	describeOutput, err := a.eksClient.DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
+		ClusterName:   aws.String(a.ClusterName),
+		AssociationId: listOutput.Associations[0].AssociationId,
+	})
+	if err != nil {
+		return false, nil, errors.Errorf("failed to describe pod identity association: %w", err)
+	}
+
+	return true, describeOutput.Association, nil
+}
+
+// CreatePodIdentityAssociationForRole associates the role with the service account, so that its pods get credentials
+// for the role. A service account already associated with another role is left as is, as the association may not be
+// managed by Otterize.
+func (a *Agent) CreatePodIdentityAssociationForRole(ctx context.Context, role types.Role, namespace string, serviceAccountName string) error {
+	found, association, err := a.GetPodIdentityAssociation(ctx, namespace, serviceAccountName)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	if found {
+		if aws.ToString(association.RoleArn) != aws.ToString(role.Arn) {
+			return errors.Errorf("service account %s/%s is already associated with role %s", namespace, serviceAccountName, aws.ToString(association.RoleArn))
+		}
+		return nil
+	}
+
+	_, err = a.eksClient.CreatePodIdentityAssociation(ctx, &eks.CreatePodIdentityAssociationInput{
+		ClusterName:    aws.String(a.ClusterName),
+		Namespace:      aws.String(namespace),
+		ServiceAccount: aws.String(serviceAccountName),
+		RoleArn:        role.Arn,
+		Tags: map[string]string{
+			serviceAccountNameTagKey:      serviceAccountName,
+			serviceAccountNamespaceTagKey: namespace,
+			clusterNameTagKey:             a.ClusterName,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+// DeletePodIdentityAssociationForServiceAccount deletes the Pod Identity association of the service account, if it
+// associates it with the role Otterize created for it.
+func (a *Agent) DeletePodIdentityAssociationForServiceAccount(ctx context.Context, namespace string, serviceAccountName string) (bool, error) {
+	found, association, err := a.GetPodIdentityAssociation(ctx, namespace, serviceAccountName)
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	if !a.isOtterizeRoleARN(aws.ToString(association.RoleArn), namespace, serviceAccountName) {
+		logrus.WithField("namespace", namespace).WithField("account", serviceAccountName).
+			Info("pod identity association is not for the Otterize role, not deleting it")
+		return false, nil
+	}
+
+	_, err = a.eksClient.DeletePodIdentityAssociation(ctx, &eks.DeletePodIdentityAssociationInput{
+		ClusterName:   aws.String(a.ClusterName),
+		AssociationId: association.AssociationId,
+	})
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,225 @@
+package awsagent
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	eksTypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	awsagentmocks "github.com/otterize/intents-operator/src/shared/awsagent/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+const (
+	testClusterName        = "test-cluster"
+	testNamespace          = "test-namespace"
+	testServiceAccountName = "test-service-account"
+	testAssociationID      = "a-1234"
+)
+
+type PodIdentitySuite struct {
+	suite.Suite
+	eksClient *awsagentmocks.MockEKSClient
+	iamClient *awsagentmocks.MockIAMClient
+	agent     *Agent
+}
+
+func (s *PodIdentitySuite) SetupTest() {
+	controller := gomock.NewController(s.T())
+	s.eksClient = awsagentmocks.NewMockEKSClient(controller)
+	s.iamClient = awsagentmocks.NewMockIAMClient(controller)
+	s.agent = &Agent{
+		AccountID:          "123456789012",
+		ClusterName:        testClusterName,
+		PodIdentityEnabled: true,
+		eksClient:          s.eksClient,
+		iamClient:          s.iamClient,
+	}
+}
+
+func (s *PodIdentitySuite) expectAssociation(roleARN string) {
+	s.eksClient.EXPECT().ListPodIdentityAssociations(gomock.Any(), &eks.ListPodIdentityAssociationsInput{
+		ClusterName:    aws.String(testClusterName),
+		Namespace:      aws.String(testNamespace),
+		ServiceAccount: aws.String(testServiceAccountName),
+	}).Return(&eks.ListPodIdentityAssociationsOutput{
+		Associations: []eksTypes.PodIdentityAssociationSummary{{AssociationId: aws.String(testAssociationID)}},
+	}, nil)
+	s.eksClient.EXPECT().DescribePodIdentityAssociation(gomock.Any(), &eks.DescribePodIdentityAssociationInput{
+		ClusterName:   aws.String(testClusterName),
+		AssociationId: aws.String(testAssociationID),
+	}).Return(&eks.DescribePodIdentityAssociationOutput{
+		Association: &eksTypes.PodIdentityAssociation{AssociationId: aws.String(testAssociationID), RoleArn: aws.String(roleARN)},
+	}, nil)
+}
+
+func (s *PodIdentitySuite) expectNoAssociation() {
+	s.eksClient.EXPECT().ListPodIdentityAssociations(gomock.Any(), gomock.Any()).Return(&eks.ListPodIdentityAssociationsOutput{}, nil)
+}
+
+func (s *PodIdentitySuite) TestTrustPolicyTrustsPodIdentityForServiceAccount() {
+	trustPolicy, err := s.agent.generateTrustPolicy(testNamespace, testServiceAccountName)
+	s.Require().NoError(err)
+
+	var policy PolicyDocument
+	s.Require().NoError(json.Unmarshal([]byte(trustPolicy), &policy))
+	s.Require().Len(policy.Statement, 1)
+	s.Equal(map[string]string{"Service": podIdentityServicePrincipal}, policy.Statement[0].Principal)
+	s.Equal([]string{"sts:AssumeRole", "sts:TagSession"}, policy.Statement[0].Action)
+	s.Equal(map[string]any{
+		"StringEquals": map[string]any{
+			"aws:RequestTag/eks-cluster-name":           testClusterName,
+			"aws:RequestTag/kubernetes-namespace":       testNamespace,
+			"aws:RequestTag/kubernetes-service-account": testServiceAccountName,
+		},
+	}, policy.Statement[0].Condition)
+}
+
+func (s *PodIdentitySuite) TestCreateAssociation() {
+	role := types.Role{Arn: aws.String(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName))}
+	s.expectNoAssociation()
+	s.eksClient.EXPECT().CreatePodIdentityAssociation(gomock.Any(), &eks.CreatePodIdentityAssociationInput{
+		ClusterName:    aws.String(testClusterName),
+		Namespace:      aws.String(testNamespace),
+		ServiceAccount: aws.String(testServiceAccountName),
+		RoleArn:        role.Arn,
+		Tags: map[string]string{
+			serviceAccountNameTagKey:      testServiceAccountName,
+			serviceAccountNamespaceTagKey: testNamespace,
+			clusterNameTagKey:             testClusterName,
+		},
+	}).Return(&eks.CreatePodIdentityAssociationOutput{}, nil)
+
+	s.Require().NoError(s.agent.CreatePodIdentityAssociationForRole(context.Background(), role, testNamespace, testServiceAccountName))
+}
+
+func (s *PodIdentitySuite) TestCreateAssociationExistingForRole() {
+	role := types.Role{Arn: aws.String(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName))}
+	s.expectAssociation(*role.Arn)
+
+	s.Require().NoError(s.agent.CreatePodIdentityAssociationForRole(context.Background(), role, testNamespace, testServiceAccountName))
+}
+
+func (s *PodIdentitySuite) TestCreateAssociationExistingForOtherRole() {
+	role := types.Role{Arn: aws.String(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName))}
+	s.expectAssociation("arn:aws:iam::123456789012:role/other")
+
+	err := s.agent.CreatePodIdentityAssociationForRole(context.Background(), role, testNamespace, testServiceAccountName)
+	s.Require().ErrorContains(err, "already associated with role arn:aws:iam::123456789012:role/other")
+}
+
+func (s *PodIdentitySuite) TestDeleteAssociation() {
+	s.expectAssociation(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName))
+	s.eksClient.EXPECT().DeletePodIdentityAssociation(gomock.Any(), &eks.DeletePodIdentityAssociationInput{
+		ClusterName:   aws.String(testClusterName),
+		AssociationId: aws.String(testAssociationID),
+	}).Return(&eks.DeletePodIdentityAssociationOutput{}, nil)
+
+	deleted, err := s.agent.DeletePodIdentityAssociationForServiceAccount(context.Background(), testNamespace, testServiceAccountName)
+	s.Require().NoError(err)
+	s.True(deleted)
+}
+
+func (s *PodIdentitySuite) TestDeleteAssociationForRoleUnderPreviousPath() {
+	s.expectAssociation(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName))
+	s.agent.RolePath = "/otterize/"
+	s.eksClient.EXPECT().DeletePodIdentityAssociation(gomock.Any(), &eks.DeletePodIdentityAssociationInput{
+		ClusterName:   aws.String(testClusterName),
+		AssociationId: aws.String(testAssociationID),
+	}).Return(&eks.DeletePodIdentityAssociationOutput{}, nil)
+
+	deleted, err := s.agent.DeletePodIdentityAssociationForServiceAccount(context.Background(), testNamespace, testServiceAccountName)
+	s.Require().NoError(err)
+	s.True(deleted)
+}
+
+func (s *PodIdentitySuite) TestDeleteAssociationKeepsOtherRoles() {
+	s.expectAssociation("arn:aws:iam::123456789012:role/other")
+
+	deleted, err := s.agent.DeletePodIdentityAssociationForServiceAccount(context.Background(), testNamespace, testServiceAccountName)
+	s.Require().NoError(err)
+	s.False(deleted)
+}
+
+func (s *PodIdentitySuite) otterizeRole() *types.Role {
+	return &types.Role{
+		RoleName: aws.String(s.agent.generateRoleName(testNamespace, testServiceAccountName)),
+		Arn:      aws.String(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName)),
+		Tags: []types.Tag{
+			{Key: aws.String(serviceAccountNameTagKey), Value: aws.String(testServiceAccountName)},
+			{Key: aws.String(serviceAccountNamespaceTagKey), Value: aws.String(testNamespace)},
+			{Key: aws.String(clusterNameTagKey), Value: aws.String(testClusterName)},
+		},
+		PermissionsBoundary: &types.AttachedPermissionsBoundary{PermissionsBoundaryArn: aws.String(s.agent.getPermissionBoundaryARN())},
+	}
+}
+
+func (s *PodIdentitySuite) expectGetRole(role *types.Role) {
+	call := s.iamClient.EXPECT().GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: aws.String(s.agent.generateRoleName(testNamespace, testServiceAccountName))})
+	if role == nil {
+		call.Return(nil, &types.NoSuchEntityException{Message: aws.String("not found")})
+		return
+	}
+	call.Return(&iam.GetRoleOutput{Role: role}, nil)
+}
+
+func (s *PodIdentitySuite) TestRoleLifecycleManagesAssociation() {
+	role := s.otterizeRole()
+
+	s.expectGetRole(nil)
+	s.iamClient.EXPECT().CreateRole(gomock.Any(), gomock.Any()).Return(&iam.CreateRoleOutput{Role: role}, nil)
+	s.expectNoAssociation()
+	s.eksClient.EXPECT().CreatePodIdentityAssociation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *eks.CreatePodIdentityAssociationInput, _ ...func(*eks.Options)) (*eks.CreatePodIdentityAssociationOutput, error) {
+			s.Equal(testNamespace, aws.ToString(input.Namespace))
+			s.Equal(testServiceAccountName, aws.ToString(input.ServiceAccount))
+			s.Equal(aws.ToString(role.Arn), aws.ToString(input.RoleArn))
+			return &eks.CreatePodIdentityAssociationOutput{}, nil
+		})
+
+	createdRole, err := s.agent.CreateOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName, false)
+	s.Require().NoError(err)
+	s.Equal(role.Arn, createdRole.Arn)
+
+	// the association already exists when the role is reconciled again
+	s.expectGetRole(role)
+	s.expectAssociation(aws.ToString(role.Arn))
+
+	_, err = s.agent.CreateOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName, false)
+	s.Require().NoError(err)
+
+	s.expectGetRole(role)
+	s.expectAssociation(aws.ToString(role.Arn))
+	s.eksClient.EXPECT().DeletePodIdentityAssociation(gomock.Any(), &eks.DeletePodIdentityAssociationInput{
+		ClusterName:   aws.String(testClusterName),
+		AssociationId: aws.String(testAssociationID),
+	}).Return(&eks.DeletePodIdentityAssociationOutput{}, nil)
+	s.iamClient.EXPECT().ListAttachedRolePolicies(gomock.Any(), &iam.ListAttachedRolePoliciesInput{RoleName: role.RoleName}).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+	s.iamClient.EXPECT().DeleteRole(gomock.Any(), &iam.DeleteRoleInput{RoleName: role.RoleName}).Return(&iam.DeleteRoleOutput{}, nil)
+
+	s.Require().NoError(s.agent.DeleteOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName))
+}
+
+func (s *PodIdentitySuite) TestAssociationDeletedWithoutRole() {
+	s.expectGetRole(nil)
+	s.expectAssociation(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName))
+	s.eksClient.EXPECT().DeletePodIdentityAssociation(gomock.Any(), gomock.Any()).Return(&eks.DeletePodIdentityAssociationOutput{}, nil)
+
+	s.Require().NoError(s.agent.DeleteOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName))
+}
+
+func (s *PodIdentitySuite) TestRoleCreationFailsForServiceAccountAssociatedWithOtherRole() {
+	s.expectGetRole(s.otterizeRole())
+	s.expectAssociation("arn:aws:iam::123456789012:role/other")
+
+	_, err := s.agent.CreateOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName, false)
+	s.Require().ErrorContains(err, "already associated with role arn:aws:iam::123456789012:role/other")
+}
+
+func TestPodIdentitySuite(t *testing.T) {
+	suite.Run(t, new(PodIdentitySuite))
+}
@@ -27,18 +27,39 @@ func (a *Agent) AddRolePolicy(ctx context.Context, namespace string, accountName
 
 	softDeletionStrategyEnabled := HasSoftDeleteStrategyTagSet(role.Tags)
 
-	policyArn := a.generatePolicyArn(a.generatePolicyName(namespace, intentsServiceName))
+	policyName := a.generatePolicyName(namespace, intentsServiceName)
+	parts, err := splitPolicyStatements(compactStatements(statements))
+	if err != nil {
+		return errors.Wrap(err)
+	}
 
+	for part, document := range parts {
+		err = a.applyPolicyPart(ctx, role, generatePolicyPartName(policyName, part), namespace, accountName, intentsServiceName, document, softDeletionStrategyEnabled)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	// parts beyond the current ones are left over from when the policy was larger
+	err = a.deletePolicyParts(ctx, policyName, len(parts))
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
+	return nil
+}
+
+func (a *Agent) applyPolicyPart(ctx context.Context, role *types.Role, policyName string, namespace string, accountName string, intentsServiceName string, document policyDocumentPart, softDeletionStrategyEnabled bool) error {
 	policyOutput, err := a.iamClient.GetPolicy(ctx, &iam.GetPolicyInput{
-		PolicyArn: aws.String(policyArn),
+		PolicyArn: aws.String(a.generatePolicyArn(policyName)),
 	})
 	if err != nil {
 		if isNoSuchEntityException(err) {
-			if len(statements) == 0 {
+			if len(document.Statement) == 0 {
 				// nothing to do
 				return nil
 			}
-			_, err := a.createPolicy(ctx, role, namespace, intentsServiceName, statements, softDeletionStrategyEnabled)
+			_, err := a.createPolicy(ctx, role, policyName, namespace, accountName, intentsServiceName, document, softDeletionStrategyEnabled)
 			return errors.Wrap(err)
 		}
 
@@ -48,7 +69,7 @@ func (a *Agent) AddRolePolicy(ctx context.Context, namespace string, accountName
 	// policy exists, update it
 	policy := policyOutput.Policy
 
-	err = a.updatePolicy(ctx, policy, statements, softDeletionStrategyEnabled)
+	err = a.updatePolicy(ctx, policy, document, a.generateCustomTags(namespace, accountName), softDeletionStrategyEnabled)
 
 	if err != nil {
 		return errors.Wrap(err)
@@ -67,21 +88,42 @@ func (a *Agent) DeleteRolePolicyByNamespacedName(ctx context.Context, namespace
 	return a.DeleteRolePolicy(ctx, a.generatePolicyName(namespace, accountName))
 }
 
+// DeleteRolePolicy deletes the policy, along with the numbered parts it was split into if it exceeded the maximum
+// policy size.
 func (a *Agent) DeleteRolePolicy(ctx context.Context, policyName string) error {
+	return a.deletePolicyParts(ctx, policyName, 0)
+}
+
+// deletePolicyParts deletes the parts of the policy starting from the given part. Parts are always created in order,
+// so deletion stops at the first part that does not exist.
+func (a *Agent) deletePolicyParts(ctx context.Context, policyName string, fromPart int) error {
+	for part := fromPart; ; part++ {
+		found, err := a.deletePolicy(ctx, generatePolicyPartName(policyName, part))
+		if err != nil {
+			return errors.Wrap(err)
+		}
+
+		if !found {
+			return nil
+		}
+	}
+}
+
+func (a *Agent) deletePolicy(ctx context.Context, policyName string) (bool, error) {
 	output, err := a.iamClient.GetPolicy(ctx, &iam.GetPolicyInput{
 		PolicyArn: aws.String(a.generatePolicyArn(policyName)),
 	})
 
 	if err != nil {
 		if isNoSuchEntityException(err) {
-			return nil
+			return false, nil
 		}
 
-		return errors.Wrap(err)
+		return false, errors.Wrap(err)
 	}
 
 	if HasSoftDeleteStrategyTagSet(output.Policy.Tags) {
-		return a.softDeletePolicy(ctx, policyName)
+		return true, a.softDeletePolicy(ctx, policyName)
 	}
 
 	policy := output.Policy
@@ -91,7 +133,7 @@ func (a *Agent) DeleteRolePolicy(ctx context.Context, policyName string) error {
 	})
 
 	if err != nil {
-		return errors.Wrap(err)
+		return false, errors.Wrap(err)
 	}
 
 	for _, role := range listEntitiesOutput.PolicyRoles {
@@ -100,11 +142,11 @@ func (a *Agent) DeleteRolePolicy(ctx context.Context, policyName string) error {
 			RoleName:  role.RoleName,
 		})
 		if isNoSuchEntityException(err) {
-			return nil
+			return true, nil
 		}
 
 		if err != nil {
-			return errors.Wrap(err)
+			return false, errors.Wrap(err)
 		}
 	}
 
@@ -113,7 +155,7 @@ func (a *Agent) DeleteRolePolicy(ctx context.Context, policyName string) error {
 	})
 
 	if err != nil {
-		return errors.Wrap(err)
+		return false, errors.Wrap(err)
 	}
 
 	for _, version := range listPolicyVersionsOutput.Versions {
@@ -125,7 +167,7 @@ func (a *Agent) DeleteRolePolicy(ctx context.Context, policyName string) error {
 			})
 
 			if err != nil {
-				return errors.Wrap(err)
+				return false, errors.Wrap(err)
 			}
 		}
 	}
@@ -135,10 +177,10 @@ func (a *Agent) DeleteRolePolicy(ctx context.Context, policyName string) error {
 	})
 
 	if err != nil {
-		return errors.Wrap(err)
+		return false, errors.Wrap(err)
 	}
 
-	return nil
+	return true, nil
 }
 
 func (a *Agent) softDeletePolicy(ctx context.Context, policyName string) error {
@@ -194,9 +236,8 @@ func (a *Agent) SetRolePolicy(ctx context.Context, namespace, accountName string
 	return nil
 }
 
-func (a *Agent) createPolicy(ctx context.Context, role *types.Role, namespace string, intentsServiceName string, statements []StatementEntry, useSoftDeleteStrategy bool) (*types.Policy, error) {
-	fullPolicyName := a.generatePolicyName(namespace, intentsServiceName)
-	policyDoc, policyHash, err := generatePolicyDocument(statements)
+func (a *Agent) createPolicy(ctx context.Context, role *types.Role, policyName string, namespace string, accountName string, intentsServiceName string, document policyDocumentPart, useSoftDeleteStrategy bool) (*types.Policy, error) {
+	policyDoc, policyHash, err := serializePolicyDocument(document)
 
 	if err != nil {
 		return nil, errors.Wrap(err)
@@ -219,10 +260,11 @@ func (a *Agent) createPolicy(ctx context.Context, role *types.Role, namespace st
 	if useSoftDeleteStrategy {
 		tags = append(tags, types.Tag{Key: aws.String(softDeletionStrategyTagKey), Value: aws.String(softDeletionStrategyTagValue)})
 	}
+	tags = append(tags, a.generateCustomTags(namespace, accountName)...)
 
 	policy, err := a.iamClient.CreatePolicy(ctx, &iam.CreatePolicyInput{
 		PolicyDocument: aws.String(policyDoc),
-		PolicyName:     aws.String(fullPolicyName),
+		PolicyName:     aws.String(policyName),
 		Tags:           tags,
 	})
 
@@ -239,8 +281,8 @@ func (a *Agent) createPolicy(ctx context.Context, role *types.Role, namespace st
 	return policy.Policy, nil
 }
 
-func (a *Agent) updatePolicy(ctx context.Context, policy *types.Policy, statements []StatementEntry, useSoftDeleteStrategy bool) error {
-	policyDoc, policyHash, err := generatePolicyDocument(statements)
+func (a *Agent) updatePolicy(ctx context.Context, policy *types.Policy, document policyDocumentPart, customTags []types.Tag, useSoftDeleteStrategy bool) error {
+	policyDoc, policyHash, err := serializePolicyDocument(document)
 
 	if err != nil {
 		return errors.Wrap(err)
@@ -279,6 +321,17 @@ func (a *Agent) updatePolicy(ctx context.Context, policy *types.Policy, statemen
 		}
 	}
 
+	if missingTags := getMissingTags(policy.Tags, customTags); len(missingTags) > 0 {
+		logrus.Debugf("adding custom tags to policy: %s", *policy.PolicyName)
+		_, err = a.iamClient.TagPolicy(ctx, &iam.TagPolicyInput{
+			PolicyArn: policy.Arn,
+			Tags:      missingTags,
+		})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
 	existingHashTag, found := lo.Find(policy.Tags, func(item types.Tag) bool {
 		return *item.Key == policyHashTagKey
 	})
@@ -360,10 +413,13 @@ func (a *Agent) attachPolicy(ctx context.Context, role *types.Role, policy *type
 }
 
 func generatePolicyDocument(statements []StatementEntry) (string, string, error) {
-	policy := PolicyDocument{
+	return serializePolicyDocument(PolicyDocument{
 		Version:   iamAPIVersion,
 		Statement: statements,
-	}
+	})
+}
+
+func serializePolicyDocument(policy any) (string, string, error) {
 	serialized, err := json.Marshal(policy)
 
 	if err != nil {
@@ -380,6 +436,15 @@ func (a *Agent) generatePolicyName(namespace string, intentsServiceName string)
 
 }
 
+// generatePolicyPartName returns the name of a part of a policy that was split to fit the maximum policy size. The
+// first part keeps the name of the policy.
+func generatePolicyPartName(policyName string, part int) string {
+	if part == 0 {
+		return policyName
+	}
+	return fmt.Sprintf("%s+%d", policyName, part+1)
+}
+
 func (a *Agent) generatePolicyArn(policyName string) string {
 	return fmt.Sprintf("arn:aws:iam::%s:policy/%s", a.AccountID, policyName)
 }
@@ -0,0 +1,185 @@
+package awsagent
+
+import (
+	"encoding/json"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxPolicyDocumentLength is the maximum size of a managed policy document, not counting whitespace.
+const maxPolicyDocumentLength = 6144
+
+// policyStatement is a compacted StatementEntry, which may apply to several resources.
+type policyStatement struct {
+	Effect    string            `json:"Effect,omitempty"`
+	Action    []string          `json:"Action,omitempty"`
+	Resource  policyResources   `json:"Resource,omitempty"`
+	Principal map[string]string `json:"Principal,omitempty"`
+	Sid       string            `json:"Sid,omitempty"`
+	Condition map[string]any    `json:"Condition,omitempty"`
+}
+
+type policyResources []string
+
+// MarshalJSON serializes a single resource as a string, so that policies that could not be compacted serialize the
+// same as their statements.
+func (r policyResources) MarshalJSON() ([]byte, error) {
+	if len(r) == 1 {
+		return json.Marshal(r[0])
+	}
+	return json.Marshal([]string(r))
+}
+
+type policyDocumentPart struct {
+	Version   string
+	Statement []policyStatement
+}
+
+func isMergeableStatement(statement StatementEntry) bool {
+	return statement.Sid == "" && len(statement.Principal) == 0 && len(statement.Condition) == 0
+}
+
+// compactStatements merges statements that share a resource or a set of actions, and drops actions and resources
+// that are covered by wildcards of the same statement. Statements with a Sid, principal or condition are kept as is.
+func compactStatements(statements []StatementEntry) []policyStatement {
+	byResource := make([]StatementEntry, 0)
+	resourceIndexes := make(map[string]int)
+	for _, statement := range statements {
+		if !isMergeableStatement(statement) {
+			byResource = append(byResource, statement)
+			continue
+		}
+
+		key := statement.Effect + "\x00" + statement.Resource
+		if i, ok := resourceIndexes[key]; ok {
+			byResource[i].Action = append(byResource[i].Action, statement.Action...)
+			continue
+		}
+		resourceIndexes[key] = len(byResource)
+		statement.Action = slices.Clone(statement.Action)
+		byResource = append(byResource, statement)
+	}
+
+	compacted := make([]policyStatement, 0)
+	actionsIndexes := make(map[string]int)
+	for _, statement := range byResource {
+		if !isMergeableStatement(statement) {
+			compacted = append(compacted, policyStatement{
+				Effect:    statement.Effect,
+				Action:    statement.Action,
+				Resource:  lo.Ternary(statement.Resource == "", nil, policyResources{statement.Resource}),
+				Principal: statement.Principal,
+				Sid:       statement.Sid,
+				Condition: statement.Condition,
+			})
+			continue
+		}
+
+		actions := deduplicateCoveredValues(statement.Action, true)
+		normalizedActions := lo.Map(actions, func(action string, _ int) string {
+			return strings.ToLower(action)
+		})
+		slices.Sort(normalizedActions)
+		key := statement.Effect + "\x00" + strings.Join(normalizedActions, "\x00")
+		if i, ok := actionsIndexes[key]; ok && statement.Resource != "" && len(compacted[i].Resource) > 0 {
+			compacted[i].Resource = append(compacted[i].Resource, statement.Resource)
+			continue
+		}
+		actionsIndexes[key] = len(compacted)
+		compacted = append(compacted, policyStatement{
+			Effect:   statement.Effect,
+			Action:   actions,
+			Resource: lo.Ternary(statement.Resource == "", nil, policyResources{statement.Resource}),
+		})
+	}
+
+	for i := range compacted {
+		if len(compacted[i].Resource) > 1 {
+			compacted[i].Resource = deduplicateCoveredValues(compacted[i].Resource, false)
+		}
+	}
+
+	return compacted
+}
+
+// deduplicateCoveredValues removes duplicate actions or resources, and those matched by a wildcard of another value,
+// keeping the order of the rest. Actions are case-insensitive, resources are not.
+func deduplicateCoveredValues(values []string, caseInsensitive bool) []string {
+	normalize := func(value string) string {
+		return lo.Ternary(caseInsensitive, strings.ToLower(value), value)
+	}
+	unique := lo.UniqBy(values, normalize)
+
+	return lo.Filter(unique, func(value string, i int) bool {
+		for j, other := range unique {
+			if i == j || !wildcardCovers(normalize(other), normalize(value)) {
+				continue
+			}
+			// values that cover each other are the same pattern in effect, the first one is kept
+			if !wildcardCovers(normalize(value), normalize(other)) || j < i {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// wildcardCovers returns whether the IAM wildcard pattern matches everything the other value matches. Values with
+// policy variables are never considered covered, since their value is only known when the policy is evaluated.
+func wildcardCovers(pattern string, value string) bool {
+	if !strings.ContainsAny(pattern, "*?") || strings.Contains(pattern, "${") || strings.Contains(value, "${") {
+		return false
+	}
+	regex := "^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern)) + "$"
+	return regexp.MustCompile(regex).MatchString(value)
+}
+
+// splitPolicyStatements splits the statements into as few policy documents as possible, each within the maximum
+// policy size. Statements that are too large on their own are split by resource.
+func splitPolicyStatements(statements []policyStatement) ([]policyDocumentPart, error) {
+	emptyDocument, err := json.Marshal(policyDocumentPart{Version: iamAPIVersion, Statement: []policyStatement{}})
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	maxStatementsLength := maxPolicyDocumentLength - len(emptyDocument)
+
+	parts := []policyDocumentPart{{Version: iamAPIVersion}}
+	partLength := 0
+	pending := slices.Clone(statements)
+	for len(pending) > 0 {
+		statement := pending[0]
+		pending = pending[1:]
+
+		serialized, err := json.Marshal(statement)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+
+		if len(serialized) > maxStatementsLength {
+			if len(statement.Resource) <= 1 {
+				return nil, errors.Errorf("policy statement for resource %s exceeds the maximum policy size of %d characters", strings.Join(statement.Resource, ", "), maxPolicyDocumentLength)
+			}
+			half := len(statement.Resource) / 2
+			first, second := statement, statement
+			first.Resource, second.Resource = statement.Resource[:half], statement.Resource[half:]
+			pending = append([]policyStatement{first, second}, pending...)
+			continue
+		}
+
+		// statements after the first are separated by a comma
+		statementLength := len(serialized) + lo.Ternary(partLength == 0, 0, 1)
+		if partLength+statementLength > maxStatementsLength {
+			parts = append(parts, policyDocumentPart{Version: iamAPIVersion})
+			statementLength = len(serialized)
+			partLength = 0
+		}
+
+		parts[len(parts)-1].Statement = append(parts[len(parts)-1].Statement, statement)
+		partLength += statementLength
+	}
+
+	return parts, nil
+}
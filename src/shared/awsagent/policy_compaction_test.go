@@ -0,0 +1,146 @@
+package awsagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	awsagentmocks "github.com/otterize/intents-operator/src/shared/awsagent/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type PolicyCompactionSuite struct {
+	suite.Suite
+	iamClient *awsagentmocks.MockIAMClient
+	agent     *Agent
+}
+
+func (s *PolicyCompactionSuite) SetupTest() {
+	controller := gomock.NewController(s.T())
+	s.iamClient = awsagentmocks.NewMockIAMClient(controller)
+	s.agent = &Agent{
+		AccountID:   "123456789012",
+		ClusterName: testClusterName,
+		iamClient:   s.iamClient,
+	}
+}
+
+func (s *PolicyCompactionSuite) TestCompactStatementsMergesResourcesAndActions() {
+	compacted := compactStatements([]StatementEntry{
+		{Effect: iamEffectAllow, Resource: "arn:aws:s3:::bucket/*", Action: []string{"s3:GetObject"}},
+		{Effect: iamEffectAllow, Resource: "arn:aws:sqs:us-east-1:123456789012:queue", Action: []string{"sqs:SendMessage"}},
+		{Effect: iamEffectAllow, Resource: "arn:aws:s3:::bucket/*", Action: []string{"s3:PutObject", "s3:getobject"}},
+		{Effect: iamEffectAllow, Resource: "arn:aws:s3:::other-bucket/*", Action: []string{"s3:PutObject", "s3:GetObject"}},
+	})
+
+	s.Equal([]policyStatement{
+		{Effect: iamEffectAllow, Resource: policyResources{"arn:aws:s3:::bucket/*", "arn:aws:s3:::other-bucket/*"}, Action: []string{"s3:GetObject", "s3:PutObject"}},
+		{Effect: iamEffectAllow, Resource: policyResources{"arn:aws:sqs:us-east-1:123456789012:queue"}, Action: []string{"sqs:SendMessage"}},
+	}, compacted)
+}
+
+func (s *PolicyCompactionSuite) TestCompactStatementsRemovesValuesCoveredByWildcards() {
+	compacted := compactStatements([]StatementEntry{
+		{Effect: iamEffectAllow, Resource: "arn:aws:s3:::bucket/reports/*", Action: []string{"s3:GetObject", "s3:Get*", "s3:ListBucket"}},
+		{Effect: iamEffectAllow, Resource: "arn:aws:s3:::bucket/reports/2024", Action: []string{"s3:ListBucket", "s3:Get*"}},
+		{Effect: iamEffectAllow, Resource: "arn:aws:s3:::bucket/${aws:username}", Action: []string{"s3:Get*", "s3:ListBucket"}},
+	})
+
+	s.Equal([]policyStatement{
+		{Effect: iamEffectAllow, Resource: policyResources{"arn:aws:s3:::bucket/reports/*", "arn:aws:s3:::bucket/${aws:username}"}, Action: []string{"s3:Get*", "s3:ListBucket"}},
+	}, compacted)
+}
+
+func (s *PolicyCompactionSuite) TestCompactStatementsKeepsConditionalStatements() {
+	conditional := StatementEntry{
+		Effect:    iamEffectAllow,
+		Resource:  "arn:aws:s3:::bucket/*",
+		Action:    []string{"s3:GetObject"},
+		Condition: map[string]any{"Bool": map[string]string{"aws:SecureTransport": "true"}},
+	}
+	compacted := compactStatements([]StatementEntry{conditional, {Effect: iamEffectAllow, Resource: "arn:aws:s3:::bucket/*", Action: []string{"s3:GetObject"}}})
+
+	s.Len(compacted, 2)
+	s.Equal(conditional.Condition, compacted[0].Condition)
+}
+
+func (s *PolicyCompactionSuite) TestUncompactedPolicySerializesAsBefore() {
+	statements := []StatementEntry{{Effect: iamEffectAllow, Resource: "arn:aws:s3:::bucket/*", Action: []string{"s3:GetObject"}}}
+	parts, err := splitPolicyStatements(compactStatements(statements))
+	s.Require().NoError(err)
+	s.Require().Len(parts, 1)
+
+	expectedDoc, expectedHash, err := generatePolicyDocument(statements)
+	s.Require().NoError(err)
+	doc, hash, err := serializePolicyDocument(parts[0])
+	s.Require().NoError(err)
+	s.Equal(expectedDoc, doc)
+	s.Equal(expectedHash, hash)
+}
+
+func (s *PolicyCompactionSuite) TestSplitPolicyStatementsFitsMaximumSize() {
+	statements := make([]StatementEntry, 0)
+	for i := 0; i < 200; i++ {
+		statements = append(statements, StatementEntry{
+			Effect:   iamEffectAllow,
+			Resource: fmt.Sprintf("arn:aws:sqs:us-east-1:123456789012:queue-%d", i),
+			Action:   []string{"sqs:SendMessage", fmt.Sprintf("sqs:Action%d", i)},
+		})
+	}
+
+	parts, err := splitPolicyStatements(compactStatements(statements))
+	s.Require().NoError(err)
+	s.Greater(len(parts), 1)
+
+	statementCount := 0
+	for _, part := range parts {
+		serialized, err := json.Marshal(part)
+		s.Require().NoError(err)
+		s.LessOrEqual(len(serialized), maxPolicyDocumentLength)
+		statementCount += len(part.Statement)
+	}
+	s.Equal(len(statements), statementCount)
+}
+
+func (s *PolicyCompactionSuite) TestSplitPolicyStatementsSplitsLargeStatementsByResource() {
+	resources := make(policyResources, 0)
+	for i := 0; i < 300; i++ {
+		resources = append(resources, fmt.Sprintf("arn:aws:s3:::bucket-%d/*", i))
+	}
+
+	parts, err := splitPolicyStatements([]policyStatement{{Effect: iamEffectAllow, Resource: resources, Action: []string{"s3:GetObject"}}})
+	s.Require().NoError(err)
+	s.Greater(len(parts), 1)
+
+	splitResources := make(policyResources, 0)
+	for _, part := range parts {
+		for _, statement := range part.Statement {
+			splitResources = append(splitResources, statement.Resource...)
+		}
+	}
+	s.Equal(resources, splitResources)
+}
+
+func (s *PolicyCompactionSuite) TestDeleteRolePolicyDeletesAllParts() {
+	policyName := s.agent.generatePolicyName(testNamespace, "client")
+	for part := 0; part < 2; part++ {
+		policyArn := aws.String(s.agent.generatePolicyArn(generatePolicyPartName(policyName, part)))
+		s.iamClient.EXPECT().GetPolicy(gomock.Any(), &iam.GetPolicyInput{PolicyArn: policyArn}).Return(&iam.GetPolicyOutput{Policy: &types.Policy{Arn: policyArn}}, nil)
+		s.iamClient.EXPECT().ListEntitiesForPolicy(gomock.Any(), &iam.ListEntitiesForPolicyInput{PolicyArn: policyArn}).Return(&iam.ListEntitiesForPolicyOutput{}, nil)
+		s.iamClient.EXPECT().ListPolicyVersions(gomock.Any(), &iam.ListPolicyVersionsInput{PolicyArn: policyArn}).Return(&iam.ListPolicyVersionsOutput{}, nil)
+		s.iamClient.EXPECT().DeletePolicy(gomock.Any(), &iam.DeletePolicyInput{PolicyArn: policyArn}).Return(&iam.DeletePolicyOutput{}, nil)
+	}
+	s.iamClient.EXPECT().GetPolicy(gomock.Any(), &iam.GetPolicyInput{PolicyArn: aws.String(s.agent.generatePolicyArn(generatePolicyPartName(policyName, 2)))}).
+		Return(nil, &types.NoSuchEntityException{Message: aws.String("not found")})
+
+	err := s.agent.DeleteRolePolicy(context.Background(), policyName)
+	s.Require().NoError(err)
+}
+
+func TestPolicyCompactionSuite(t *testing.T) {
+	suite.Run(t, new(PolicyCompactionSuite))
+}
@@ -0,0 +1,72 @@
+package awsagent
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/otterize/intents-operator/src/shared/errors"
+	"github.com/samber/lo"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const otterizeTagKeyPrefix = "otterize/"
+
+var (
+	namespaceTagTemplateRegex      = regexp.MustCompile(`\$\(NAMESPACE\)`)
+	serviceAccountTagTemplateRegex = regexp.MustCompile(`\$\(SERVICE_ACCOUNT\)`)
+)
+
+// generateCustomTags returns the configured custom tags of the role of a service account and of its policies, sorted
+// by key.
+func (a *Agent) generateCustomTags(namespace string, accountName string) []types.Tag {
+	keys := lo.Keys(a.CustomTags)
+	slices.Sort(keys)
+
+	return lo.Map(keys, func(key string, _ int) types.Tag {
+		value := namespaceTagTemplateRegex.ReplaceAllLiteralString(a.CustomTags[key], namespace)
+		value = serviceAccountTagTemplateRegex.ReplaceAllLiteralString(value, accountName)
+		return types.Tag{Key: aws.String(key), Value: aws.String(value)}
+	})
+}
+
+// getMissingTags returns the desired tags that are not set to the same value in the existing tags.
+func getMissingTags(existing []types.Tag, desired []types.Tag) []types.Tag {
+	existingValues := lo.SliceToMap(existing, func(tag types.Tag) (string, string) {
+		return aws.ToString(tag.Key), aws.ToString(tag.Value)
+	})
+
+	return lo.Filter(desired, func(tag types.Tag, _ int) bool {
+		value, ok := existingValues[aws.ToString(tag.Key)]
+		return !ok || value != aws.ToString(tag.Value)
+	})
+}
+
+// validateRoleSettings validates the configured role path and custom tags, which AWS would otherwise only reject when
+// the first role is created.
+func (a *Agent) validateRoleSettings() error {
+	if a.RolePath != "" && (!strings.HasPrefix(a.RolePath, "/") || !strings.HasSuffix(a.RolePath, "/")) {
+		return errors.Errorf("role path %s must begin and end with '/'", a.RolePath)
+	}
+	for key := range a.CustomTags {
+		if strings.HasPrefix(key, otterizeTagKeyPrefix) {
+			return errors.Errorf("custom tag %s uses the reserved prefix %s", key, otterizeTagKeyPrefix)
+		}
+	}
+	return nil
+}
+
+func (a *Agent) getPermissionBoundaryARN() string {
+	if a.PermissionBoundaryARN != "" {
+		return a.PermissionBoundaryARN
+	}
+	return fmt.Sprintf("arn:aws:iam::%s:policy/%s-limit-iam-permission-boundary", a.AccountID, a.ClusterName)
+}
+
+func (a *Agent) getRolePath() string {
+	if a.RolePath != "" {
+		return a.RolePath
+	}
+	return "/"
+}
@@ -0,0 +1,142 @@
+package awsagent
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	awsagentmocks "github.com/otterize/intents-operator/src/shared/awsagent/mocks"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+const testPermissionBoundaryARN = "arn:aws:iam::123456789012:policy/security-boundary"
+
+type RoleSettingsSuite struct {
+	suite.Suite
+	iamClient *awsagentmocks.MockIAMClient
+	agent     *Agent
+}
+
+func (s *RoleSettingsSuite) SetupTest() {
+	controller := gomock.NewController(s.T())
+	s.iamClient = awsagentmocks.NewMockIAMClient(controller)
+	s.agent = &Agent{
+		AccountID:             "123456789012",
+		ClusterName:           testClusterName,
+		OidcURL:               "oidc.eks.us-east-1.amazonaws.com/id/1234",
+		PermissionBoundaryARN: testPermissionBoundaryARN,
+		RolePath:              "/otterize/",
+		CustomTags:            map[string]string{"team": "payments", "owner": "$(SERVICE_ACCOUNT).$(NAMESPACE)"},
+		iamClient:             s.iamClient,
+	}
+}
+
+func (s *RoleSettingsSuite) expectGetRole(role *types.Role) {
+	call := s.iamClient.EXPECT().GetRole(gomock.Any(), &iam.GetRoleInput{RoleName: aws.String(s.agent.generateRoleName(testNamespace, testServiceAccountName))})
+	if role == nil {
+		call.Return(nil, &types.NoSuchEntityException{Message: aws.String("not found")})
+		return
+	}
+	call.Return(&iam.GetRoleOutput{Role: role}, nil)
+}
+
+func (s *RoleSettingsSuite) TestGenerateCustomTagsTemplatesValues() {
+	s.Equal([]types.Tag{
+		{Key: aws.String("owner"), Value: aws.String(testServiceAccountName + "." + testNamespace)},
+		{Key: aws.String("team"), Value: aws.String("payments")},
+	}, s.agent.generateCustomTags(testNamespace, testServiceAccountName))
+}
+
+func (s *RoleSettingsSuite) TestCreateRoleAppliesSettings() {
+	s.expectGetRole(nil)
+	s.iamClient.EXPECT().CreateRole(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input *iam.CreateRoleInput, _ ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+			s.Equal("/otterize/", aws.ToString(input.Path))
+			s.Equal(testPermissionBoundaryARN, aws.ToString(input.PermissionsBoundary))
+			s.Subset(input.Tags, []types.Tag{
+				{Key: aws.String("owner"), Value: aws.String(testServiceAccountName + "." + testNamespace)},
+				{Key: aws.String("team"), Value: aws.String("payments")},
+			})
+			return &iam.CreateRoleOutput{Role: &types.Role{Arn: aws.String(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName))}}, nil
+		})
+
+	role, err := s.agent.CreateOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName, false)
+	s.Require().NoError(err)
+	s.Equal("arn:aws:iam::123456789012:role/otterize/"+s.agent.generateRoleName(testNamespace, testServiceAccountName), aws.ToString(role.Arn))
+}
+
+func (s *RoleSettingsSuite) TestExistingRoleReconciled() {
+	roleName := aws.String(s.agent.generateRoleName(testNamespace, testServiceAccountName))
+	s.expectGetRole(&types.Role{
+		Arn:                 aws.String(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName)),
+		RoleName:            roleName,
+		Path:                aws.String("/otterize/"),
+		PermissionsBoundary: &types.AttachedPermissionsBoundary{PermissionsBoundaryArn: aws.String("arn:aws:iam::123456789012:policy/old-boundary")},
+		Tags:                []types.Tag{{Key: aws.String("team"), Value: aws.String("payments")}},
+	})
+	s.iamClient.EXPECT().PutRolePermissionsBoundary(gomock.Any(), &iam.PutRolePermissionsBoundaryInput{
+		RoleName:            roleName,
+		PermissionsBoundary: aws.String(testPermissionBoundaryARN),
+	}).Return(&iam.PutRolePermissionsBoundaryOutput{}, nil)
+	s.iamClient.EXPECT().TagRole(gomock.Any(), &iam.TagRoleInput{
+		RoleName: roleName,
+		Tags:     []types.Tag{{Key: aws.String("owner"), Value: aws.String(testServiceAccountName + "." + testNamespace)}},
+	}).Return(&iam.TagRoleOutput{}, nil)
+
+	_, err := s.agent.CreateOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName, false)
+	s.Require().NoError(err)
+}
+
+func (s *RoleSettingsSuite) TestExistingRoleUpToDate() {
+	s.expectGetRole(&types.Role{
+		Arn:                 aws.String(s.agent.GenerateRoleARN(testNamespace, testServiceAccountName)),
+		RoleName:            aws.String(s.agent.generateRoleName(testNamespace, testServiceAccountName)),
+		Path:                aws.String("/otterize/"),
+		PermissionsBoundary: &types.AttachedPermissionsBoundary{PermissionsBoundaryArn: aws.String(testPermissionBoundaryARN)},
+		Tags: []types.Tag{
+			{Key: aws.String("owner"), Value: aws.String(testServiceAccountName + "." + testNamespace)},
+			{Key: aws.String("team"), Value: aws.String("payments")},
+		},
+	})
+
+	_, err := s.agent.CreateOtterizeIAMRole(context.Background(), testNamespace, testServiceAccountName, false)
+	s.Require().NoError(err)
+}
+
+func (s *RoleSettingsSuite) TestExistingRoleARNKeepsPreviousPath() {
+	roleARN := "arn:aws:iam::123456789012:role/" + s.agent.generateRoleName(testNamespace, testServiceAccountName)
+	s.expectGetRole(&types.Role{
+		Arn:      aws.String(roleARN),
+		RoleName: aws.String(s.agent.generateRoleName(testNamespace, testServiceAccountName)),
+		Path:     aws.String("/"),
+	})
+
+	arn, err := s.agent.GetOtterizeRoleARN(context.Background(), testNamespace, testServiceAccountName)
+	s.Require().NoError(err)
+	s.Equal(roleARN, arn)
+}
+
+func (s *RoleSettingsSuite) TestMissingRoleARNGenerated() {
+	s.expectGetRole(nil)
+
+	arn, err := s.agent.GetOtterizeRoleARN(context.Background(), testNamespace, testServiceAccountName)
+	s.Require().NoError(err)
+	s.Equal("arn:aws:iam::123456789012:role/otterize/"+s.agent.generateRoleName(testNamespace, testServiceAccountName), arn)
+}
+
+func (s *RoleSettingsSuite) TestValidateRoleSettings() {
+	s.Require().NoError(s.agent.validateRoleSettings())
+
+	s.agent.RolePath = "otterize"
+	s.Require().ErrorContains(s.agent.validateRoleSettings(), "must begin and end with '/'")
+
+	s.agent.RolePath = ""
+	s.agent.CustomTags = map[string]string{"otterize/team": "payments"}
+	s.Require().ErrorContains(s.agent.validateRoleSettings(), "reserved prefix")
+}
+
+func TestRoleSettingsSuite(t *testing.T) {
+	suite.Run(t, new(RoleSettingsSuite))
+}
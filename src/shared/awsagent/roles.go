@@ -198,6 +198,15 @@ func (a *Agent) CreateOtterizeIAMRole(ctx context.Context, namespaceName string,
 				return nil, errors.Wrap(err)
 			}
 		}
+		err = a.reconcileRoleSettings(ctx, role, namespaceName, accountName)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
+
+		err = a.ensurePodIdentityAssociation(ctx, role, namespaceName, accountName)
+		if err != nil {
+			return nil, errors.Wrap(err)
+		}
 
 		return role, nil
 	}
@@ -224,12 +233,14 @@ func (a *Agent) CreateOtterizeIAMRole(ctx context.Context, namespaceName string,
 	if useSoftDeleteStrategy {
 		tags = append(tags, types.Tag{Key: aws.String(softDeletionStrategyTagKey), Value: aws.String(softDeletionStrategyTagValue)})
 	}
+	tags = append(tags, a.generateCustomTags(namespaceName, accountName)...)
 	createRoleInput := &iam.CreateRoleInput{
 		RoleName:                 aws.String(a.generateRoleName(namespaceName, accountName)),
+		Path:                     aws.String(a.getRolePath()),
 		AssumeRolePolicyDocument: aws.String(trustPolicy),
 		Tags:                     tags,
 		Description:              aws.String(iamRoleDescription),
-		PermissionsBoundary:      aws.String(fmt.Sprintf("arn:aws:iam::%s:policy/%s-limit-iam-permission-boundary", a.AccountID, a.ClusterName)),
+		PermissionsBoundary:      aws.String(a.getPermissionBoundaryARN()),
 	}
 	createRoleOutput, createRoleError := a.iamClient.CreateRole(ctx, createRoleInput)
 
@@ -238,10 +249,60 @@ func (a *Agent) CreateOtterizeIAMRole(ctx context.Context, namespaceName string,
 	}
 
 	logger.Debugf("created new role, arn: %s", *createRoleOutput.Role.Arn)
+
+	err = a.ensurePodIdentityAssociation(ctx, createRoleOutput.Role, namespaceName, accountName)
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+
 	return createRoleOutput.Role, nil
 
 }
 
+// ensurePodIdentityAssociation associates the role with the service account when roles trust EKS Pod Identity, as pods
+// only get credentials for the role through the association.
+func (a *Agent) ensurePodIdentityAssociation(ctx context.Context, role *types.Role, namespaceName string, accountName string) error {
+	if !a.PodIdentityEnabled {
+		return nil
+	}
+
+	return errors.Wrap(a.CreatePodIdentityAssociationForRole(ctx, *role, namespaceName, accountName))
+}
+
+// reconcileRoleSettings applies the configured permission boundary and custom tags to an existing role. The path of a
+// role cannot be changed once it is created, so a role under another path is kept there, and its ARN should be taken
+// from the role itself rather than generated.
+func (a *Agent) reconcileRoleSettings(ctx context.Context, role *types.Role, namespaceName string, accountName string) error {
+	logger := logrus.WithField("namespace", namespaceName).WithField("account", accountName)
+
+	permissionBoundaryARN := a.getPermissionBoundaryARN()
+	if role.PermissionsBoundary == nil || aws.ToString(role.PermissionsBoundary.PermissionsBoundaryArn) != permissionBoundaryARN {
+		logger.Debugf("setting role permission boundary to %s", permissionBoundaryARN)
+		_, err := a.iamClient.PutRolePermissionsBoundary(ctx, &iam.PutRolePermissionsBoundaryInput{
+			RoleName:            role.RoleName,
+			PermissionsBoundary: aws.String(permissionBoundaryARN),
+		})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	missingTags := getMissingTags(role.Tags, a.generateCustomTags(namespaceName, accountName))
+	if len(missingTags) > 0 {
+		logger.Debug("adding custom tags to role")
+		_, err := a.iamClient.TagRole(ctx, &iam.TagRoleInput{RoleName: role.RoleName, Tags: missingTags})
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
+	if role.Path != nil && *role.Path != a.getRolePath() {
+		logger.Infof("role was created under path %s rather than %s, keeping it under its current path", *role.Path, a.getRolePath())
+	}
+
+	return nil
+}
+
 func (a *Agent) DeleteOtterizeIAMRole(ctx context.Context, namespaceName, accountName string) error {
 	logger := logrus.WithField("namespace", namespaceName).WithField("account", accountName)
 	exists, role, err := a.GetOtterizeRole(ctx, namespaceName, accountName)
@@ -250,6 +311,15 @@ func (a *Agent) DeleteOtterizeIAMRole(ctx context.Context, namespaceName, accoun
 		return errors.Wrap(err)
 	}
 
+	if a.PodIdentityEnabled {
+		// Deleted even if the role no longer exists, so that the association does not outlive it. Associations with
+		// roles not created by Otterize are kept.
+		_, err = a.DeletePodIdentityAssociationForServiceAccount(ctx, namespaceName, accountName)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+	}
+
 	if !exists {
 		logger.Info("role does not exist, not doing anything")
 		return nil
@@ -309,7 +379,8 @@ func (a *Agent) SoftDeleteOtterizeIAMRole(ctx context.Context, role *types.Role)
 	return nil
 }
 
-// deleteAllRolePolicies deletes the inline role policy, if exists, in preparation to delete the role.
+// deleteAllRolePolicies deletes the inline role policy, if exists, and detaches the managed policies attached by
+// Otterize, in preparation to delete the role.
 func (a *Agent) deleteAllRolePolicies(ctx context.Context, role *types.Role) error {
 	listOutput, err := a.iamClient.ListAttachedRolePolicies(ctx, &iam.ListAttachedRolePoliciesInput{RoleName: role.RoleName})
 
@@ -317,6 +388,11 @@ func (a *Agent) deleteAllRolePolicies(ctx context.Context, role *types.Role) err
 		return errors.Errorf("failed to list role attached policies: %w", err)
 	}
 
+	err = a.detachOwnedManagedPolicies(ctx, role, listOutput.AttachedPolicies)
+	if err != nil {
+		return errors.Wrap(err)
+	}
+
 	for _, policy := range listOutput.AttachedPolicies {
 		if strings.HasPrefix(*policy.PolicyName, "otterize-") || strings.HasPrefix(*policy.PolicyName, "otr-") {
 			err = a.DeleteRolePolicy(ctx, *policy.PolicyName)
@@ -414,6 +490,10 @@ func (a *Agent) generateTrustPolicy(namespaceName, accountName string) (string,
 		return a.generateTrustPolicyForRolesAnywhere(namespaceName, accountName)
 	}
 
+	if a.PodIdentityEnabled {
+		return a.generateTrustPolicyForPodIdentity(namespaceName, accountName)
+	}
+
 	oidc := strings.TrimPrefix(a.OidcURL, "https://")
 
 	policy := PolicyDocument{
@@ -477,6 +557,39 @@ func (a *Agent) generateTrustPolicyForRolesAnywhere(namespaceName, accountName s
 	return string(serialized), errors.Wrap(err)
 }
 
+// generateTrustPolicyForPodIdentity trusts the EKS Pod Identity agent, limited by the session tags it sets to the
+// service account of this cluster the role is created for.
+func (a *Agent) generateTrustPolicyForPodIdentity(namespaceName, accountName string) (string, error) {
+	policy := PolicyDocument{
+		Version: iamAPIVersion,
+		Statement: []StatementEntry{
+			{
+				Effect: iamEffectAllow,
+				Action: []string{"sts:AssumeRole", "sts:TagSession"},
+				Principal: map[string]string{
+					"Service": podIdentityServicePrincipal,
+				},
+				Condition: map[string]any{
+					"StringEquals": map[string]string{
+						"aws:RequestTag/eks-cluster-name":           a.ClusterName,
+						"aws:RequestTag/kubernetes-namespace":       namespaceName,
+						"aws:RequestTag/kubernetes-service-account": accountName,
+					},
+				},
+			},
+		},
+	}
+
+	serialized, err := json.Marshal(policy)
+
+	if err != nil {
+		logrus.WithError(err).Error("failed to create trust policy")
+		return "", errors.Wrap(err)
+	}
+
+	return string(serialized), errors.Wrap(err)
+}
+
 func (a *Agent) generateRoleName(namespace string, accountName string) string {
 	fullName := fmt.Sprintf("otr-%s.%s@%s", namespace, accountName, a.ClusterName)
 	return agentutils.TruncateHashName(fullName, maxAWSNameLength)
@@ -488,7 +601,30 @@ func (a *Agent) generateRolesAnywhereProfileName(namespace string, accountName s
 	return agentutils.TruncateHashName(fullName, maxAWSNameLength)
 }
 
+// GenerateRoleARN returns the ARN of the role for the service account when it is created under the configured path.
+// Roles created before the path was changed keep their previous path, use GetOtterizeRoleARN to get their actual ARN.
 func (a *Agent) GenerateRoleARN(namespace string, accountName string) string {
 	roleName := a.generateRoleName(namespace, accountName)
-	return fmt.Sprintf("arn:aws:iam::%s:role/%s", a.AccountID, roleName)
+	return fmt.Sprintf("arn:aws:iam::%s:role%s%s", a.AccountID, a.getRolePath(), roleName)
+}
+
+// GetOtterizeRoleARN returns the ARN of the existing role for the service account, which may be under a path other than
+// the configured one, or the generated ARN if the role does not exist yet.
+func (a *Agent) GetOtterizeRoleARN(ctx context.Context, namespace string, accountName string) (string, error) {
+	exists, role, err := a.GetOtterizeRole(ctx, namespace, accountName)
+	if err != nil {
+		return "", errors.Wrap(err)
+	}
+
+	if !exists {
+		return a.GenerateRoleARN(namespace, accountName), nil
+	}
+
+	return aws.ToString(role.Arn), nil
+}
+
+// isOtterizeRoleARN returns whether the ARN is of the role for the service account under any path.
+func (a *Agent) isOtterizeRoleARN(roleARN string, namespace string, accountName string) bool {
+	roleName := a.generateRoleName(namespace, accountName)
+	return strings.HasPrefix(roleARN, fmt.Sprintf("arn:aws:iam::%s:role/", a.AccountID)) && strings.HasSuffix(roleARN, "/"+roleName)
 }
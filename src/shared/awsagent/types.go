@@ -13,6 +13,9 @@ const softDeletedTagKey = "otterize/softDeletedAt"
 const policyNameTagKey = "otterize/policyName"
 const policyNamespaceTagKey = "otterize/policyNamespace"
 const policyHashTagKey = "otterize/policyHash"
+const managedPolicyTagKeyPrefix = "otterize/managedPolicy/"
+
+const podIdentityServicePrincipal = "pods.eks.amazonaws.com"
 
 const iamRoleDescription = "This IAM role was created by the Otterize intents-operator's AWS integration. For more details, go to https://otterize.com"
 
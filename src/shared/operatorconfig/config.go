@@ -37,8 +37,32 @@ const (
 	EnableNetworkPolicyDefault                  = true
 	EnableIstioPolicyKey                        = "enable-istio-policy-creation" // Whether to enable Istio authorization policy creation
 	EnableIstioPolicyDefault                    = true
+	EnableIstioStrictMTLSKey                    = "enable-istio-strict-mtls" // Whether to enforce strict Istio mTLS on protected services
+	EnableIstioStrictMTLSDefault                = false
+	EnableIstioSidecarEgressKey                 = "enable-istio-sidecar-egress" // Whether to restrict the egress of Istio sidecars to the targets of their intents
+	EnableIstioSidecarEgressDefault             = false
+	EnableIstioAmbientKey                       = "enable-istio-ambient-support" // Whether to support workloads enrolled in the Istio ambient mesh
+	EnableIstioAmbientDefault                   = false
+	IstioEgressGatewayKey                       = "istio-egress-gateway" // The Istio egress gateway service, as namespace/name, to route internet traffic to domains through
+	IstioEgressGatewayDefault                   = ""
+	EnableIstioEgressGatewayPolicyKey           = "enable-istio-egress-gateway-policy" // Whether to create authorization policies on the Istio egress gateway, which deny all of its traffic that no intent allows
+	EnableIstioEgressGatewayPolicyDefault       = false
+	EnableLinkerdPolicyKey                      = "enable-linkerd-policy-creation" // Whether to enable Linkerd authorization policy creation
+	EnableLinkerdPolicyDefault                  = false
 	EnableKafkaACLKey                           = "enable-kafka-acl-creation" // Whether to disable Intents Kafka ACL creation
 	EnableKafkaACLDefault                       = true
+	KafkaACLResyncIntervalKey                   = "kafka-acl-resync-interval" // How often to compare the ACLs on Kafka servers with the intents, 0 disables the resync
+	KafkaACLResyncIntervalDefault               = 10 * time.Minute
+	KafkaACLDriftRepairKey                      = "kafka-acl-drift-repair" // Whether to repair Kafka ACL drift found by the resync, instead of only reporting it
+	KafkaACLDriftRepairDefault                  = false
+	KafkaAdminIdleTimeoutKey                    = "kafka-admin-idle-timeout" // How long connections to Kafka servers are kept open while unused
+	KafkaAdminIdleTimeoutDefault                = 5 * time.Minute
+	KafkaAdminMaxConcurrentRequestsKey          = "kafka-admin-max-concurrent-requests" // The maximum number of reconciles using the connection to a Kafka server concurrently
+	KafkaAdminMaxConcurrentRequestsDefault      = 10
+	ImportKafkaACLsFromKey                      = "import-kafka-acls-from" // A KafkaServerConfig, as namespace/name, to import the ACLs of as ClientIntents, instead of running the operator
+	ImportKafkaACLsFromDefault                  = ""
+	ImportKafkaACLsOutputDirKey                 = "import-kafka-acls-output-dir" // The directory to write the ClientIntents imported from Kafka ACLs to
+	ImportKafkaACLsOutputDirDefault             = "."
 	IntentsOperatorPodNameKey                   = "pod-name"
 	IntentsOperatorPodNamespaceKey              = "pod-namespace"
 	EnvPrefix                                   = "OTTERIZE"
@@ -54,6 +78,12 @@ const (
 	EnableAWSPolicyDefault                      = false
 	EnableAWSRolesAnywhereKey                   = "enable-aws-iam-rolesanywhere"
 	EnableAWSRolesAnywhereDefault               = false
+	EnableAWSPodIdentityKey                     = "enable-aws-pod-identity" // Trust EKS Pod Identity instead of the cluster's OIDC provider in created IAM roles
+	EnableAWSPodIdentityDefault                 = false
+	AWSRolePermissionBoundaryARNKey             = "aws-role-permission-boundary-arn" // The permission boundary of created IAM roles, instead of the <cluster name>-limit-iam-permission-boundary policy
+	AWSRolePathKey                              = "aws-role-path"                    // The IAM path of created roles
+	AWSRolePathDefault                          = "/"
+	AWSResourceTagsKey                          = "aws-resource-tags" // Tags added to created IAM roles and policies, as a JSON object; values may reference $(NAMESPACE) and $(SERVICE_ACCOUNT)
 	EnableGCPPolicyKey                          = "enable-gcp-iam-policy"
 	EnableGCPPolicyDefault                      = false
 	EnableAzurePolicyKey                        = "enable-azure-iam-policy"
@@ -85,11 +115,26 @@ func init() {
 	viper.SetDefault(AllowExternalTrafficKey, AllowExternalTrafficDefault)
 	viper.SetDefault(EnableNetworkPolicyKey, EnableNetworkPolicyDefault)
 	viper.SetDefault(EnableKafkaACLKey, EnableKafkaACLDefault)
+	viper.SetDefault(KafkaACLResyncIntervalKey, KafkaACLResyncIntervalDefault)
+	viper.SetDefault(KafkaACLDriftRepairKey, KafkaACLDriftRepairDefault)
+	viper.SetDefault(KafkaAdminIdleTimeoutKey, KafkaAdminIdleTimeoutDefault)
+	viper.SetDefault(KafkaAdminMaxConcurrentRequestsKey, KafkaAdminMaxConcurrentRequestsDefault)
+	viper.SetDefault(ImportKafkaACLsFromKey, ImportKafkaACLsFromDefault)
+	viper.SetDefault(ImportKafkaACLsOutputDirKey, ImportKafkaACLsOutputDirDefault)
 	viper.SetDefault(EnableIstioPolicyKey, EnableIstioPolicyDefault)
+	viper.SetDefault(EnableIstioStrictMTLSKey, EnableIstioStrictMTLSDefault)
+	viper.SetDefault(EnableIstioSidecarEgressKey, EnableIstioSidecarEgressDefault)
+	viper.SetDefault(EnableIstioAmbientKey, EnableIstioAmbientDefault)
+	viper.SetDefault(IstioEgressGatewayKey, IstioEgressGatewayDefault)
+	viper.SetDefault(EnableIstioEgressGatewayPolicyKey, EnableIstioEgressGatewayPolicyDefault)
+	viper.SetDefault(EnableLinkerdPolicyKey, EnableLinkerdPolicyDefault)
 	viper.SetDefault(DisableWebhookServerKey, DisableWebhookServerDefault)
 	viper.SetDefault(EnableEgressNetworkPolicyReconcilersKey, EnableEgressNetworkPolicyReconcilersDefault)
 	viper.SetDefault(EnableAWSPolicyKey, EnableAWSPolicyDefault)
 	viper.SetDefault(EnableAWSRolesAnywhereKey, EnableAWSRolesAnywhereDefault)
+	viper.SetDefault(EnableAWSPodIdentityKey, EnableAWSPodIdentityDefault)
+	viper.SetDefault(AWSRolePermissionBoundaryARNKey, "")
+	viper.SetDefault(AWSRolePathKey, AWSRolePathDefault)
 	viper.SetDefault(EnableGCPPolicyKey, EnableGCPPolicyDefault)
 	viper.SetDefault(EnableAzurePolicyKey, EnableAzurePolicyDefault)
 	viper.SetDefault(TelemetryErrorsAPIKeyKey, TelemetryErrorsAPIKeyDefault)
@@ -143,12 +188,24 @@ func InitCLIFlags() {
 	pflag.Bool(EnforcementDefaultStateKey, EnforcementDefaultStateDefault, "Sets the default state of the enforcement. If true, always enforces. If false, can be overridden using ProtectedService.")
 	pflag.Bool(EnableNetworkPolicyKey, EnableNetworkPolicyDefault, "Whether to enable Intents network policy creation")
 	pflag.Bool(EnableKafkaACLKey, EnableKafkaACLDefault, "Whether to disable Intents Kafka ACL creation")
+	pflag.Duration(KafkaACLResyncIntervalKey, KafkaACLResyncIntervalDefault, "How often to compare the ACLs on Kafka servers with the intents, 0 disables the resync")
+	pflag.Bool(KafkaACLDriftRepairKey, KafkaACLDriftRepairDefault, "Whether to repair Kafka ACL drift found by the resync, instead of only reporting it")
+	pflag.Duration(KafkaAdminIdleTimeoutKey, KafkaAdminIdleTimeoutDefault, "How long connections to Kafka servers are kept open while unused")
+	pflag.Int(KafkaAdminMaxConcurrentRequestsKey, KafkaAdminMaxConcurrentRequestsDefault, "The maximum number of reconciles using the connection to a Kafka server concurrently")
+	pflag.String(ImportKafkaACLsFromKey, ImportKafkaACLsFromDefault, "A KafkaServerConfig, as namespace/name, to import the ACLs of as ClientIntents, instead of running the operator")
+	pflag.String(ImportKafkaACLsOutputDirKey, ImportKafkaACLsOutputDirDefault, "The directory to write the ClientIntents imported from Kafka ACLs to")
 	pflag.String(MetricsAddrKey, MetricsAddrDefault, "The address the metric endpoint binds to.")
 	pflag.String(ProbeAddrKey, ProbeAddrDefault, "The address the probe endpoint binds to.")
 	pflag.Bool(EnableLeaderElectionKey, EnableLeaderElectionDefault, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	pflag.StringSlice(WatchedNamespacesKey, nil, "Namespaces that will be watched by the operator. Specify multiple values by specifying multiple times or separate with commas.")
 	pflag.StringSlice(ActiveEnforcementNamespacesKey, nil, "While using the shadow enforcement mode, namespaces in this list will be treated as if the enforcement were active.")
 	pflag.Bool(EnableIstioPolicyKey, EnableIstioPolicyDefault, "Whether to enable Istio authorization policy creation")
+	pflag.Bool(EnableIstioStrictMTLSKey, EnableIstioStrictMTLSDefault, "Whether to enforce strict Istio mTLS on protected services, blocking their plaintext and out-of-mesh clients")
+	pflag.Bool(EnableIstioSidecarEgressKey, EnableIstioSidecarEgressDefault, "Whether to restrict the egress of Istio sidecars to the targets of their intents")
+	pflag.Bool(EnableIstioAmbientKey, EnableIstioAmbientDefault, "Whether to support workloads enrolled in the Istio ambient mesh")
+	pflag.String(IstioEgressGatewayKey, IstioEgressGatewayDefault, "The Istio egress gateway service, as namespace/name, to route internet traffic to domains through")
+	pflag.Bool(EnableIstioEgressGatewayPolicyKey, EnableIstioEgressGatewayPolicyDefault, "Whether to create authorization policies on the Istio egress gateway, allowing each client only the domains of its intents. Once enabled, the gateway denies all traffic that no intent allows, including traffic of workloads without intents, so it should only be enabled for a gateway dedicated to clients with intents")
+	pflag.Bool(EnableLinkerdPolicyKey, EnableLinkerdPolicyDefault, "Whether to enable Linkerd authorization policy creation")
 	pflag.Bool(telemetriesconfig.TelemetryEnabledKey, telemetriesconfig.TelemetryEnabledDefault, "When set to false, all telemetries are disabled")
 	pflag.Bool(telemetriesconfig.TelemetryUsageEnabledKey, telemetriesconfig.TelemetryUsageEnabledDefault, "Whether usage telemetry should be enabled")
 	pflag.Bool(telemetriesconfig.TelemetryErrorsEnabledKey, telemetriesconfig.TelemetryErrorEnabledDefault, "Whether errors telemetry should be enabled")